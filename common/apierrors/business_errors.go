@@ -8,4 +8,5 @@ const (
 	ErrCodeInvalidProductData = "INVALID_PRODUCT_DATA" // When product information is invalid
 	ErrCodeOrderLimitExceeded = "ORDER_LIMIT_EXCEEDED" // When purchase exceeds allowed quantity
 	ErrCodePriceMismatch      = "PRICE_MISMATCH"       // When expected and actual prices don't match
+	ErrCodeConflict           = "CONFLICT"             // When an update's expected state no longer matches
 )
@@ -5,6 +5,9 @@ import (
 	"time"
 )
 
+// ContextSimulated is the ContextData key set on errors injected by debug simulation.
+const ContextSimulated = "simulated"
+
 // AppError defines a standard application error.
 type AppError struct {
 	Code        string                 // Application-specific error code
@@ -13,10 +16,17 @@ type AppError struct {
 	Timestamp   time.Time              // When error occurred
 	ContextData map[string]interface{} // Additional context
 	Category    ErrorCategory          // Business or Application
+	Breadcrumb  string                 // Layer/operation added by Wrap (optional)
+	// Client-facing details returned in the error response, unlike ContextData
+	Details map[string]interface{}
 }
 
 // Error implements the error interface.
 func (e *AppError) Error() string {
+	if e.Breadcrumb != "" && e.Err != nil {
+		// Wrapped errors read as a path: "service.op: repo.op: AppError(...)"
+		return e.Breadcrumb + ": " + e.Err.Error()
+	}
 	if e.Err != nil {
 		// Include cause for better internal logging
 		return fmt.Sprintf("AppError(Code=%s, Category=%s, Message=%s, Cause=%v)",
@@ -47,6 +57,21 @@ func (e *AppError) WithContext(key string, value interface{}) *AppError {
 	return e
 }
 
+// WithDetail adds a detail returned to the client alongside the error message
+func (e *AppError) WithDetail(key string, value interface{}) *AppError {
+	if e.Details == nil {
+		e.Details = make(map[string]interface{})
+	}
+	e.Details[key] = value
+	return e
+}
+
+// Simulated reports whether the error was injected by debug simulation.
+func (e *AppError) Simulated() bool {
+	simulated, _ := e.ContextData[ContextSimulated].(bool)
+	return simulated
+}
+
 // NewAppError creates a new AppError with defaults
 func NewAppError(code, message string, cause error) *AppError {
 	// Determine category based on code prefix
@@ -57,6 +82,7 @@ func NewAppError(code, message string, cause error) *AppError {
 		ErrCodeInvalidProductData,
 		ErrCodeOrderLimitExceeded,
 		ErrCodePriceMismatch,
+		ErrCodeConflict,
 	} {
 		if code == prefix {
 			category = CategoryBusiness
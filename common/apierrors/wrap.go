@@ -0,0 +1,47 @@
+package apierrors
+
+import "errors"
+
+// Wrap adds a breadcrumb describing the current layer to err while keeping its code,
+// category, user-facing message and context. Errors that are not AppErrors become
+// internal processing errors. Wrap returns nil for a nil err.
+func Wrap(err error, message string) *AppError {
+	if err == nil {
+		return nil
+	}
+
+	var inner *AppError
+	if !errors.As(err, &inner) {
+		return NewApplicationError(ErrCodeInternalProcessing, message, err)
+	}
+
+	return &AppError{
+		Code:        inner.Code,
+		Message:     inner.Message,
+		Err:         err,
+		Timestamp:   inner.Timestamp,
+		ContextData: inner.ContextData,
+		Category:    inner.Category,
+		Breadcrumb:  message,
+		Details:     inner.Details,
+	}
+}
+
+// WithOp records the operation (e.g. "product_service.buy_product") an error passed through.
+func WithOp(err error, operation string) *AppError {
+	return Wrap(err, operation)
+}
+
+// Breadcrumbs returns the breadcrumbs along the error chain, outermost first
+// (e.g. handler, service, repository).
+func (e *AppError) Breadcrumbs() []string {
+	var crumbs []string
+	var err error = e
+	for err != nil {
+		if appErr, ok := err.(*AppError); ok && appErr.Breadcrumb != "" {
+			crumbs = append(crumbs, appErr.Breadcrumb)
+		}
+		err = errors.Unwrap(err)
+	}
+	return crumbs
+}
@@ -0,0 +1,70 @@
+package apierrors
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWrapPreservesCodeAndCategory(t *testing.T) {
+	disk := errors.New("disk full")
+	dbFailure := NewApplicationError(ErrCodeDatabaseAccess, "Failed to write product data", disk).
+		WithContext("product_name", "Coffee Mug")
+	outOfStock := NewBusinessError(ErrCodeInsufficientStock, "only 2 left", nil)
+
+	tests := []struct {
+		name            string
+		err             *AppError
+		wantCode        string
+		wantCategory    ErrorCategory
+		wantMessage     string
+		wantBreadcrumbs []string
+		wantCause       error
+	}{
+		{name: "one layer", err: WithOp(dbFailure, "product_service.update_stock"),
+			wantCode: ErrCodeDatabaseAccess, wantCategory: CategoryApplication, wantMessage: "Failed to write product data",
+			wantBreadcrumbs: []string{"product_service.update_stock"}, wantCause: disk},
+		{name: "handler, service and repository",
+			err:      Wrap(WithOp(WithOp(dbFailure, "product_repository.update_stock"), "product_service.update_stock"), "product_handler.update_stock"),
+			wantCode: ErrCodeDatabaseAccess, wantCategory: CategoryApplication, wantMessage: "Failed to write product data",
+			wantBreadcrumbs: []string{"product_handler.update_stock", "product_service.update_stock", "product_repository.update_stock"}, wantCause: disk},
+		{name: "business error", err: WithOp(outOfStock, "product_service.buy_product"),
+			wantCode: ErrCodeInsufficientStock, wantCategory: CategoryBusiness, wantMessage: "only 2 left",
+			wantBreadcrumbs: []string{"product_service.buy_product"}, wantCause: outOfStock},
+		{name: "plain error becomes internal", err: Wrap(disk, "product_service.export"),
+			wantCode: ErrCodeInternalProcessing, wantCategory: CategoryApplication, wantMessage: "product_service.export",
+			wantCause: disk},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Code != tt.wantCode || tt.err.Category != tt.wantCategory || tt.err.Message != tt.wantMessage {
+				t.Errorf("got code %s, category %s, message %q; want %s, %s, %q",
+					tt.err.Code, tt.err.Category, tt.err.Message, tt.wantCode, tt.wantCategory, tt.wantMessage)
+			}
+			if got := tt.err.Breadcrumbs(); !reflect.DeepEqual(got, tt.wantBreadcrumbs) {
+				t.Errorf("Breadcrumbs() = %q, want %q", got, tt.wantBreadcrumbs)
+			}
+			if !errors.Is(tt.err, tt.wantCause) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", tt.err, tt.wantCause)
+			}
+		})
+	}
+
+	if got := WithOp(dbFailure, "product_service.update_stock").ContextData["product_name"]; got != "Coffee Mug" {
+		t.Errorf("wrapped context product_name = %v, want Coffee Mug", got)
+	}
+	if got := Wrap(nil, "product_service.update_stock"); got != nil {
+		t.Errorf("Wrap(nil) = %v, want nil", got)
+	}
+}
+
+func TestWrappedErrorReadsAsAPath(t *testing.T) {
+	inner := NewBusinessError(ErrCodeProductNotFound, "no such product", nil)
+	err := WithOp(WithOp(inner, "product_repository.get_by_name"), "product_service.get_by_name")
+
+	want := "product_service.get_by_name: product_repository.get_by_name: " + inner.Error()
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package apirequests
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Normalize applies the `normalize` struct tag rules to the string and *string
+// fields of the struct req points to, and returns the JSON names of the fields it
+// changed. Rules are comma-separated: "trim" strips surrounding whitespace and
+// "lower" case-folds the value.
+func Normalize(req interface{}) []string {
+	v := reflect.ValueOf(req)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	var changed []string
+	for i := 0; i < t.NumField(); i++ {
+		rules := t.Field(i).Tag.Get("normalize")
+		if rules == "" {
+			continue
+		}
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+		if field.Kind() != reflect.String || !field.CanSet() {
+			continue
+		}
+
+		original := field.String()
+		normalized := applyRules(original, rules)
+		if normalized != original {
+			field.SetString(normalized)
+			changed = append(changed, jsonName(t.Field(i)))
+		}
+	}
+	return changed
+}
+
+func applyRules(value, rules string) string {
+	for _, rule := range strings.Split(rules, ",") {
+		switch strings.TrimSpace(rule) {
+		case "trim":
+			value = strings.TrimSpace(value)
+		case "lower":
+			value = strings.ToLower(value)
+		}
+	}
+	return value
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
@@ -0,0 +1,53 @@
+package apirequests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestNormalize(t *testing.T) {
+	type taggedRequest struct {
+		SKU      string  `json:"sku" normalize:"trim,lower"`
+		Note     string  `json:"note"`
+		Label    *string `json:"label,omitempty" normalize:"lower"`
+		Untagged string  `normalize:"trim"`
+	}
+
+	tests := []struct {
+		name        string
+		req         interface{}
+		want        interface{}
+		wantChanged []string
+	}{
+		{name: "padded name trimmed", req: &ProductBuyRequest{Name: "  Coffee Mug \t", Quantity: 2},
+			want: &ProductBuyRequest{Name: "Coffee Mug", Quantity: 2}, wantChanged: []string{"name"}},
+		{name: "clean name untouched", req: &GetByNameRequest{Name: "Coffee Mug"},
+			want: &GetByNameRequest{Name: "Coffee Mug"}},
+		{name: "case is kept for product names", req: &UpdateStockRequest{Name: " coffee MUG", Stock: 3},
+			want: &UpdateStockRequest{Name: "coffee MUG", Stock: 3}, wantChanged: []string{"name"}},
+		{name: "optional field trimmed", req: &UpdateProductRequest{Category: ptr(" Kitchenware ")},
+			want: &UpdateProductRequest{Category: ptr("Kitchenware")}, wantChanged: []string{"category"}},
+		{name: "absent optional field skipped", req: &UpdateProductRequest{Stock: ptr(4)},
+			want: &UpdateProductRequest{Stock: ptr(4)}},
+		{name: "rules combine and untagged fields are left alone",
+			req:         &taggedRequest{SKU: " AB-12 ", Note: " keep ", Label: ptr("Sale"), Untagged: " x "},
+			want:        &taggedRequest{SKU: "ab-12", Note: " keep ", Label: ptr("sale"), Untagged: "x"},
+			wantChanged: []string{"sku", "label", "Untagged"}},
+		{name: "non-pointer ignored", req: GetByNameRequest{Name: " Coffee Mug "},
+			want: GetByNameRequest{Name: " Coffee Mug "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := Normalize(tt.req)
+			if !reflect.DeepEqual(tt.req, tt.want) {
+				t.Errorf("normalized request = %+v, want %+v", tt.req, tt.want)
+			}
+			if !reflect.DeepEqual(changed, tt.wantChanged) {
+				t.Errorf("changed fields = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+}
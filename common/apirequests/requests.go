@@ -2,21 +2,33 @@ package apirequests
 
 // Requires: go get github.com/go-playground/validator/v10 📦
 
+// String fields tagged `normalize` are cleaned up by Normalize before validation.
+
 // Used for GetProductByName
 type GetByNameRequest struct {
-	Name string `json:"name" validate:"required"` // Mark name as required
+	Name string `json:"name" validate:"required" normalize:"trim"` // Mark name as required
 }
 
 // Used for UpdateProductStock
 type UpdateStockRequest struct {
-	Name  string `json:"name" validate:"required"`
+	Name  string `json:"name" validate:"required" normalize:"trim"`
 	Stock int    `json:"stock" validate:"required,gte=0"` // Stock must be provided and >= 0
+	// Optional compare-and-set guard: the update is rejected if current stock differs
+	ExpectedStock *int `json:"expectedStock,omitempty" validate:"omitempty,gte=0"`
 }
 
 // Used for BuyProduct
 type ProductBuyRequest struct {
-	Name     string `json:"name" validate:"required"`
+	Name     string `json:"name" validate:"required" normalize:"trim"`
 	Quantity int    `json:"quantity" validate:"required,gt=0"` // Quantity must be provided and > 0
 }
 
+// Used for UpdateProduct; only fields present in the body are changed
+type UpdateProductRequest struct {
+	Price       *float64 `json:"price,omitempty" validate:"omitempty,gt=0"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Category    *string  `json:"category,omitempty" validate:"omitempty,min=1,max=100" normalize:"trim"`
+	Stock       *int     `json:"stock,omitempty" validate:"omitempty,gte=0"`
+}
+
 // Note: GetProductsByCategory uses query param, validation handled separately (in handler)
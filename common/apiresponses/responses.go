@@ -19,6 +19,8 @@ type ErrorDetail struct {
 	Code      string `json:"code"`    // Application-specific error code
 	Message   string `json:"message"` // User-friendly message
 	Timestamp string `json:"timestamp,omitempty"`
+	// Extra machine-readable information, e.g. suggestions for a missing product
+	Details map[string]interface{} `json:"details,omitempty"`
 }
 
 // Helper to create a success response
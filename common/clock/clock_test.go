@@ -0,0 +1,112 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+func TestFakeAfterFiresOnceItsDeadlineIsReached(t *testing.T) {
+	tests := []struct {
+		name     string
+		wait     time.Duration
+		advances []time.Duration
+		wantFire bool
+		wantAt   time.Time
+	}{
+		{name: "not yet due", wait: time.Second, advances: []time.Duration{999 * time.Millisecond}},
+		{name: "exactly due", wait: time.Second, advances: []time.Duration{time.Second}, wantFire: true, wantAt: start.Add(time.Second)},
+		{name: "due over several advances", wait: time.Second, advances: []time.Duration{600 * time.Millisecond, 600 * time.Millisecond}, wantFire: true, wantAt: start.Add(1200 * time.Millisecond)},
+		{name: "zero duration fires immediately", wait: 0, wantFire: true, wantAt: start},
+		{name: "negative duration fires immediately", wait: -time.Second, wantFire: true, wantAt: start},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFake(start)
+			ch := fake.After(tt.wait)
+			for _, d := range tt.advances {
+				fake.Advance(d)
+			}
+
+			select {
+			case at := <-ch:
+				if !tt.wantFire {
+					t.Fatalf("After(%s) fired at %s, want it still pending", tt.wait, at)
+				}
+				if !at.Equal(tt.wantAt) {
+					t.Errorf("After(%s) fired with %s, want %s", tt.wait, at, tt.wantAt)
+				}
+			default:
+				if tt.wantFire {
+					t.Fatalf("After(%s) has not fired, want it fired", tt.wait)
+				}
+			}
+			wantWaiters := 1
+			if tt.wantFire {
+				wantWaiters = 0
+			}
+			if got := fake.Waiters(); got != wantWaiters {
+				t.Errorf("Waiters() = %d, want %d", got, wantWaiters)
+			}
+		})
+	}
+}
+
+func TestFakeAdvanceFiresWaitersEarliestFirst(t *testing.T) {
+	fake := NewFake(start)
+	late := fake.After(3 * time.Second)
+	early := fake.After(time.Second)
+	pending := fake.After(time.Minute)
+
+	fake.Advance(5 * time.Second)
+
+	for name, ch := range map[string]<-chan time.Time{"1s": early, "3s": late} {
+		select {
+		case <-ch:
+		default:
+			t.Errorf("%s waiter has not fired after advancing 5s", name)
+		}
+	}
+	select {
+	case <-pending:
+		t.Error("1m waiter fired after advancing 5s")
+	default:
+	}
+	if got := fake.Waiters(); got != 1 {
+		t.Errorf("Waiters() = %d, want 1", got)
+	}
+	if got := Since(fake, start); got != 5*time.Second {
+		t.Errorf("Since(start) = %s, want 5s", got)
+	}
+}
+
+func TestFakeSleepBlocksUntilAdvanced(t *testing.T) {
+	fake := NewFake(start)
+	done := make(chan struct{})
+	go func() {
+		fake.Sleep(time.Hour)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for fake.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Sleep never started waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-done:
+		t.Fatal("Sleep returned before the clock advanced")
+	default:
+	}
+
+	fake.Advance(time.Hour)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Sleep did not return after the clock advanced past it")
+	}
+}
@@ -1,5 +1,15 @@
 package config
 
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
 // Config defines the application configuration structure using environment variables.
 type Config struct {
 	// Core App Settings
@@ -7,8 +17,57 @@ type Config struct {
 	PRODUCT_SERVICE_PORT      string `env:"PRODUCT_SERVICE_PORT,required" envDefault:"8082"`
 	MASTER_STORE_SERVICE_PORT string `env:"MASTER_STORE_SERVICE_PORT,required" envDefault:"8083"`
 	LOG_LEVEL                 string `env:"LOG_LEVEL" envDefault:"info"`
+	// Per-scope minimum exported log level, e.g. "file_database=warn,product_service=info".
+	LOG_SCOPE_LEVELS string `env:"LOG_SCOPE_LEVELS" envDefault:"file_database=warn"`
+	// Route output of the standard log package (telemetry setup diagnostics) through the logger.
+	LOG_CAPTURE_STDLIB bool `env:"LOG_CAPTURE_STDLIB" envDefault:"true"`
+	// Shutdown budgets per signal: SIGTERM comes from orchestrators with a grace period,
+	// SIGINT is usually a developer's Ctrl-C and should exit quickly.
+	ShutdownSigtermTimeout time.Duration `env:"SHUTDOWN_SIGTERM_TIMEOUT" envDefault:"30s"`
+	ShutdownSigintTimeout  time.Duration `env:"SHUTDOWN_SIGINT_TIMEOUT" envDefault:"5s"`
+	// Re-read the reloadable config (log level, trace sample ratio, simulation settings) on SIGHUP.
+	CONFIG_RELOAD_ON_SIGHUP bool `env:"CONFIG_RELOAD_ON_SIGHUP" envDefault:"true"`
 	// Default path set for container environment; override for local dev using .env or env var.
 	PRODUCT_DATA_FILE_PATH string `env:"PRODUCT_DATA_FILE_PATH,required" envDefault:"/product-service/data.json"`
+	// Write the data file indented for readability; disable for faster, smaller writes.
+	DB_PRETTY_JSON bool `env:"DB_PRETTY_JSON" envDefault:"true"`
+	// Catalog version recorded on database spans to tell data files apart across pods.
+	DB_CATALOG_VERSION string `env:"DB_CATALOG_VERSION" envDefault:"unversioned"`
+	// Data file reads/writes slower than this get a db.slow span event and a warning; 0 disables.
+	DB_SLOW_THRESHOLD_MS int `env:"DB_SLOW_THRESHOLD_MS" envDefault:"200"`
+	// Hard limit on a single data file read; the request fails with REQUEST_TIMEOUT instead
+	// of hanging on a stuck filesystem. Writes always run to completion. 0 disables.
+	DB_OP_TIMEOUT time.Duration `env:"DB_OP_TIMEOUT" envDefault:"5s"`
+	// When the data file is read-only, serve it from memory instead of failing every
+	// write. Changes are lost on restart.
+	DB_READONLY_FALLBACK bool `env:"DB_READONLY_FALLBACK" envDefault:"false"`
+	// Number of workers used to aggregate large catalogs; 1 keeps aggregation serial.
+	AGGREGATION_WORKERS int `env:"AGGREGATION_WORKERS" envDefault:"1"`
+	// Largest quantity accepted in a single purchase.
+	MAX_PURCHASE_QUANTITY int `env:"MAX_PURCHASE_QUANTITY" envDefault:"1000"`
+	// Times a purchase re-reads the product and retries after a concurrent purchase
+	// changed its stock; once exhausted the purchase fails with CONFLICT (409).
+	BUY_CONFLICT_RETRIES int `env:"BUY_CONFLICT_RETRIES" envDefault:"3"`
+	// Categories sold without stock checks or decrements, e.g. "Digital,Gift Cards".
+	UNLIMITED_STOCK_CATEGORIES string `env:"UNLIMITED_STOCK_CATEGORIES"`
+	// Category reported for products whose category is blank in the data file.
+	DEFAULT_CATEGORY string `env:"DEFAULT_CATEGORY" envDefault:"uncategorized"`
+	// Comma-separated upper bounds of the price bands reported by the price band gauge;
+	// "10,50" yields 0-10, 10-50 and 50+.
+	PRICE_BAND_BOUNDS string `env:"PRICE_BAND_BOUNDS" envDefault:"10,50"`
+	// Products with stock below this are reported as low on stock.
+	LOW_STOCK_THRESHOLD int `env:"LOW_STOCK_THRESHOLD" envDefault:"10"`
+	// Per-category overrides of LOW_STOCK_THRESHOLD, e.g. "Perishables=20,Electronics=5".
+	CATEGORY_STOCK_THRESHOLDS string `env:"CATEGORY_STOCK_THRESHOLDS"`
+	// Catalog responses with more products than this are streamed instead of buffered; 0 disables.
+	RESPONSE_STREAM_THRESHOLD int `env:"RESPONSE_STREAM_THRESHOLD" envDefault:"1000"`
+	// Operations whose JSON bodies reject unknown fields, e.g. "buy_product,update_product_stock";
+	// "*" applies to every operation, empty keeps unknown fields ignored.
+	STRICT_REQUEST_DECODING string `env:"STRICT_REQUEST_DECODING"`
+	// Number of recent readiness results kept for /debug/health-history.
+	HEALTH_HISTORY_SIZE int `env:"HEALTH_HISTORY_SIZE" envDefault:"50"`
+	// Locales error messages may be served in, picked from Accept-Language; English is always the fallback.
+	ERROR_MESSAGE_LOCALES string `env:"ERROR_MESSAGE_LOCALES" envDefault:"en,es,de,fr"`
 	// URL for the product service API
 	PRODUCT_SERVICE_URL string `env:"PRODUCT_SERVICE_URL" envDefault:"http://product-service:8082"`
 
@@ -17,15 +76,194 @@ type Config struct {
 	OTEL_ENDPOINT   string `env:"OTEL_ENDPOINT,required" envDefault:"localhost:4317"`
 	SERVICE_NAME    string `env:"SERVICE_NAME" envDefault:"product-service"`
 	SERVICE_VERSION string `env:"SERVICE_VERSION" envDefault:"unknown"`
+	// Optional per-signal collector endpoints; empty falls back to OTEL_ENDPOINT.
+	OTEL_EXPORTER_OTLP_TRACES_ENDPOINT  string `env:"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"`
+	OTEL_EXPORTER_OTLP_METRICS_ENDPOINT string `env:"OTEL_EXPORTER_OTLP_METRICS_ENDPOINT"`
+	OTEL_EXPORTER_OTLP_LOGS_ENDPOINT    string `env:"OTEL_EXPORTER_OTLP_LOGS_ENDPOINT"`
+	// Recorded as deployment.environment on the OTel resource (e.g. staging, production).
+	DEPLOYMENT_ENV string `env:"DEPLOYMENT_ENV" envDefault:"development"`
+	// Comma-separated key=value pairs sent with every OTLP export.
+	// Values may carry API keys and are never logged; only keys are.
+	OTEL_EXPORTER_OTLP_HEADERS string `env:"OTEL_EXPORTER_OTLP_HEADERS" redact:"true"`
+	// Comma-separated list of context propagators: tracecontext, baggage, b3, b3multi.
+	OTEL_PROPAGATORS string `env:"OTEL_PROPAGATORS" envDefault:"tracecontext,baggage"`
+	// Response header carrying the request's trace ID for support correlation; empty disables.
+	TRACE_RESPONSE_HEADER string `env:"TRACE_RESPONSE_HEADER" envDefault:"X-Trace-Id"`
+	// Distinct values kept per high-cardinality metric attribute (e.g. product.name)
+	// before further values collapse into "other"; 0 disables the guard.
+	OTEL_METRIC_CARDINALITY_LIMIT int `env:"OTEL_METRIC_CARDINALITY_LIMIT" envDefault:"100"`
+	// Fraction of new traces to sample (0.0-1.0); child spans follow their parent's decision.
+	OTEL_TRACE_SAMPLE_RATIO float64 `env:"OTEL_TRACE_SAMPLE_RATIO" envDefault:"1.0"`
+	// After startup, sample everything and decay linearly to the ratio over this window; 0 disables.
+	OTEL_TRACE_SAMPLE_WARMUP time.Duration `env:"OTEL_TRACE_SAMPLE_WARMUP" envDefault:"0s"`
+	// Export every span with an error status but only this fraction of successful traces.
+	// Applies after the head sampler, so it only sees traces that were sampled.
+	OTEL_TAIL_ERROR_SAMPLING bool    `env:"OTEL_TAIL_ERROR_SAMPLING" envDefault:"false"`
+	OTEL_TAIL_SUCCESS_RATIO  float64 `env:"OTEL_TAIL_SUCCESS_RATIO" envDefault:"0.1"`
+	// Upper bound on the estimated span payload per export call; larger batches are split.
+	// Keep below the collector's gRPC max receive size (4 MiB by default). 0 disables chunking.
+	OTEL_MAX_EXPORT_BATCH_BYTES int `env:"OTEL_MAX_EXPORT_BATCH_BYTES" envDefault:"3145728"`
+	// Extra attempts for a chunk of a split batch that failed to export, so one transient
+	// failure does not drop that chunk while the others succeed.
+	OTEL_EXPORT_CHUNK_RETRIES int `env:"OTEL_EXPORT_CHUNK_RETRIES" envDefault:"2"`
+	// Separate budgets so a slow collector connection cannot starve exports:
+	// dial bounds each gRPC connection attempt, export bounds each OTLP export call,
+	// reader bounds a metric collect-and-export cycle.
+	OTEL_DIAL_TIMEOUT   time.Duration `env:"OTEL_DIAL_TIMEOUT" envDefault:"5s"`
+	OTEL_EXPORT_TIMEOUT time.Duration `env:"OTEL_EXPORT_TIMEOUT" envDefault:"10s"`
+	OTEL_READER_TIMEOUT time.Duration `env:"OTEL_READER_TIMEOUT" envDefault:"30s"`
+	// Connect to the collector in the background at startup instead of on the first
+	// export, so early telemetry is not lost while the connection comes up.
+	OTEL_CONNECTION_WARMUP bool `env:"OTEL_CONNECTION_WARMUP" envDefault:"true"`
+	// Use TLS for the collector connection; the minimum version accepts "1.2" or "1.3".
+	OTEL_EXPORTER_TLS_ENABLED     bool   `env:"OTEL_EXPORTER_TLS_ENABLED" envDefault:"false"`
+	OTEL_EXPORTER_TLS_MIN_VERSION string `env:"OTEL_EXPORTER_TLS_MIN_VERSION" envDefault:"1.2"`
 
 	// Debug/Simulation Settings
+	// Exposes /debug/* endpoints; keep disabled in production.
+	DEBUG_ENDPOINTS_ENABLED        bool    `env:"DEBUG_ENDPOINTS_ENABLED" envDefault:"false"`
 	SimulateDelayEnabled           bool    `env:"SIMULATE_DELAY_ENABLED" envDefault:"false"`
 	SimulateDelayMinMs             int     `env:"SIMULATE_DELAY_MIN_MS" envDefault:"10"`
 	SimulateDelayMaxMs             int     `env:"SIMULATE_DELAY_MAX_MS" envDefault:"100"`
+	SimulateDelayStrict            bool    `env:"SIMULATE_DELAY_STRICT" envDefault:"true"`
 	SimulateRandomErrorEnabled     bool    `env:"SIMULATE_RANDOM_ERROR_ENABLED" envDefault:"false"`
 	SimulateOverallErrorChance     float64 `env:"SIMULATE_OVERALL_ERROR_CHANCE" envDefault:"0.1"`
 	SimulateApplicationErrorWeight int     `env:"SIMULATE_APPLICATION_ERROR_WEIGHT" envDefault:"1"`
 	SimulateBusinessErrorWeight    int     `env:"SIMULATE_BUSINESS_ERROR_WEIGHT" envDefault:"1"`
+	// Extra runtime feature flags as name=bool pairs, e.g. "caching=true,reservations=false".
+	FEATURE_FLAGS string `env:"FEATURE_FLAGS"`
+
+	// Access Log Settings
+	// One structured line per request; routes in AccessLogSampledRoutes are logged at
+	// AccessLogSampleRate, everything else (and every error response) always.
+	AccessLogEnabled       bool    `env:"ACCESS_LOG_ENABLED" envDefault:"true"`
+	AccessLogLevel         string  `env:"ACCESS_LOG_LEVEL" envDefault:"info"`
+	AccessLogSampleRate    float64 `env:"ACCESS_LOG_SAMPLE_RATE" envDefault:"1.0"`
+	AccessLogSampledRoutes string  `env:"ACCESS_LOG_SAMPLED_ROUTES" envDefault:"/health,/products"`
+	// Only log trace IDs of sampled traces, since unsampled ones don't resolve in the backend.
+	AccessLogSampledTraceIDOnly bool `env:"ACCESS_LOG_SAMPLED_TRACE_ID_ONLY" envDefault:"false"`
+
+	// Error Span Settings
+	// Errored requests get their route, query params and top-level JSON body fields as
+	// span attributes. Keys containing a denylisted word are never recorded.
+	ErrorSpanRequestContext bool   `env:"ERROR_SPAN_REQUEST_CONTEXT" envDefault:"true"`
+	ErrorSpanDenylist       string `env:"ERROR_SPAN_DENYLIST" envDefault:"password,secret,token,authorization,email,phone,address,card"`
+
+	// Maintenance Settings
+	// Start with writes blocked (503); toggle at runtime via the maintenance_mode flag.
+	MaintenanceModeEnabled   bool `env:"MAINTENANCE_MODE_ENABLED" envDefault:"false"`
+	MaintenanceRetryAfterSec int  `env:"MAINTENANCE_RETRY_AFTER_SEC" envDefault:"120"`
+
+	// Deadline Settings
+	// Apply the caller's X-Deadline-Ms header as a deadline on incoming request contexts.
+	DeadlinePropagationEnabled bool `env:"DEADLINE_PROPAGATION_ENABLED" envDefault:"true"`
+
+	// Notification Settings
+	// Critical errors are posted here when set; empty disables notifications.
+	NotifyWebhookURL       string `env:"NOTIFY_WEBHOOK_URL"`
+	NotifyMinIntervalSec   int    `env:"NOTIFY_MIN_INTERVAL_SEC" envDefault:"60"`
+	NotifyDBErrorThreshold int    `env:"NOTIFY_DB_ERROR_THRESHOLD" envDefault:"5"`
+}
+
+// TracesEndpoint returns the OTLP endpoint for traces.
+func (c *Config) TracesEndpoint() string {
+	return endpointOrDefault(c.OTEL_EXPORTER_OTLP_TRACES_ENDPOINT, c.OTEL_ENDPOINT)
+}
+
+// MetricsEndpoint returns the OTLP endpoint for metrics.
+func (c *Config) MetricsEndpoint() string {
+	return endpointOrDefault(c.OTEL_EXPORTER_OTLP_METRICS_ENDPOINT, c.OTEL_ENDPOINT)
+}
+
+// LogsEndpoint returns the OTLP endpoint for logs.
+func (c *Config) LogsEndpoint() string {
+	return endpointOrDefault(c.OTEL_EXPORTER_OTLP_LOGS_ENDPOINT, c.OTEL_ENDPOINT)
+}
+
+func endpointOrDefault(endpoint, fallback string) string {
+	if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+		return endpoint
+	}
+	return fallback
+}
+
+// ExporterTLSMinVersion returns OTEL_EXPORTER_TLS_MIN_VERSION as a crypto/tls
+// version constant. Versions below 1.2 and unknown values are rejected.
+func (c *Config) ExporterTLSMinVersion() (uint16, error) {
+	switch strings.TrimSpace(c.OTEL_EXPORTER_TLS_MIN_VERSION) {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	case "1.0", "1.1":
+		return 0, fmt.Errorf("OTEL_EXPORTER_TLS_MIN_VERSION %q is too low; use 1.2 or 1.3", c.OTEL_EXPORTER_TLS_MIN_VERSION)
+	default:
+		return 0, fmt.Errorf("unknown OTEL_EXPORTER_TLS_MIN_VERSION %q; use 1.2 or 1.3", c.OTEL_EXPORTER_TLS_MIN_VERSION)
+	}
+}
+
+// ValidateDataFilePath checks that PRODUCT_DATA_FILE_PATH names a regular file, or a
+// file that can be created in an existing directory, so a misconfigured path fails
+// at startup instead of as a confusing read error on the first request.
+func (c *Config) ValidateDataFilePath() error {
+	path := c.PRODUCT_DATA_FILE_PATH
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && info.IsDir():
+		return fmt.Errorf("PRODUCT_DATA_FILE_PATH %q is a directory; point it at the product data JSON file", path)
+	case err == nil && !info.Mode().IsRegular():
+		return fmt.Errorf("PRODUCT_DATA_FILE_PATH %q is not a regular file", path)
+	case err == nil:
+		return nil
+	case !os.IsNotExist(err):
+		return fmt.Errorf("PRODUCT_DATA_FILE_PATH %q cannot be accessed: %w", path, err)
+	}
+
+	// A missing file is fine as long as it could be created later
+	dir := filepath.Dir(path)
+	dirInfo, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("PRODUCT_DATA_FILE_PATH %q is in a directory that cannot be accessed: %w", path, err)
+	}
+	if !dirInfo.IsDir() {
+		return fmt.Errorf("PRODUCT_DATA_FILE_PATH %q is inside %q, which is not a directory", path, dir)
+	}
+	return nil
+}
+
+// ValidateSimulateDelay checks the simulated delay range. The minimum must be
+// non-negative and below a positive maximum; Simulate skips delays otherwise.
+func (c *Config) ValidateSimulateDelay() error {
+	if c.SimulateDelayMinMs < 0 || c.SimulateDelayMaxMs <= 0 || c.SimulateDelayMinMs >= c.SimulateDelayMaxMs {
+		return fmt.Errorf("invalid simulated delay range: SIMULATE_DELAY_MIN_MS=%d must be >= 0 and below SIMULATE_DELAY_MAX_MS=%d",
+			c.SimulateDelayMinMs, c.SimulateDelayMaxMs)
+	}
+	return nil
+}
+
+// OtlpHeaders parses OTEL_EXPORTER_OTLP_HEADERS into a header map.
+// Malformed pairs are skipped.
+func (c *Config) OtlpHeaders() map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(c.OTEL_EXPORTER_OTLP_HEADERS, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+// HeaderKeys returns only the keys of the given headers, sorted, for safe logging.
+func HeaderKeys(headers map[string]string) []string {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // NOTE: Removed GetProductionConfig, GetDevelopmentConfig, commonConfig functions
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOtlpHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  string
+		want     map[string]string
+		wantKeys []string
+	}{
+		{name: "empty", headers: "", want: map[string]string{}, wantKeys: []string{}},
+		{name: "single pair", headers: "api-key=abc", want: map[string]string{"api-key": "abc"}, wantKeys: []string{"api-key"}},
+		{
+			name:     "spacing trimmed and keys sorted",
+			headers:  " x-tenant = acme , authorization=Bearer t=1",
+			want:     map[string]string{"x-tenant": "acme", "authorization": "Bearer t=1"},
+			wantKeys: []string{"authorization", "x-tenant"},
+		},
+		{
+			name:     "malformed pairs skipped",
+			headers:  "novalue,=orphan,ok=1,",
+			want:     map[string]string{"ok": "1"},
+			wantKeys: []string{"ok"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{OTEL_EXPORTER_OTLP_HEADERS: tt.headers}
+			got := cfg.OtlpHeaders()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OtlpHeaders() = %v, want %v", got, tt.want)
+			}
+			if keys := HeaderKeys(got); !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("HeaderKeys() = %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestSignalEndpoints(t *testing.T) {
+	tests := []struct {
+		name                              string
+		cfg                               Config
+		wantTraces, wantMetrics, wantLogs string
+	}{
+		{
+			name:       "common endpoint only",
+			cfg:        Config{OTEL_ENDPOINT: "collector:4317"},
+			wantTraces: "collector:4317", wantMetrics: "collector:4317", wantLogs: "collector:4317",
+		},
+		{
+			name: "every signal overridden",
+			cfg: Config{
+				OTEL_ENDPOINT:                       "collector:4317",
+				OTEL_EXPORTER_OTLP_TRACES_ENDPOINT:  "traces:4317",
+				OTEL_EXPORTER_OTLP_METRICS_ENDPOINT: "metrics:4317",
+				OTEL_EXPORTER_OTLP_LOGS_ENDPOINT:    "logs:4317",
+			},
+			wantTraces: "traces:4317", wantMetrics: "metrics:4317", wantLogs: "logs:4317",
+		},
+		{
+			name:       "one signal overridden",
+			cfg:        Config{OTEL_ENDPOINT: "collector:4317", OTEL_EXPORTER_OTLP_LOGS_ENDPOINT: "logs:4317"},
+			wantTraces: "collector:4317", wantMetrics: "collector:4317", wantLogs: "logs:4317",
+		},
+		{
+			name:       "blank override falls back",
+			cfg:        Config{OTEL_ENDPOINT: "collector:4317", OTEL_EXPORTER_OTLP_TRACES_ENDPOINT: "  "},
+			wantTraces: "collector:4317", wantMetrics: "collector:4317", wantLogs: "collector:4317",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.TracesEndpoint(); got != tt.wantTraces {
+				t.Errorf("TracesEndpoint() = %q, want %q", got, tt.wantTraces)
+			}
+			if got := tt.cfg.MetricsEndpoint(); got != tt.wantMetrics {
+				t.Errorf("MetricsEndpoint() = %q, want %q", got, tt.wantMetrics)
+			}
+			if got := tt.cfg.LogsEndpoint(); got != tt.wantLogs {
+				t.Errorf("LogsEndpoint() = %q, want %q", got, tt.wantLogs)
+			}
+		})
+	}
+}
+
+func TestExporterTLSMinVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    uint16
+		wantErr string
+	}{
+		{version: "1.2", want: tls.VersionTLS12},
+		{version: " 1.3 ", want: tls.VersionTLS13},
+		{version: "1.1", wantErr: "too low"},
+		{version: "1.0", wantErr: "too low"},
+		{version: "TLS1.3", wantErr: "unknown"},
+		{version: "", wantErr: "unknown"},
+	}
+	for _, tt := range tests {
+		cfg := Config{OTEL_EXPORTER_TLS_MIN_VERSION: tt.version}
+		got, err := cfg.ExporterTLSMinVersion()
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ExporterTLSMinVersion(%q) error = %v, want one saying %q", tt.version, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ExporterTLSMinVersion(%q) = %#x, %v, want %#x", tt.version, got, err, tt.want)
+		}
+	}
+}
+
+func TestValidateDataFilePath(t *testing.T) {
+	dir := t.TempDir()
+	dataFile := filepath.Join(dir, "data.json")
+	if err := os.WriteFile(dataFile, []byte("{}"), 0o644); err != nil {
+		t.Fatalf("write data file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "existing file", path: dataFile},
+		{name: "missing file in an existing directory", path: filepath.Join(dir, "new.json")},
+		{name: "directory", path: dir, wantErr: "is a directory"},
+		{name: "device", path: os.DevNull, wantErr: "not a regular file"},
+		{name: "missing directory", path: filepath.Join(dir, "missing", "data.json"), wantErr: "cannot be accessed"},
+		{name: "parent is a file", path: filepath.Join(dataFile, "data.json"), wantErr: "not a directory"},
+	}
+	for _, tt := range tests {
+		cfg := Config{PRODUCT_DATA_FILE_PATH: tt.path}
+		err := cfg.ValidateDataFilePath()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: ValidateDataFilePath() = %v, want nil", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "PRODUCT_DATA_FILE_PATH") {
+			t.Errorf("%s: ValidateDataFilePath() = %v, want an error naming PRODUCT_DATA_FILE_PATH and saying %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateSimulateDelay(t *testing.T) {
+	tests := []struct {
+		min, max int
+		wantErr  bool
+	}{
+		{min: 10, max: 100},
+		{min: 0, max: 1},
+		{min: 100, max: 100, wantErr: true},
+		{min: 200, max: 100, wantErr: true},
+		{min: -1, max: 100, wantErr: true},
+		{min: 0, max: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		cfg := Config{SimulateDelayMinMs: tt.min, SimulateDelayMaxMs: tt.max}
+		if err := cfg.ValidateSimulateDelay(); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateSimulateDelay(min=%d, max=%d) = %v, want error %v", tt.min, tt.max, err, tt.wantErr)
+		}
+	}
+}
@@ -4,55 +4,97 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
-	"os"
+	"time"
 
+	"github.com/narender/common/clock"
 	"github.com/narender/common/globals"
+	"github.com/narender/common/telemetry/metric"
 	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// AttrCatalogVersion identifies the catalog version on database spans.
+const AttrCatalogVersion = "db.catalog.version"
+
+// AttrInMemory marks database spans served by the in-memory read-only fallback.
+const AttrInMemory = "db.in_memory"
+
 // FileDatabase provides methods to interact with a JSON file database.
 type FileDatabase struct {
-	filePath string
-	logger   *slog.Logger
+	filePath      string
+	prettyJSON    bool
+	slowThreshold time.Duration
+	logger        *slog.Logger
+	version       string // catalog version recorded on spans
+	// memory replaces the file when it is read-only and DB_READONLY_FALLBACK is set
+	memory *memoryStore
+	// clock times operations for slow-operation detection
+	clock clock.Clock
+}
+
+// Option customizes a FileDatabase.
+type Option func(*FileDatabase)
+
+// WithClock sets the clock used to time operations, e.g. a clock.Fake in tests.
+func WithClock(c clock.Clock) Option {
+	return func(db *FileDatabase) {
+		db.clock = c
+	}
 }
 
 // NewFileDatabase creates a new instance of FileDatabase.
-func NewFileDatabase() *FileDatabase {
-	return &FileDatabase{
-		filePath: globals.Cfg().PRODUCT_DATA_FILE_PATH,
-		logger:   globals.Logger(),
+func NewFileDatabase(opts ...Option) *FileDatabase {
+	db := &FileDatabase{
+		filePath:      globals.Cfg().PRODUCT_DATA_FILE_PATH,
+		prettyJSON:    globals.Cfg().DB_PRETTY_JSON,
+		slowThreshold: time.Duration(globals.Cfg().DB_SLOW_THRESHOLD_MS) * time.Millisecond,
+		logger:        globals.Logger().With(slog.String("component", "file_database")),
+		version:       globals.Cfg().DB_CATALOG_VERSION,
+		clock:         clock.Real{},
+	}
+	for _, opt := range opts {
+		opt(db)
 	}
+	metric.SetDBFilePath(db.filePath)
+	if globals.Cfg().DB_READONLY_FALLBACK && isReadOnly(db.filePath) {
+		db.enableMemoryFallback()
+	}
+	db.logger.Info("File database initialized",
+		slog.String("file_path", db.filePath),
+		slog.Bool("pretty_json", db.prettyJSON))
+	return db
 }
 
 // Read loads data from the JSON file into the dest interface{}.
 func (db *FileDatabase) Read(ctx context.Context, dest interface{}) (opErr error) {
-	// Get request ID from context if available
-	var requestID string
-	if id, ok := ctx.Value("requestID").(string); ok {
-		requestID = id
-	}
-
 	// Start DB Span
 	ctx, spanner := commontrace.StartSpan(ctx,
 		"file_database",
 		"read",
 		semconv.DBSystemKey.String("file"),
 		semconv.DBOperationKey.String("READ"),
+		attribute.String(metric.AttrDBFilePath, db.filePath),
+		attribute.String(AttrCatalogVersion, db.version),
+		attribute.Bool(AttrInMemory, db.memory != nil),
 	)
 	defer commontrace.EndSpan(spanner, &opErr, nil)
 
+	start := db.clock.Now()
+	var sizeBytes int
+	defer func() { db.flagIfSlow(ctx, spanner, "read", clock.Since(db.clock, start), sizeBytes) }()
+
 	db.logger.DebugContext(ctx, "Database file access initiated",
 		slog.String("file_path", db.filePath),
-		slog.String("request_id", requestID),
 		slog.String("operation", "read_database"))
 
-	fileContent, err := os.ReadFile(db.filePath)
+	fileContent, err := db.readFile()
+	sizeBytes = len(fileContent)
 	if err != nil {
 		db.logger.ErrorContext(ctx, "Database file read error",
 			slog.String("file_path", db.filePath),
 			slog.String("error", err.Error()),
-			slog.String("request_id", requestID),
 			slog.String("operation", "read_database"))
 		opErr = err // Assign error to opErr
 		return opErr
@@ -63,7 +105,6 @@ func (db *FileDatabase) Read(ctx context.Context, dest interface{}) (opErr error
 		db.logger.ErrorContext(ctx, "JSON parsing error",
 			slog.String("file_path", db.filePath),
 			slog.String("error", err.Error()),
-			slog.String("request_id", requestID),
 			slog.String("operation", "parse_json"))
 		opErr = err // Assign error to opErr
 		return opErr
@@ -71,50 +112,54 @@ func (db *FileDatabase) Read(ctx context.Context, dest interface{}) (opErr error
 
 	db.logger.DebugContext(ctx, "Database data read successfully",
 		slog.String("file_path", db.filePath),
-		slog.String("request_id", requestID),
 		slog.String("operation", "read_database"))
 	return nil // Success
 }
 
 // Write marshals the data interface{} to JSON and writes it to the file, overwriting existing content.
 func (db *FileDatabase) Write(ctx context.Context, data interface{}) (opErr error) {
-	// Get request ID from context if available
-	var requestID string
-	if id, ok := ctx.Value("requestID").(string); ok {
-		requestID = id
-	}
-
 	// Start DB Span
 	ctx, spanner := commontrace.StartSpan(ctx,
 		"file_database",
 		"write",
 		semconv.DBSystemKey.String("file"),
 		semconv.DBOperationKey.String("WRITE"),
+		attribute.String(metric.AttrDBFilePath, db.filePath),
+		attribute.String(AttrCatalogVersion, db.version),
+		attribute.Bool(AttrInMemory, db.memory != nil),
 	)
 	defer commontrace.EndSpan(spanner, &opErr, nil)
 
+	start := db.clock.Now()
+	var sizeBytes int
+	defer func() { db.flagIfSlow(ctx, spanner, "write", clock.Since(db.clock, start), sizeBytes) }()
+
 	db.logger.DebugContext(ctx, "Database file write initiated",
 		slog.String("file_path", db.filePath),
-		slog.String("request_id", requestID),
 		slog.String("operation", "write_database"))
 
-	jsonData, err := json.MarshalIndent(data, "", "  ") // Use MarshalIndent for readability
+	var jsonData []byte
+	var err error
+	if db.prettyJSON {
+		jsonData, err = json.MarshalIndent(data, "", "  ") // Use MarshalIndent for readability
+	} else {
+		jsonData, err = json.Marshal(data)
+	}
 	if err != nil {
 		db.logger.ErrorContext(ctx, "JSON serialization error",
 			slog.String("file_path", db.filePath),
 			slog.String("error", err.Error()),
-			slog.String("request_id", requestID),
 			slog.String("operation", "serialize_json"))
 		opErr = err // Assign error to opErr
 		return opErr
 	}
 
-	err = os.WriteFile(db.filePath, jsonData, 0644) // 0644 provides read/write for owner, read for others
+	sizeBytes = len(jsonData)
+	err = db.writeFile(jsonData)
 	if err != nil {
 		db.logger.ErrorContext(ctx, "Database file write error",
 			slog.String("file_path", db.filePath),
 			slog.String("error", err.Error()),
-			slog.String("request_id", requestID),
 			slog.String("operation", "write_database"))
 		opErr = err // Assign error to opErr
 		return opErr
@@ -122,11 +167,48 @@ func (db *FileDatabase) Write(ctx context.Context, data interface{}) (opErr erro
 
 	db.logger.DebugContext(ctx, "Database data written successfully",
 		slog.String("file_path", db.filePath),
-		slog.String("request_id", requestID),
 		slog.String("operation", "write_database"))
 	return nil // Success
 }
 
+// flagIfSlow adds a db.slow event and logs a warning when an operation took longer
+// than the configured threshold, so filesystem slowness stands out from app logic.
+func (db *FileDatabase) flagIfSlow(ctx context.Context, span trace.Span, operation string, elapsed time.Duration, sizeBytes int) {
+	if db.slowThreshold <= 0 || elapsed < db.slowThreshold {
+		return
+	}
+
+	span.AddEvent("db.slow", trace.WithAttributes(
+		attribute.String("db.operation", operation),
+		attribute.Int64("db.duration_ms", elapsed.Milliseconds()),
+		attribute.Int64("db.threshold_ms", db.slowThreshold.Milliseconds()),
+		attribute.Int("db.file.size_bytes", sizeBytes),
+	))
+	db.logger.WarnContext(ctx, "Slow database file operation",
+		slog.String("file_path", db.filePath),
+		slog.String("operation", operation),
+		slog.Int64("duration_ms", elapsed.Milliseconds()),
+		slog.Int64("threshold_ms", db.slowThreshold.Milliseconds()),
+		slog.Int("size_bytes", sizeBytes))
+}
+
+// enableMemoryFallback switches reads and writes to an in-memory copy of the file.
+// If the file cannot be read either, the database keeps using the file so errors
+// surface on the first request as before.
+func (db *FileDatabase) enableMemoryFallback() {
+	memory, err := newMemoryStore(db.filePath)
+	if err != nil {
+		db.logger.Error("Data file is read-only and could not be loaded for the in-memory fallback",
+			slog.String("file_path", db.filePath),
+			slog.String("error", err.Error()))
+		return
+	}
+	db.memory = memory
+	db.logger.Warn("DATA FILE IS READ-ONLY: serving from memory; stock and product changes will NOT be persisted and are lost on restart",
+		slog.String("file_path", db.filePath),
+		slog.Int("size_bytes", len(memory.content)))
+}
+
 // FilePath returns the path to the database file.
 func (db *FileDatabase) FilePath() string {
 	return db.filePath
@@ -0,0 +1,284 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/narender/common/clock"
+	"github.com/narender/common/config"
+	"github.com/narender/common/globals"
+	"github.com/narender/common/telemetry/metric"
+	"github.com/narender/common/telemetry/telemetrytest"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var harness *telemetrytest.Harness
+
+func TestMain(m *testing.M) {
+	harness = telemetrytest.NewTestHarness()
+	code := m.Run()
+	harness.Teardown()
+	os.Exit(code)
+}
+
+// testProduct mirrors the shape of a catalog entry without depending on a service model.
+type testProduct struct {
+	Name     string  `json:"name"`
+	Category string  `json:"category"`
+	Price    float64 `json:"price"`
+	Stock    int     `json:"stock"`
+}
+
+// newTestDatabase returns a FileDatabase on a fresh file in a temporary directory.
+func newTestDatabase(tb testing.TB, pretty bool) (*FileDatabase, string) {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "data.json")
+	globals.InitForTest(tb, func(c *config.Config) {
+		c.PRODUCT_DATA_FILE_PATH = path
+		c.DB_PRETTY_JSON = pretty
+	})
+	return NewFileDatabase(), path
+}
+
+// catalog returns n products spread over a handful of categories.
+func catalog(n int) map[string]testProduct {
+	categories := []string{"Kitchen", "Office", "Sports", "Garden", "Toys"}
+	products := make(map[string]testProduct, n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("Product %05d", i)
+		products[name] = testProduct{Name: name, Category: categories[i%len(categories)], Price: float64(i%500) + 0.99, Stock: i % 100}
+	}
+	return products
+}
+
+func TestWriteRoundTripsInBothModes(t *testing.T) {
+	tests := []struct {
+		name       string
+		pretty     bool
+		wantIndent bool
+	}{
+		{name: "pretty", pretty: true, wantIndent: true},
+		{name: "compact", pretty: false, wantIndent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, path := newTestDatabase(t, tt.pretty)
+			want := catalog(20)
+
+			if err := db.Write(context.Background(), want); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			var got map[string]testProduct
+			if err := db.Read(context.Background(), &got); err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("read back %d products that differ from the %d written", len(got), len(want))
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read data file: %v", err)
+			}
+			if indented := bytes.Contains(content, []byte("\n  ")); indented != tt.wantIndent {
+				t.Errorf("data file indented = %v, want %v", indented, tt.wantIndent)
+			}
+		})
+	}
+}
+
+func BenchmarkWrite(b *testing.B) {
+	products := catalog(10_000)
+	for _, mode := range []struct {
+		name   string
+		pretty bool
+	}{
+		{name: "pretty", pretty: true},
+		{name: "compact", pretty: false},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			db, _ := newTestDatabase(b, mode.pretty)
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := db.Write(ctx, products); err != nil {
+					b.Fatalf("Write: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// slowClock is a fake clock that moves forward by step every time it is read,
+// so each operation appears to take step to the database.
+type slowClock struct {
+	*clock.Fake
+	step time.Duration
+}
+
+func (c *slowClock) Now() time.Time {
+	now := c.Fake.Now()
+	c.Fake.Advance(c.step)
+	return now
+}
+
+// slowEvent returns the db.slow event attributes on the only span named name.
+func slowEvent(t *testing.T, name string) (map[attribute.Key]attribute.Value, bool) {
+	t.Helper()
+	spans := harness.SpansNamed(name)
+	if len(spans) != 1 {
+		t.Fatalf("got %d %q spans, want 1", len(spans), name)
+	}
+	for _, event := range spans[0].Events {
+		if event.Name == "db.slow" {
+			return eventAttributes(event), true
+		}
+	}
+	return nil, false
+}
+
+func eventAttributes(event sdktrace.Event) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value, len(event.Attributes))
+	for _, kv := range event.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestSlowOperationsAreFlagged(t *testing.T) {
+	tests := []struct {
+		name        string
+		thresholdMS int
+		step        time.Duration
+		wantSlow    bool
+	}{
+		{name: "slower than threshold", thresholdMS: 100, step: 250 * time.Millisecond, wantSlow: true},
+		{name: "exactly at threshold", thresholdMS: 100, step: 100 * time.Millisecond, wantSlow: true},
+		{name: "faster than threshold", thresholdMS: 100, step: 20 * time.Millisecond, wantSlow: false},
+		{name: "detection disabled", thresholdMS: 0, step: time.Hour, wantSlow: false},
+	}
+
+	for _, tt := range tests {
+		for _, operation := range []string{"read", "write"} {
+			t.Run(tt.name+"/"+operation, func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "data.json")
+				globals.InitForTest(t, func(c *config.Config) {
+					c.PRODUCT_DATA_FILE_PATH = path
+					c.DB_SLOW_THRESHOLD_MS = tt.thresholdMS
+					c.DB_PRETTY_JSON = false
+				})
+				logs := globals.CaptureLogsForTest(t)
+				if err := os.WriteFile(path, []byte(`{"a":1}`), 0o644); err != nil {
+					t.Fatalf("seed data file: %v", err)
+				}
+				db := NewFileDatabase(WithClock(&slowClock{Fake: clock.NewFake(time.Unix(0, 0)), step: tt.step}))
+				harness.Reset()
+
+				var err error
+				if operation == "read" {
+					var dest map[string]int
+					err = db.Read(context.Background(), &dest)
+				} else {
+					err = db.Write(context.Background(), map[string]int{"a": 1})
+				}
+				if err != nil {
+					t.Fatalf("%s: %v", operation, err)
+				}
+
+				attrs, slow := slowEvent(t, "file_database :: "+operation)
+				warned := bytes.Contains(logs.Bytes(), []byte(`"msg":"Slow database file operation"`))
+				if slow != tt.wantSlow || warned != tt.wantSlow {
+					t.Fatalf("db.slow event = %v, warning logged = %v, want %v", slow, warned, tt.wantSlow)
+				}
+				if !tt.wantSlow {
+					return
+				}
+
+				if got := attrs["db.operation"].AsString(); got != operation {
+					t.Errorf("db.operation = %q, want %q", got, operation)
+				}
+				if got := attrs["db.duration_ms"].AsInt64(); got != tt.step.Milliseconds() {
+					t.Errorf("db.duration_ms = %d, want %d", got, tt.step.Milliseconds())
+				}
+				if got := attrs["db.threshold_ms"].AsInt64(); got != int64(tt.thresholdMS) {
+					t.Errorf("db.threshold_ms = %d, want %d", got, tt.thresholdMS)
+				}
+				if got := attrs["db.file.size_bytes"].AsInt64(); got != 7 {
+					t.Errorf("db.file.size_bytes = %d, want 7", got)
+				}
+
+				var record map[string]any
+				for _, line := range bytes.Split(bytes.TrimSpace(logs.Bytes()), []byte("\n")) {
+					if bytes.Contains(line, []byte("Slow database file operation")) {
+						if err := json.Unmarshal(line, &record); err != nil {
+							t.Fatalf("decode log line: %v", err)
+						}
+					}
+				}
+				if record["level"] != "WARN" || record["operation"] != operation || record["duration_ms"] != float64(tt.step.Milliseconds()) {
+					t.Errorf("warning = %v, want level WARN, operation %q and duration_ms %d", record, operation, tt.step.Milliseconds())
+				}
+			})
+		}
+	}
+}
+
+func TestDatabaseSpansCarryFileAndCatalogVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		wantVersion string
+	}{
+		{name: "configured version", version: "2026-10", wantVersion: "2026-10"},
+		{name: "default version", wantVersion: "unversioned"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "data.json")
+			globals.InitForTest(t, func(c *config.Config) {
+				c.PRODUCT_DATA_FILE_PATH = path
+				if tt.version != "" {
+					c.DB_CATALOG_VERSION = tt.version
+				}
+			})
+			db := NewFileDatabase()
+			harness.Reset()
+
+			if err := db.Write(context.Background(), catalog(2)); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			var got map[string]testProduct
+			if err := db.Read(context.Background(), &got); err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+
+			for _, name := range []string{"file_database :: write", "file_database :: read"} {
+				spans := harness.SpansNamed(name)
+				if len(spans) != 1 {
+					t.Fatalf("got %d %q spans, want 1", len(spans), name)
+				}
+				attrs := make(map[attribute.Key]string)
+				for _, kv := range spans[0].Attributes {
+					attrs[kv.Key] = kv.Value.Emit()
+				}
+				if attrs[metric.AttrDBFilePath] != path {
+					t.Errorf("%s: %s = %q, want %q", name, metric.AttrDBFilePath, attrs[metric.AttrDBFilePath], path)
+				}
+				if attrs[AttrCatalogVersion] != tt.wantVersion {
+					t.Errorf("%s: %s = %q, want %q", name, AttrCatalogVersion, attrs[AttrCatalogVersion], tt.wantVersion)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// memoryStore holds the data file contents when the file cannot be written.
+// Changes live only as long as the process.
+type memoryStore struct {
+	mu      sync.RWMutex
+	content []byte
+}
+
+// isReadOnly reports whether path exists but cannot be opened for writing,
+// e.g. on a read-only container filesystem.
+func isReadOnly(path string) bool {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err == nil {
+		f.Close()
+		return false
+	}
+	return os.IsPermission(err) || errors.Is(err, syscall.EROFS)
+}
+
+// newMemoryStore seeds a memoryStore from the file at path.
+func newMemoryStore(path string) (*memoryStore, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &memoryStore{content: content}, nil
+}
+
+func (m *memoryStore) read() []byte {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]byte(nil), m.content...)
+}
+
+func (m *memoryStore) write(content []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.content = append([]byte(nil), content...)
+}
+
+// readFile returns the data file contents, from memory when the fallback is active.
+func (db *FileDatabase) readFile() ([]byte, error) {
+	if db.memory != nil {
+		return db.memory.read(), nil
+	}
+	return os.ReadFile(db.filePath)
+}
+
+// writeFile replaces the data file contents, in memory when the fallback is active.
+func (db *FileDatabase) writeFile(content []byte) error {
+	if db.memory != nil {
+		db.memory.write(content)
+		return nil
+	}
+	return os.WriteFile(db.filePath, content, 0644) // 0644 provides read/write for owner, read for others
+}
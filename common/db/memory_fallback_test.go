@@ -0,0 +1,129 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/narender/common/config"
+	"github.com/narender/common/globals"
+)
+
+// writeCatalogFile writes products to a fresh data file and returns its path.
+func writeCatalogFile(t *testing.T, products map[string]testProduct) string {
+	t.Helper()
+	content, err := json.Marshal(products)
+	if err != nil {
+		t.Fatalf("marshal catalog: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+	return path
+}
+
+func TestReadOnlyDataFileDetection(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only files")
+	}
+	tests := []struct {
+		name       string
+		mode       os.FileMode
+		fallback   bool
+		wantMemory bool
+	}{
+		{name: "writable file", mode: 0o644, fallback: true, wantMemory: false},
+		{name: "read-only file", mode: 0o444, fallback: true, wantMemory: true},
+		{name: "read-only file without fallback", mode: 0o444, fallback: false, wantMemory: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeCatalogFile(t, catalog(3))
+			if err := os.Chmod(path, tt.mode); err != nil {
+				t.Fatalf("chmod: %v", err)
+			}
+			globals.InitForTest(t, func(c *config.Config) {
+				c.PRODUCT_DATA_FILE_PATH = path
+				c.DB_READONLY_FALLBACK = tt.fallback
+			})
+
+			if got := NewFileDatabase().memory != nil; got != tt.wantMemory {
+				t.Errorf("in-memory fallback active = %v, want %v", got, tt.wantMemory)
+			}
+		})
+	}
+}
+
+func TestMemoryFallbackWritesDoNotPersist(t *testing.T) {
+	seed := catalog(3)
+	path := writeCatalogFile(t, seed)
+	globals.InitForTest(t, func(c *config.Config) {
+		c.PRODUCT_DATA_FILE_PATH = path
+		c.DB_READONLY_FALLBACK = true
+	})
+	logs := globals.CaptureLogsForTest(t)
+	db := NewFileDatabase()
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read data file: %v", err)
+	}
+
+	// NewFileDatabase does this for a read-only file; root can write to any file,
+	// so switch it on directly
+	db.enableMemoryFallback()
+	if !strings.Contains(logs.String(), "DATA FILE IS READ-ONLY") {
+		t.Errorf("no read-only warning logged, got: %s", logs.String())
+	}
+
+	var seeded map[string]testProduct
+	if err := db.Read(context.Background(), &seeded); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !reflect.DeepEqual(seeded, seed) {
+		t.Errorf("in-memory store not seeded from the file: got %d products, want %d", len(seeded), len(seed))
+	}
+
+	updated := catalog(5)
+	harness.Reset()
+	if err := db.Write(context.Background(), updated); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	var got map[string]testProduct
+	if err := db.Read(context.Background(), &got); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !reflect.DeepEqual(got, updated) {
+		t.Errorf("Read after Write returned %d products, want the %d written", len(got), len(updated))
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read data file: %v", err)
+	}
+	if !bytes.Equal(after, before) {
+		t.Errorf("data file changed while serving from memory")
+	}
+
+	for _, name := range []string{"file_database :: write", "file_database :: read"} {
+		spans := harness.SpansNamed(name)
+		if len(spans) != 1 {
+			t.Fatalf("got %d %q spans, want 1", len(spans), name)
+		}
+		inMemory := false
+		for _, kv := range spans[0].Attributes {
+			if kv.Key == AttrInMemory {
+				inMemory = kv.Value.AsBool()
+			}
+		}
+		if !inMemory {
+			t.Errorf("%s: %s not set", name, AttrInMemory)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/narender/common/clock"
+	"github.com/narender/common/telemetry/metric"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// Stream decodes the top-level JSON object of the file one entry at a time and
+// calls fn with each key and its raw value, in file order. Unlike Read it never
+// holds more than one entry in memory, so it suits exporting large data files.
+// Streaming stops at the first error returned by fn, which Stream returns.
+func (db *FileDatabase) Stream(ctx context.Context, fn func(key string, value json.RawMessage) error) (opErr error) {
+	ctx, spanner := commontrace.StartSpan(ctx,
+		"file_database",
+		"stream",
+		semconv.DBSystemKey.String("file"),
+		semconv.DBOperationKey.String("STREAM"),
+		attribute.String(metric.AttrDBFilePath, db.filePath),
+		attribute.String(AttrCatalogVersion, db.version),
+		attribute.Bool(AttrInMemory, db.memory != nil),
+	)
+	defer commontrace.EndSpan(spanner, &opErr, nil)
+
+	start := db.clock.Now()
+	entries := 0
+	counter := &countingReader{}
+	defer func() {
+		spanner.SetAttributes(attribute.Int("db.stream.entries", entries))
+		db.flagIfSlow(ctx, spanner, "stream", clock.Since(db.clock, start), int(counter.n))
+	}()
+
+	var source io.Reader
+	if db.memory != nil {
+		source = bytes.NewReader(db.memory.read())
+	} else {
+		file, err := os.Open(db.filePath)
+		if err != nil {
+			db.logger.ErrorContext(ctx, "Database file open error",
+				slog.String("file_path", db.filePath),
+				slog.String("error", err.Error()),
+				slog.String("operation", "stream_database"))
+			opErr = err
+			return opErr
+		}
+		defer file.Close()
+		source = bufio.NewReader(file)
+	}
+	counter.r = source
+
+	dec := json.NewDecoder(counter)
+	if err := expectDelim(dec, '{'); err != nil {
+		opErr = err
+		return opErr
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			opErr = err
+			return opErr
+		}
+		key, ok := token.(string)
+		if !ok {
+			opErr = fmt.Errorf("expected an object key in %s, got %v", db.filePath, token)
+			return opErr
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			opErr = err
+			return opErr
+		}
+		entries++
+		if err := fn(key, value); err != nil {
+			opErr = err
+			return opErr
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		opErr = err
+		return opErr
+	}
+	return nil
+}
+
+// expectDelim reads the next token from dec and checks that it is delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %q in JSON data file, got %v", delim, token)
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
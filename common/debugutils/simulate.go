@@ -3,9 +3,13 @@ package debugutils
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"math/rand"
+	"sync"
 	"time"
 
+	"github.com/narender/common/clock"
+	"github.com/narender/common/featureflags"
 	"github.com/narender/common/globals"
 	// Import common errors package
 	apierrors "github.com/narender/common/apierrors"
@@ -34,6 +38,19 @@ var predefinedBusinessErrors = []simulatedErrorBlueprint{
 	{Code: apierrors.ErrCodeInvalidProductData, Category: apierrors.CategoryBusiness, Message: "Simulated invalid product data"},
 }
 
+// simClock times injected delays; tests replace it with a fake via SetClock.
+var simClock clock.Clock = clock.Real{}
+
+// SetClock replaces the clock used for simulated delays and returns the previous one.
+func SetClock(c clock.Clock) clock.Clock {
+	previous := simClock
+	simClock = c
+	return previous
+}
+
+// invalidDelayWarning reports a misconfigured delay range once instead of on every request.
+var invalidDelayWarning sync.Once
+
 // Simulate now returns *apierrors.AppError or nil
 func Simulate(ctx context.Context) *apierrors.AppError {
 	cfg := globals.Cfg() // Assuming Cfg() returns a struct that will have the new fields
@@ -45,20 +62,33 @@ func Simulate(ctx context.Context) *apierrors.AppError {
 	rng := rand.New(source)
 
 	// Existing Delay Simulation Logic
-	if cfg.SimulateDelayEnabled {
+	if featureflags.IsEnabled(featureflags.SimulateDelay) {
 		// Check for valid delay configuration
-		if !(cfg.SimulateDelayMinMs < 0 || cfg.SimulateDelayMaxMs <= 0 || cfg.SimulateDelayMinMs >= cfg.SimulateDelayMaxMs) {
+		if cfg.ValidateSimulateDelay() == nil {
 			delayRange := cfg.SimulateDelayMaxMs - cfg.SimulateDelayMinMs
 			randomDelayMs := rng.Intn(delayRange+1) + cfg.SimulateDelayMinMs
 			delayDuration := time.Duration(randomDelayMs) * time.Millisecond
-			time.Sleep(delayDuration)
+
+			// Respect cancellation so injected delays never outlive the request deadline
+			select {
+			case <-simClock.After(delayDuration):
+			case <-ctx.Done():
+				return apierrors.NewApplicationError(apierrors.ErrCodeRequestTimeout,
+					"Request cancelled during simulated delay", ctx.Err())
+			}
+		} else {
+			invalidDelayWarning.Do(func() {
+				slog.WarnContext(ctx, "Simulated delay skipped: invalid delay range",
+					slog.Int("min_ms", cfg.SimulateDelayMinMs),
+					slog.Int("max_ms", cfg.SimulateDelayMaxMs))
+			})
 		}
 	}
 
 	// Check if the random error simulation feature is enabled
 	// Assumes SimulateRandomErrorEnabled, SimulateOverallErrorChance,
 	// SimulateApplicationErrorWeight, and SimulateBusinessErrorWeight are available in cfg.
-	if !cfg.SimulateRandomErrorEnabled { // Master switch for this feature
+	if !featureflags.IsEnabled(featureflags.SimulateRandomError) { // Master switch for this feature
 		return nil
 	}
 
@@ -113,10 +143,11 @@ func Simulate(ctx context.Context) *apierrors.AppError {
 
 		if chosenBlueprint != nil {
 			errMsg := fmt.Sprintf("%s from debug utils", chosenBlueprint.Message)
+			// Marked so metrics about real client behaviour can leave simulated errors out
 			if chosenBlueprint.Category == apierrors.CategoryBusiness {
-				return apierrors.NewBusinessError(chosenBlueprint.Code, errMsg, nil)
+				return apierrors.NewBusinessError(chosenBlueprint.Code, errMsg, nil).WithContext(apierrors.ContextSimulated, true)
 			}
-			return apierrors.NewApplicationError(chosenBlueprint.Code, errMsg, nil)
+			return apierrors.NewApplicationError(chosenBlueprint.Code, errMsg, nil).WithContext(apierrors.ContextSimulated, true)
 		}
 	}
 
@@ -0,0 +1,170 @@
+package debugutils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/narender/common/clock"
+	"github.com/narender/common/config"
+	"github.com/narender/common/globals"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// useFakeClock times simulated delays on a fake clock for the rest of the test.
+func useFakeClock(t *testing.T) *clock.Fake {
+	t.Helper()
+	fake := clock.NewFake(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	previous := SetClock(fake)
+	t.Cleanup(func() { SetClock(previous) })
+	return fake
+}
+
+func TestSimulateDelayRespectsContext(t *testing.T) {
+	tests := []struct {
+		name      string
+		interrupt func(cancel context.CancelFunc, fake *clock.Fake)
+		wantErr   error
+	}{
+		{name: "delay elapses", interrupt: func(cancel context.CancelFunc, fake *clock.Fake) { fake.Advance(time.Minute) }},
+		{name: "cancelled mid-delay", interrupt: func(cancel context.CancelFunc, fake *clock.Fake) { cancel() }, wantErr: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A long delay that only the fake clock can end
+			globals.InitForTest(t, func(c *config.Config) {
+				c.SimulateDelayEnabled = true
+				c.SimulateDelayMinMs = 30_000
+				c.SimulateDelayMaxMs = 60_000
+			})
+			fake := useFakeClock(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			done := make(chan *apierrors.AppError, 1)
+			go func() { done <- Simulate(ctx) }()
+			waitForDelay(t, fake)
+			tt.interrupt(cancel, fake)
+
+			select {
+			case appErr := <-done:
+				checkSimulateResult(t, appErr, tt.wantErr)
+			case <-time.After(time.Second):
+				t.Fatal("Simulate did not return promptly")
+			}
+		})
+	}
+}
+
+func TestSimulateDelayAlreadyExpiredContext(t *testing.T) {
+	globals.InitForTest(t, func(c *config.Config) {
+		c.SimulateDelayEnabled = true
+		c.SimulateDelayMinMs = 30_000
+		c.SimulateDelayMaxMs = 60_000
+	})
+	useFakeClock(t)
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	checkSimulateResult(t, Simulate(ctx), context.DeadlineExceeded)
+}
+
+// waitForDelay blocks until Simulate has started waiting on fake.
+func waitForDelay(t *testing.T, fake *clock.Fake) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for fake.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Simulate never started its delay")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// checkSimulateResult fails unless appErr is nil for a nil wantErr, or a request
+// timeout wrapping wantErr otherwise.
+func checkSimulateResult(t *testing.T, appErr *apierrors.AppError, wantErr error) {
+	t.Helper()
+	if wantErr == nil {
+		if appErr != nil {
+			t.Fatalf("Simulate = %v, want nil", appErr)
+		}
+		return
+	}
+	if appErr == nil {
+		t.Fatalf("Simulate = nil, want %s", apierrors.ErrCodeRequestTimeout)
+	}
+	if appErr.Code != apierrors.ErrCodeRequestTimeout {
+		t.Errorf("error code = %q, want %q", appErr.Code, apierrors.ErrCodeRequestTimeout)
+	}
+	if !errors.Is(appErr, wantErr) {
+		t.Errorf("error %v does not wrap %v", appErr, wantErr)
+	}
+}
+
+func TestSimulateWarnsOnceAboutAnInvalidDelayRange(t *testing.T) {
+	globals.InitForTest(t, func(c *config.Config) {
+		c.SimulateDelayEnabled = true
+		c.SimulateDelayMinMs = 500
+		c.SimulateDelayMaxMs = 100
+	})
+	fake := useFakeClock(t)
+	invalidDelayWarning = sync.Once{}
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	for i := 0; i < 3; i++ {
+		checkSimulateResult(t, Simulate(context.Background()), nil)
+	}
+
+	if fake.Waiters() != 0 {
+		t.Errorf("Simulate waited on the clock despite the invalid range")
+	}
+	if got := strings.Count(logs.String(), "Simulated delay skipped: invalid delay range"); got != 1 {
+		t.Errorf("logged the invalid range warning %d times, want once:\n%s", got, logs.String())
+	}
+	if !strings.Contains(logs.String(), `"min_ms":500,"max_ms":100`) {
+		t.Errorf("warning does not report the configured range:\n%s", logs.String())
+	}
+}
+
+func TestSimulateDelayStaysWithinTheConfiguredRange(t *testing.T) {
+	globals.InitForTest(t, func(c *config.Config) {
+		c.SimulateDelayEnabled = true
+		c.SimulateDelayMinMs = 30_000
+		c.SimulateDelayMaxMs = 60_000
+	})
+	fake := useFakeClock(t)
+
+	for i := 0; i < 20; i++ {
+		done := make(chan *apierrors.AppError, 1)
+		go func() { done <- Simulate(context.Background()) }()
+		waitForDelay(t, fake)
+
+		// Short of the minimum the delay must still be running
+		fake.Advance(30*time.Second - time.Millisecond)
+		select {
+		case appErr := <-done:
+			t.Fatalf("Simulate returned %v before the 30s minimum delay", appErr)
+		case <-time.After(5 * time.Millisecond):
+		}
+
+		// Past the 60s maximum the delay has always ended
+		fake.Advance(30 * time.Second)
+		select {
+		case appErr := <-done:
+			checkSimulateResult(t, appErr, nil)
+		case <-time.After(time.Second):
+			t.Fatal("Simulate still waiting after the 60s maximum delay")
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package events is a small in-process event bus for business events that other
+// parts of the service may react to, such as a sale that needs a restock.
+// Handlers run synchronously on the publishing goroutine.
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Event is anything published on a Bus; Name selects its subscribers.
+type Event interface {
+	Name() string
+}
+
+// ReorderNeededEvent is the name of ReorderNeeded events.
+const ReorderNeededEvent = "ReorderNeeded"
+
+// ReorderNeeded is published when a purchase takes a product's stock below its
+// category's low-stock threshold.
+type ReorderNeeded struct {
+	Product   string
+	Category  string
+	Stock     int
+	Threshold int
+	At        time.Time
+}
+
+// Name implements Event.
+func (ReorderNeeded) Name() string { return ReorderNeededEvent }
+
+// Handler reacts to a published event.
+type Handler func(ctx context.Context, e Event)
+
+// Bus delivers published events to the handlers subscribed to their name.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewBus creates an empty Bus.
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// Default is the process-wide bus used by the services.
+var Default = NewBus()
+
+// Subscribe registers h for events with the given name.
+func (b *Bus) Subscribe(name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], h)
+}
+
+// Publish delivers e to its subscribers and records an event on the current span.
+// A panicking handler is logged and does not stop delivery to the others.
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	b.mu.RLock()
+	handlers := append([]Handler(nil), b.handlers[e.Name()]...)
+	b.mu.RUnlock()
+
+	trace.SpanFromContext(ctx).AddEvent("event.published", trace.WithAttributes(
+		attribute.String("event.name", e.Name()),
+		attribute.Int("event.subscribers", len(handlers)),
+	))
+
+	for _, h := range handlers {
+		deliver(ctx, h, e)
+	}
+}
+
+func deliver(ctx context.Context, h Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.ErrorContext(ctx, "Event handler panicked",
+				slog.String("event", e.Name()),
+				slog.String("panic", fmt.Sprint(r)))
+		}
+	}()
+	h(ctx, e)
+}
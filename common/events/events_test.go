@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// named is a test event with a configurable name.
+type named string
+
+func (n named) Name() string { return string(n) }
+
+func TestBusDeliversByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		publish Event
+		want    []string
+	}{
+		{name: "subscribers in order", publish: ReorderNeeded{Product: "Coffee Mug"}, want: []string{"first", "second"}},
+		{name: "recovers a panicking handler", publish: named("panics"), want: []string{"after panic"}},
+		{name: "no subscribers", publish: named("unheard")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewBus()
+			var got []string
+			record := func(label string) Handler {
+				return func(ctx context.Context, e Event) { got = append(got, label) }
+			}
+			bus.Subscribe(ReorderNeededEvent, record("first"))
+			bus.Subscribe(ReorderNeededEvent, record("second"))
+			bus.Subscribe("panics", func(ctx context.Context, e Event) { panic("handler failed") })
+			bus.Subscribe("panics", record("after panic"))
+			bus.Subscribe("other", record("other"))
+
+			bus.Publish(context.Background(), tt.publish)
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("delivered to %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublishRecordsASpanEvent(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	bus := NewBus()
+	bus.Subscribe(ReorderNeededEvent, func(ctx context.Context, e Event) {})
+
+	ctx, span := provider.Tracer("events_test").Start(context.Background(), "buy")
+	bus.Publish(ctx, ReorderNeeded{Product: "Coffee Mug"})
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	if len(events) != 1 || events[0].Name != "event.published" {
+		t.Fatalf("span events = %+v, want one event.published", events)
+	}
+	attrs := make(map[string]string)
+	for _, kv := range events[0].Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["event.name"] != ReorderNeededEvent || attrs["event.subscribers"] != "1" {
+		t.Errorf("event.published attributes = %v, want event.name=%s and event.subscribers=1", attrs, ReorderNeededEvent)
+	}
+}
@@ -0,0 +1,114 @@
+// Package featureflags holds runtime toggles seeded from configuration.
+// Flags can be flipped at runtime (e.g. via the debug endpoints) without a restart.
+package featureflags
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/narender/common/config"
+)
+
+// Built-in flag names, seeded from the matching *_ENABLED config fields.
+const (
+	SimulateDelay       = "simulate_delay"
+	SimulateRandomError = "simulate_random_error"
+	MaintenanceMode     = "maintenance_mode"
+)
+
+var (
+	mu    sync.RWMutex
+	flags = make(map[string]bool)
+)
+
+// Init seeds the flags from configuration. Built-in flags come from their config
+// fields; FEATURE_FLAGS ("name=true,other=false") adds or overrides others. A
+// malformed FEATURE_FLAGS is rejected and leaves the flags unchanged.
+func Init(cfg *config.Config) error {
+	seeded := map[string]bool{
+		SimulateDelay:       cfg.SimulateDelayEnabled,
+		SimulateRandomError: cfg.SimulateRandomErrorEnabled,
+		MaintenanceMode:     cfg.MaintenanceModeEnabled,
+	}
+	overrides, err := Parse(cfg.FEATURE_FLAGS)
+	if err != nil {
+		return err
+	}
+	for name, enabled := range overrides {
+		seeded[name] = enabled
+	}
+
+	mu.Lock()
+	flags = seeded
+	mu.Unlock()
+	return nil
+}
+
+// Parse reads a FEATURE_FLAGS value of comma-separated name=bool pairs. Empty
+// entries are ignored; every entry without a name or with a value that is not a
+// boolean is reported in the returned error.
+func Parse(spec string) (map[string]bool, error) {
+	parsed := make(map[string]bool)
+	var malformed []string
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if !ok || name == "" || err != nil {
+			malformed = append(malformed, strings.TrimSpace(pair))
+			continue
+		}
+		parsed[name] = enabled
+	}
+	if len(malformed) > 0 {
+		return nil, fmt.Errorf("FEATURE_FLAGS has malformed entries %q, expected name=true or name=false", malformed)
+	}
+	return parsed, nil
+}
+
+// IsEnabled reports whether the named flag is on. Unknown flags are off.
+func IsEnabled(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return flags[name]
+}
+
+// Set overrides a known flag at runtime. It returns false if the flag is unknown.
+func Set(name string, enabled bool) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := flags[name]; !ok {
+		return false
+	}
+	flags[name] = enabled
+	return true
+}
+
+// All returns a copy of every flag and its current value.
+func All() map[string]bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	snapshot := make(map[string]bool, len(flags))
+	for name, enabled := range flags {
+		snapshot[name] = enabled
+	}
+	return snapshot
+}
+
+// Names returns the known flag names in sorted order.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
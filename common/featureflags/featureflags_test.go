@@ -0,0 +1,100 @@
+package featureflags
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/narender/common/config"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          string
+		want          map[string]bool
+		wantMalformed []string
+	}{
+		{name: "empty", spec: "", want: map[string]bool{}},
+		{name: "single flag", spec: "new_checkout=true", want: map[string]bool{"new_checkout": true}},
+		{
+			name: "several flags with spacing",
+			spec: " new_checkout = true , dark_mode=false,beta=1",
+			want: map[string]bool{"new_checkout": true, "dark_mode": false, "beta": true},
+		},
+		{name: "empty entries ignored", spec: "a=true,, ,", want: map[string]bool{"a": true}},
+		{name: "later entry wins", spec: "a=true,a=false", want: map[string]bool{"a": false}},
+		{name: "missing value separator", spec: "a=true,dark_mode", wantMalformed: []string{"dark_mode"}},
+		{name: "missing name", spec: "=true", wantMalformed: []string{"=true"}},
+		{name: "non-boolean value", spec: "a=yes,b=on,c=true", wantMalformed: []string{"a=yes", "b=on"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.spec)
+
+			if len(tt.wantMalformed) > 0 {
+				if err == nil {
+					t.Fatalf("Parse(%q) returned no error, want malformed entries %q", tt.spec, tt.wantMalformed)
+				}
+				for _, entry := range tt.wantMalformed {
+					if !strings.Contains(err.Error(), entry) {
+						t.Errorf("error %q does not name malformed entry %q", err, entry)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.spec, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInit(t *testing.T) {
+	t.Run("seeds built-in and configured flags", func(t *testing.T) {
+		err := Init(&config.Config{SimulateDelayEnabled: true, FEATURE_FLAGS: "new_checkout=true,maintenance_mode=true"})
+		if err != nil {
+			t.Fatalf("Init returned error: %v", err)
+		}
+
+		want := map[string]bool{
+			SimulateDelay:       true,
+			SimulateRandomError: false,
+			MaintenanceMode:     true,
+			"new_checkout":      true,
+		}
+		if got := All(); !reflect.DeepEqual(got, want) {
+			t.Errorf("All() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("rejects malformed flags and keeps the current ones", func(t *testing.T) {
+		if err := Init(&config.Config{FEATURE_FLAGS: "kept=true"}); err != nil {
+			t.Fatalf("Init returned error: %v", err)
+		}
+
+		if err := Init(&config.Config{FEATURE_FLAGS: "kept=false,broken"}); err == nil {
+			t.Fatalf("Init accepted malformed FEATURE_FLAGS")
+		}
+		if !IsEnabled("kept") {
+			t.Errorf("flags changed although FEATURE_FLAGS was rejected")
+		}
+	})
+}
+
+func TestSetOnlyKnownFlags(t *testing.T) {
+	if err := Init(&config.Config{FEATURE_FLAGS: "beta=false"}); err != nil {
+		t.Fatalf("Init returned error: %v", err)
+	}
+
+	if !Set("beta", true) || !IsEnabled("beta") {
+		t.Errorf("Set did not enable a known flag")
+	}
+	if Set("unknown", true) || IsEnabled("unknown") {
+		t.Errorf("Set accepted an unknown flag")
+	}
+}
@@ -2,20 +2,25 @@ package globals
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
+	"os"
 	"reflect"
 	"sync"
+	"sync/atomic"
 
 	"github.com/caarlos0/env/v10"
 	"github.com/joho/godotenv"
 	"github.com/narender/common/config"
+	"github.com/narender/common/featureflags"
 	commonLog "github.com/narender/common/log"
 	commonOtel "github.com/narender/common/telemetry"
 )
 
 var (
-	cfg    *config.Config
+	// Swapped whole on reload so readers never see a half-applied config
+	cfg    atomic.Pointer[config.Config]
 	logger *slog.Logger
 	once   sync.Once
 )
@@ -35,19 +40,20 @@ func Init() error {
 			initErr = fmt.Errorf("failed to parse configuration: %w", err)
 			return
 		}
-		cfg = currentCfg
-
-		fmt.Println("--- Loaded Configuration ---")
-		val := reflect.ValueOf(cfg).Elem()
-		typ := val.Type()
-		for i := 0; i < val.NumField(); i++ {
-			fieldName := typ.Field(i).Name
-			fieldValue := val.Field(i).Interface()
-			fmt.Printf("Key: %s, Value: %v\n", fieldName, fieldValue)
+		if err := currentCfg.ValidateDataFilePath(); err != nil {
+			log.Printf("CRITICAL: Invalid product data path: %v\n", err)
+			initErr = fmt.Errorf("invalid configuration: %w", err)
+			return
 		}
-		fmt.Println("--------------------------")
+		if err := checkSimulateDelay(currentCfg); err != nil {
+			initErr = err
+			return
+		}
+		cfg.Store(currentCfg)
+
+		printConfig(os.Stdout, currentCfg)
 
-		if err := commonLog.Init(cfg.LOG_LEVEL, cfg.ENVIRONMENT); err != nil {
+		if err := commonLog.Init(currentCfg.LOG_LEVEL, currentCfg.ENVIRONMENT, currentCfg.LOG_SCOPE_LEVELS); err != nil {
 			log.Printf("CRITICAL: Logger initialization failed: %v\n", err)
 			initErr = fmt.Errorf("failed to initialize logger: %w", err)
 			return
@@ -58,14 +64,25 @@ func Init() error {
 			initErr = fmt.Errorf("logger nil after successful initialization")
 			return
 		}
-		logger.Info("Logger initialized", slog.String("level", cfg.LOG_LEVEL))
+		logger.Info("Logger initialized", slog.String("level", currentCfg.LOG_LEVEL))
+		if currentCfg.LOG_CAPTURE_STDLIB {
+			commonLog.RedirectStdLog(logger)
+		}
+
+		if err := featureflags.Init(currentCfg); err != nil {
+			logger.Error("Invalid feature flag configuration", slog.Any("error", err))
+			initErr = fmt.Errorf("invalid configuration: %w", err)
+			return
+		}
 
-		if err := commonOtel.InitTelemetry(cfg); err != nil {
+		if err := commonOtel.InitTelemetry(currentCfg); err != nil {
 			logger.Error("Failed to initialize OpenTelemetry", slog.Any("error", err))
 			initErr = fmt.Errorf("failed to initialize telemetry: %w", err)
 			return
 		}
-		logger.Info("OpenTelemetry initialized", slog.String("endpoint", cfg.OTEL_ENDPOINT))
+		logger.Info("OpenTelemetry initialized",
+			slog.String("endpoint", currentCfg.OTEL_ENDPOINT),
+			slog.String("deployment_environment", currentCfg.DEPLOYMENT_ENV))
 
 		logger.Info("Application Globals Initialized Successfully.")
 	})
@@ -76,10 +93,11 @@ func Init() error {
 // Cfg returns the loaded configuration.
 // Panics if Init() was not called or failed.
 func Cfg() *config.Config {
-	if cfg == nil {
+	current := cfg.Load()
+	if current == nil {
 		panic("FATAL: Configuration accessed before successful initialization. Call globals.Init() at application start and check for errors.")
 	}
-	return cfg
+	return current
 }
 
 // Logger returns the initialized global logger.
@@ -90,3 +108,36 @@ func Logger() *slog.Logger {
 	}
 	return logger
 }
+
+// printConfig writes every configuration field to w. Fields tagged redact:"true"
+// hold OTLP headers, whose values may carry credentials, so only their keys are
+// written.
+func printConfig(w io.Writer, c *config.Config) {
+	fmt.Fprintln(w, "--- Loaded Configuration ---")
+	val := reflect.ValueOf(c).Elem()
+	typ := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		fieldName := typ.Field(i).Name
+		fieldValue := val.Field(i).Interface()
+		if typ.Field(i).Tag.Get("redact") == "true" {
+			fieldValue = config.HeaderKeys(c.OtlpHeaders())
+		}
+		fmt.Fprintf(w, "Key: %s, Value: %v\n", fieldName, fieldValue)
+	}
+	fmt.Fprintln(w, "--------------------------")
+}
+
+// checkSimulateDelay rejects an invalid simulated delay range when
+// SIMULATE_DELAY_STRICT is set, and only warns about it otherwise.
+func checkSimulateDelay(c *config.Config) error {
+	err := c.ValidateSimulateDelay()
+	if err == nil {
+		return nil
+	}
+	if c.SimulateDelayStrict {
+		log.Printf("CRITICAL: %v\n", err)
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	log.Printf("WARN: %v; simulated delays will be skipped\n", err)
+	return nil
+}
@@ -0,0 +1,84 @@
+package globals
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/narender/common/config"
+)
+
+func TestPrintConfigNeverWritesHeaderValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		headers    string
+		wantKeys   []string
+		hiddenVals []string
+	}{
+		{name: "no headers", headers: ""},
+		{name: "single api key", headers: "signoz-ingestion-key=s3cr3t-token", wantKeys: []string{"signoz-ingestion-key"}, hiddenVals: []string{"s3cr3t-token"}},
+		{
+			name:       "several headers with spacing",
+			headers:    " authorization = Bearer abc.def , x-tenant=acme-prod",
+			wantKeys:   []string{"authorization", "x-tenant"},
+			hiddenVals: []string{"Bearer abc.def", "abc.def", "acme-prod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			printConfig(&out, &config.Config{OTEL_EXPORTER_OTLP_HEADERS: tt.headers})
+
+			for _, value := range tt.hiddenVals {
+				if strings.Contains(out.String(), value) {
+					t.Errorf("configuration output contains header value %q:\n%s", value, out.String())
+				}
+			}
+			for _, key := range tt.wantKeys {
+				if !strings.Contains(out.String(), key) {
+					t.Errorf("configuration output is missing header key %q", key)
+				}
+			}
+			if !strings.Contains(out.String(), "Key: OTEL_EXPORTER_OTLP_HEADERS") {
+				t.Errorf("configuration output is missing the headers field")
+			}
+		})
+	}
+}
+
+func TestStartupChecksTheSimulatedDelayRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max int
+		strict   bool
+		wantErr  bool
+		wantLog  string
+	}{
+		{name: "valid range", min: 10, max: 100, strict: true},
+		{name: "invalid range rejected when strict", min: 100, max: 10, strict: true, wantErr: true, wantLog: "CRITICAL: invalid simulated delay range"},
+		{name: "invalid range warned about otherwise", min: 100, max: 10, strict: false, wantLog: "WARN: invalid simulated delay range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			previous := log.Writer()
+			log.SetOutput(&logs)
+			t.Cleanup(func() { log.SetOutput(previous) })
+
+			err := checkSimulateDelay(&config.Config{SimulateDelayMinMs: tt.min, SimulateDelayMaxMs: tt.max, SimulateDelayStrict: tt.strict})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkSimulateDelay = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantLog == "" && logs.Len() > 0 {
+				t.Errorf("logged %q for a valid range", logs.String())
+			}
+			if !strings.Contains(logs.String(), tt.wantLog) {
+				t.Errorf("log = %q, want it to contain %q", logs.String(), tt.wantLog)
+			}
+		})
+	}
+}
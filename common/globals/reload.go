@@ -0,0 +1,107 @@
+package globals
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"github.com/caarlos0/env/v10"
+	"github.com/joho/godotenv"
+	"github.com/narender/common/config"
+	"github.com/narender/common/featureflags"
+	commonLog "github.com/narender/common/log"
+	commontrace "github.com/narender/common/telemetry/trace"
+)
+
+// reloadableFields are the config fields Reload applies to the running process.
+// Changes to any other field are reported and ignored until the next restart.
+var reloadableFields = map[string]bool{
+	"LOG_LEVEL":                      true,
+	"OTEL_TRACE_SAMPLE_RATIO":        true,
+	"SimulateDelayEnabled":           true,
+	"SimulateDelayMinMs":             true,
+	"SimulateDelayMaxMs":             true,
+	"SimulateRandomErrorEnabled":     true,
+	"SimulateOverallErrorChance":     true,
+	"SimulateApplicationErrorWeight": true,
+	"SimulateBusinessErrorWeight":    true,
+}
+
+// Reload re-reads the configuration and applies the reloadable subset: the log
+// level, the trace sample ratio and the simulation settings. Values in .env take
+// precedence over the process environment so an edited file is picked up.
+func Reload() error {
+	current := Cfg()
+
+	if err := godotenv.Overload(); err != nil {
+		logger.Info("No .env file reloaded; using process environment", slog.Any("error", err))
+	}
+	fresh := &config.Config{}
+	if err := env.Parse(fresh); err != nil {
+		return fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	next := *current
+	nextVal := reflect.ValueOf(&next).Elem()
+	currentVal := reflect.ValueOf(current).Elem()
+	freshVal := reflect.ValueOf(fresh).Elem()
+	typ := currentVal.Type()
+
+	var applied []string
+	for i := 0; i < typ.NumField(); i++ {
+		name := typ.Field(i).Name
+		if reflect.DeepEqual(currentVal.Field(i).Interface(), freshVal.Field(i).Interface()) {
+			continue
+		}
+		if !reloadableFields[name] {
+			logger.Warn("Config change ignored; restart to apply", slog.String("field", name))
+			continue
+		}
+		nextVal.Field(i).Set(freshVal.Field(i))
+		applied = append(applied, name)
+		if typ.Field(i).Tag.Get("redact") == "true" {
+			logger.Info("Config field reloaded", slog.String("field", name))
+			continue
+		}
+		logger.Info("Config field reloaded",
+			slog.String("field", name),
+			slog.Any("old", currentVal.Field(i).Interface()),
+			slog.Any("new", freshVal.Field(i).Interface()))
+	}
+
+	if len(applied) == 0 {
+		logger.Info("Config reload found no reloadable changes")
+		return nil
+	}
+
+	if err := next.ValidateSimulateDelay(); err != nil {
+		if next.SimulateDelayStrict {
+			return err
+		}
+		logger.Warn("Reloaded simulated delay range is invalid; delays will be skipped", slog.Any("error", err))
+	}
+
+	if next.LOG_LEVEL != current.LOG_LEVEL {
+		level, err := commonLog.ParseLevel(next.LOG_LEVEL)
+		if err != nil {
+			return err
+		}
+		commonLog.SetLevel(level)
+	}
+	if next.OTEL_TRACE_SAMPLE_RATIO != current.OTEL_TRACE_SAMPLE_RATIO {
+		if err := commontrace.SetSampleRatio(next.OTEL_TRACE_SAMPLE_RATIO); err != nil {
+			return fmt.Errorf("failed to apply trace sample ratio: %w", err)
+		}
+	}
+	// Only touch flags whose config value changed, keeping runtime overrides of the rest
+	if next.SimulateDelayEnabled != current.SimulateDelayEnabled {
+		featureflags.Set(featureflags.SimulateDelay, next.SimulateDelayEnabled)
+	}
+	if next.SimulateRandomErrorEnabled != current.SimulateRandomErrorEnabled {
+		featureflags.Set(featureflags.SimulateRandomError, next.SimulateRandomErrorEnabled)
+	}
+
+	cfg.Store(&next)
+	logger.Info("Config reloaded", slog.Any("fields", applied))
+	return nil
+}
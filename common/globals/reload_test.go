@@ -0,0 +1,96 @@
+package globals
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/narender/common/config"
+	"github.com/narender/common/featureflags"
+	commonLog "github.com/narender/common/log"
+	commontrace "github.com/narender/common/telemetry/trace"
+)
+
+func TestReloadAppliesOnlyReloadableFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		check func(t *testing.T)
+	}{
+		{name: "log level", env: map[string]string{"LOG_LEVEL": "debug"}, check: func(t *testing.T) {
+			if got := commonLog.Level(); got != slog.LevelDebug {
+				t.Errorf("log level = %s, want DEBUG", got)
+			}
+			if got := Cfg().LOG_LEVEL; got != "debug" {
+				t.Errorf("LOG_LEVEL = %q, want debug", got)
+			}
+		}},
+		{name: "sample ratio", env: map[string]string{"OTEL_TRACE_SAMPLE_RATIO": "0.2"}, check: func(t *testing.T) {
+			if got := commontrace.CurrentSamplingConfig().Ratio; got != 0.2 {
+				t.Errorf("sample ratio = %v, want 0.2", got)
+			}
+		}},
+		{name: "simulation flag", env: map[string]string{"SIMULATE_DELAY_ENABLED": "true"}, check: func(t *testing.T) {
+			if !featureflags.IsEnabled(featureflags.SimulateDelay) {
+				t.Errorf("%s flag not enabled by the reload", featureflags.SimulateDelay)
+			}
+			if !Cfg().SimulateDelayEnabled {
+				t.Errorf("SimulateDelayEnabled not reloaded")
+			}
+		}},
+		{name: "other fields ignored", env: map[string]string{"PRODUCT_DATA_FILE_PATH": "/elsewhere/data.json", "LOG_LEVEL": "warn"}, check: func(t *testing.T) {
+			if got := Cfg().PRODUCT_DATA_FILE_PATH; got != "/data/products.json" {
+				t.Errorf("PRODUCT_DATA_FILE_PATH = %q, want it left at /data/products.json", got)
+			}
+			if got := commonLog.Level(); got != slog.LevelWarn {
+				t.Errorf("log level = %s, want WARN", got)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := commonLog.Level()
+			t.Cleanup(func() { commonLog.SetLevel(previous) })
+			commontrace.NewSampler(InitForTest(t, func(c *config.Config) {
+				c.PRODUCT_DATA_FILE_PATH = "/data/products.json"
+			}))
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			if err := Reload(); err != nil {
+				t.Fatalf("Reload: %v", err)
+			}
+			tt.check(t)
+		})
+	}
+}
+
+func TestReloadChecksTheSimulatedDelayRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		strict  bool
+		wantErr bool
+		wantMin int
+	}{
+		{name: "strict keeps the running range", strict: true, wantErr: true, wantMin: 10},
+		{name: "lenient applies it", strict: false, wantMin: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			InitForTest(t, func(c *config.Config) { c.SimulateDelayStrict = tt.strict })
+			t.Setenv("SIMULATE_DELAY_MIN_MS", "500")
+			t.Setenv("SIMULATE_DELAY_MAX_MS", "100")
+
+			err := Reload()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reload = %v, want error %v", err, tt.wantErr)
+			}
+			if got := Cfg().SimulateDelayMinMs; got != tt.wantMin {
+				t.Errorf("SimulateDelayMinMs = %d after reload, want %d", got, tt.wantMin)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package globals
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/caarlos0/env/v10"
+	"github.com/narender/common/config"
+	"github.com/narender/common/featureflags"
+)
+
+// InitForTest installs a configuration built from the environment defaults, with
+// overrides applied in order, and a logger that discards its output. Unlike Init it
+// does not read .env and does not start telemetry, so tests can pair it with a
+// telemetrytest.Harness. It may be called again to replace the configuration.
+func InitForTest(t testing.TB, overrides ...func(*config.Config)) *config.Config {
+	t.Helper()
+
+	current := &config.Config{}
+	if err := env.Parse(current); err != nil {
+		t.Fatalf("parse default configuration: %v", err)
+	}
+	for _, override := range overrides {
+		override(current)
+	}
+	cfg.Store(current)
+	logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	if err := featureflags.Init(current); err != nil {
+		t.Fatalf("seed feature flags: %v", err)
+	}
+	return current
+}
+
+// CaptureLogsForTest replaces the logger with one writing JSON records to the
+// returned buffer. Components take the logger when they are built, so build them
+// after calling it.
+func CaptureLogsForTest(t testing.TB) *bytes.Buffer {
+	t.Helper()
+	logs := &bytes.Buffer{}
+	logger = slog.New(slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return logs
+}
@@ -40,6 +40,7 @@ require (
 	github.com/lmittmann/tint v1.0.7
 	github.com/samber/slog-multi v1.4.0
 	go.opentelemetry.io/contrib/bridges/otelslog v0.10.0
+	go.opentelemetry.io/contrib/propagators/b3 v1.35.0
 	go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc v0.11.0
 	go.opentelemetry.io/otel/log v0.11.0
 	go.opentelemetry.io/otel/sdk/log v0.11.0
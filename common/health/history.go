@@ -0,0 +1,61 @@
+// Package health keeps a short history of readiness results so flapping
+// dependencies can be diagnosed after the fact.
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// Result is the outcome of one readiness check.
+type Result struct {
+	Time    time.Time `json:"time"`
+	Ready   bool      `json:"ready"`
+	Failing []string  `json:"failing,omitempty"` // Subsystems that failed the check
+}
+
+// History is a fixed-size ring buffer of the most recent readiness results.
+// Once full, each new result overwrites the oldest one.
+type History struct {
+	mu      sync.Mutex
+	results []Result
+	next    int
+	full    bool
+}
+
+// NewHistory creates a History holding up to capacity results; a non-positive
+// capacity is treated as 1.
+func NewHistory(capacity int) *History {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &History{results: make([]Result, capacity)}
+}
+
+// Record adds r, overwriting the oldest result when the buffer is full.
+func (h *History) Record(r Result) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.results[h.next] = r
+	h.next = (h.next + 1) % len(h.results)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Snapshot returns the recorded results, oldest first.
+func (h *History) Snapshot() []Result {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.full {
+		return append(make([]Result, 0, h.next), h.results[:h.next]...)
+	}
+	snapshot := make([]Result, 0, len(h.results))
+	snapshot = append(snapshot, h.results[h.next:]...)
+	return append(snapshot, h.results[:h.next]...)
+}
+
+// Capacity returns the maximum number of results kept.
+func (h *History) Capacity() int {
+	return len(h.results)
+}
@@ -0,0 +1,66 @@
+package health
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := func(i int) Result {
+		r := Result{Time: start.Add(time.Duration(i) * time.Second), Ready: i%2 == 0}
+		if !r.Ready {
+			r.Failing = []string{"data_file"}
+		}
+		return r
+	}
+
+	tests := []struct {
+		name         string
+		capacity     int
+		records      int
+		wantCapacity int
+		want         []int // indexes of the results expected, oldest first
+	}{
+		{name: "empty", capacity: 3, records: 0, wantCapacity: 3, want: []int{}},
+		{name: "partly filled", capacity: 3, records: 2, wantCapacity: 3, want: []int{0, 1}},
+		{name: "exactly full", capacity: 3, records: 3, wantCapacity: 3, want: []int{0, 1, 2}},
+		{name: "rolls over", capacity: 3, records: 5, wantCapacity: 3, want: []int{2, 3, 4}},
+		{name: "rolls over more than once", capacity: 3, records: 7, wantCapacity: 3, want: []int{4, 5, 6}},
+		{name: "non-positive capacity keeps one", capacity: 0, records: 2, wantCapacity: 1, want: []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHistory(tt.capacity)
+			for i := 0; i < tt.records; i++ {
+				h.Record(result(i))
+			}
+
+			want := make([]Result, 0, len(tt.want))
+			for _, i := range tt.want {
+				want = append(want, result(i))
+			}
+			if got := h.Snapshot(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Snapshot() = %+v, want %+v", got, want)
+			}
+			if got := h.Capacity(); got != tt.wantCapacity {
+				t.Errorf("Capacity() = %d, want %d", got, tt.wantCapacity)
+			}
+		})
+	}
+}
+
+func TestHistorySnapshotIsACopy(t *testing.T) {
+	h := NewHistory(2)
+	h.Record(Result{Ready: true})
+
+	snapshot := h.Snapshot()
+	snapshot[0].Ready = false
+	h.Record(Result{Ready: false})
+
+	if got := h.Snapshot(); !got[0].Ready {
+		t.Errorf("Snapshot()[0].Ready = false after changing an earlier snapshot, want true")
+	}
+}
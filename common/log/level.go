@@ -0,0 +1,30 @@
+package log
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// level is shared by every handler Init builds, so SetLevel takes effect on the
+// running logger without rebuilding it.
+var level = new(slog.LevelVar)
+
+// ParseLevel parses a level name such as "debug" or "WARN".
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.ToLower(s))); err != nil {
+		return l, fmt.Errorf("invalid log level %q: %w", s, err)
+	}
+	return l, nil
+}
+
+// SetLevel changes the minimum level of the global logger.
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// Level returns the current minimum level of the global logger.
+func Level() slog.Level {
+	return level.Level()
+}
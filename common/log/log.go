@@ -7,25 +7,28 @@ import (
 	"time"
 
 	"github.com/lmittmann/tint"
+	"github.com/narender/common/telemetry"
 	slogmulti "github.com/samber/slog-multi"
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 )
 
 var L *slog.Logger
 
-func Init(logLevelStr, environment string) error {
+// Init configures the global logger. scopeLevels optionally raises the minimum
+// exported level per scope (see ParseScopeLevels); the console output is unaffected.
+func Init(logLevelStr, environment, scopeLevels string) error {
 	if L != nil {
 		slog.Warn("Logger already initialized")
 		return nil
 	}
 
 	// Determine log level from parameter, default to Info
-	var level slog.Level = slog.LevelInfo // Default level
-	logLevelLower := strings.ToLower(logLevelStr)
-	if err := level.UnmarshalText([]byte(logLevelLower)); err != nil {
+	initialLevel, err := ParseLevel(logLevelStr)
+	if err != nil {
 		slog.Warn("Invalid log level provided, defaulting to INFO", slog.String("providedLevel", logLevelStr), slog.Any("error", err))
-		level = slog.LevelInfo // Ensure default on error
+		initialLevel = slog.LevelInfo // Ensure default on error
 	}
+	level.Set(initialLevel)
 
 	handlerOpts := &slog.HandlerOptions{
 		AddSource: true,
@@ -38,7 +41,7 @@ func Init(logLevelStr, environment string) error {
 	if isProduction {
 		slog.Info("Production environment: Configuring OTLP and Console (Tint) slog handlers.")
 
-		otlpHandler := otelslog.NewHandler("otlp_logger_placeholder")
+		otlpHandler := NewScopeLevelHandler(otelslog.NewHandler("otlp_logger_placeholder"), ParseScopeLevels(scopeLevels))
 
 		consoleHandler := tint.NewHandler(os.Stdout, &tint.Options{
 			AddSource:  handlerOpts.AddSource,
@@ -57,10 +60,11 @@ func Init(logLevelStr, environment string) error {
 		})
 	}
 
-	L = slog.New(handler)
+	// Context-carried fields (component, operation, request_id) are added to every record
+	L = slog.New(telemetry.NewContextHandler(handler))
 
 	slog.SetDefault(L)
 
-	L.Info("Logger initialized and set as default", slog.String("level", level.String()), slog.String("environment", environment))
+	L.Info("Logger initialized and set as default", slog.String("level", initialLevel.String()), slog.String("environment", environment))
 	return nil
 }
@@ -0,0 +1,80 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// ScopeAttr is the record attribute used to identify the instrumentation scope of a log.
+const ScopeAttr = "component"
+
+// ParseScopeLevels parses "scope=level" pairs separated by commas,
+// e.g. "file_database=warn,product_service=info". Malformed pairs are skipped.
+func ParseScopeLevels(spec string) map[string]slog.Level {
+	levels := make(map[string]slog.Level)
+	for _, pair := range strings.Split(spec, ",") {
+		scope, levelStr, ok := strings.Cut(pair, "=")
+		scope = strings.TrimSpace(scope)
+		if !ok || scope == "" {
+			continue
+		}
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(strings.ToLower(strings.TrimSpace(levelStr)))); err != nil {
+			slog.Warn("Invalid scope log level, ignoring", slog.String("scope", scope), slog.String("level", levelStr))
+			continue
+		}
+		levels[scope] = level
+	}
+	return levels
+}
+
+// scopeLevelHandler drops records below the minimum level configured for their scope.
+// Records without a scope, or with a scope not in the map, pass through unchanged.
+type scopeLevelHandler struct {
+	next   slog.Handler
+	levels map[string]slog.Level
+	scope  string // scope bound via Logger.With, if any
+}
+
+// NewScopeLevelHandler wraps next so each scope exports only at or above its configured level.
+func NewScopeLevelHandler(next slog.Handler, levels map[string]slog.Level) slog.Handler {
+	if len(levels) == 0 {
+		return next
+	}
+	return &scopeLevelHandler{next: next, levels: levels}
+}
+
+func (h *scopeLevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *scopeLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	scope := h.scope
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == ScopeAttr {
+			scope = a.Value.String()
+			return false
+		}
+		return true
+	})
+
+	if minLevel, ok := h.levels[scope]; ok && r.Level < minLevel {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *scopeLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	scope := h.scope
+	for _, a := range attrs {
+		if a.Key == ScopeAttr {
+			scope = a.Value.String()
+		}
+	}
+	return &scopeLevelHandler{next: h.next.WithAttrs(attrs), levels: h.levels, scope: scope}
+}
+
+func (h *scopeLevelHandler) WithGroup(name string) slog.Handler {
+	return &scopeLevelHandler{next: h.next.WithGroup(name), levels: h.levels, scope: h.scope}
+}
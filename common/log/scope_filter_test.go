@@ -0,0 +1,85 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/narender/common/telemetry/telemetrytest"
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+)
+
+var harness *telemetrytest.Harness
+
+func TestMain(m *testing.M) {
+	harness = telemetrytest.NewTestHarness()
+	code := m.Run()
+	harness.Teardown()
+	os.Exit(code)
+}
+
+func TestScopeLevelHandlerFiltersExportedLogs(t *testing.T) {
+	levels := ParseScopeLevels("file_database=warn,product_service=info")
+	logger := slog.New(NewScopeLevelHandler(otelslog.NewHandler("scope_filter_test"), levels))
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		log        func()
+		wantExport bool
+	}{
+		{name: "debug from a warn scope", log: func() {
+			logger.With(slog.String(ScopeAttr, "file_database")).DebugContext(ctx, "Database file access initiated")
+		}},
+		{name: "info from a warn scope", log: func() {
+			logger.InfoContext(ctx, "Database data read successfully", slog.String(ScopeAttr, "file_database"))
+		}},
+		{name: "warn from a warn scope", log: func() {
+			logger.With(slog.String(ScopeAttr, "file_database")).WarnContext(ctx, "Slow database file operation")
+		}, wantExport: true},
+		{name: "info from an info scope", log: func() {
+			logger.InfoContext(ctx, "Processing purchase request", slog.String(ScopeAttr, "product_service"))
+		}, wantExport: true},
+		{name: "debug from an info scope", log: func() {
+			logger.DebugContext(ctx, "Purchase details", slog.String(ScopeAttr, "product_service"))
+		}},
+		{name: "debug from an unlisted scope", log: func() {
+			logger.DebugContext(ctx, "Processing purchase details", slog.String(ScopeAttr, "product_handler"))
+		}, wantExport: true},
+		{name: "record scope overrides the bound scope", log: func() {
+			logger.With(slog.String(ScopeAttr, "product_handler")).InfoContext(ctx, "Reading file", slog.String(ScopeAttr, "file_database"))
+		}},
+		{name: "scope kept inside a group", log: func() {
+			logger.With(slog.String(ScopeAttr, "file_database")).WithGroup("request").InfoContext(ctx, "Reading file")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			harness.Reset()
+			tt.log()
+			if exported := len(harness.LogRecords()) == 1; exported != tt.wantExport {
+				t.Errorf("exported = %v, want %v", exported, tt.wantExport)
+			}
+		})
+	}
+}
+
+func TestParseScopeLevels(t *testing.T) {
+	tests := []struct {
+		spec string
+		want map[string]slog.Level
+	}{
+		{spec: "", want: map[string]slog.Level{}},
+		{spec: "file_database=warn", want: map[string]slog.Level{"file_database": slog.LevelWarn}},
+		{spec: " file_database = WARN , product_service=info ", want: map[string]slog.Level{"file_database": slog.LevelWarn, "product_service": slog.LevelInfo}},
+		{spec: "file_database=loud,=info,product_service", want: map[string]slog.Level{}},
+	}
+	for _, tt := range tests {
+		if got := ParseScopeLevels(tt.spec); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseScopeLevels(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
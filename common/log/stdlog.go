@@ -0,0 +1,55 @@
+package log
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"strings"
+)
+
+// stdLogPrefixes maps the severity prefixes used in setup diagnostics
+// (e.g. "ERROR: OTLP Trace exporter setup failed") to slog levels.
+var stdLogPrefixes = []struct {
+	prefix string
+	level  slog.Level
+}{
+	{"CRITICAL:", slog.LevelError},
+	{"ERROR:", slog.LevelError},
+	{"WARN:", slog.LevelWarn},
+	{"WARNING:", slog.LevelWarn},
+	{"Info:", slog.LevelInfo},
+	{"INFO:", slog.LevelInfo},
+}
+
+// stdLogWriter turns lines written through the standard log package into
+// structured records on a slog logger.
+type stdLogWriter struct {
+	logger *slog.Logger
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		level := slog.LevelInfo
+		for _, known := range stdLogPrefixes {
+			if rest, ok := strings.CutPrefix(line, known.prefix); ok {
+				level = known.level
+				line = strings.TrimSpace(rest)
+				break
+			}
+		}
+		w.logger.Log(context.Background(), level, line, slog.String("log.source", "stdlib"))
+	}
+	return len(p), nil
+}
+
+// RedirectStdLog sends output of the standard log package (log.Printf and friends,
+// used for telemetry setup diagnostics) to logger, so it reaches the same handlers,
+// including OTLP, with a level taken from its ERROR:/WARN: prefix.
+func RedirectStdLog(logger *slog.Logger) {
+	log.SetFlags(0)
+	log.SetOutput(&stdLogWriter{logger: logger})
+}
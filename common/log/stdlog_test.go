@@ -0,0 +1,81 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stdLogRecord is the part of a redirected line's JSON record the tests check.
+type stdLogRecord struct {
+	Level  string `json:"level"`
+	Msg    string `json:"msg"`
+	Source string `json:"log.source"`
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func()
+		want  []stdLogRecord
+	}{
+		{
+			name:  "unprefixed line",
+			write: func() { log.Println("OTLP endpoint configured") },
+			want:  []stdLogRecord{{Level: "INFO", Msg: "OTLP endpoint configured", Source: "stdlib"}},
+		},
+		{
+			name:  "error prefix",
+			write: func() { log.Printf("ERROR: OTLP Trace exporter setup failed: %v", "dial timeout") },
+			want:  []stdLogRecord{{Level: "ERROR", Msg: "OTLP Trace exporter setup failed: dial timeout", Source: "stdlib"}},
+		},
+		{
+			name:  "critical prefix",
+			write: func() { log.Print("CRITICAL: no exporter available") },
+			want:  []stdLogRecord{{Level: "ERROR", Msg: "no exporter available", Source: "stdlib"}},
+		},
+		{
+			name:  "warn prefix",
+			write: func() { log.Print("WARN: falling back to stdout") },
+			want:  []stdLogRecord{{Level: "WARN", Msg: "falling back to stdout", Source: "stdlib"}},
+		},
+		{
+			name:  "multi-line write",
+			write: func() { log.Print("WARNING: first\n\nInfo: second") },
+			want: []stdLogRecord{
+				{Level: "WARN", Msg: "first", Source: "stdlib"},
+				{Level: "INFO", Msg: "second", Source: "stdlib"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, output := log.Flags(), log.Writer()
+			t.Cleanup(func() {
+				log.SetFlags(flags)
+				log.SetOutput(output)
+			})
+			var buf bytes.Buffer
+			RedirectStdLog(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+			tt.write()
+
+			var got []stdLogRecord
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				var record stdLogRecord
+				if err := json.Unmarshal([]byte(line), &record); err != nil {
+					t.Fatalf("output is not a JSON record: %q: %v", line, err)
+				}
+				got = append(got, record)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("records = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
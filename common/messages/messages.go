@@ -0,0 +1,157 @@
+// Package messages holds the client-facing error message catalog, keyed by error
+// code and locale. English is the fallback; other locales only replace the message
+// when a translation exists for the code.
+package messages
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// DefaultLocale is used when the client asks for nothing we can serve.
+const DefaultLocale = "en"
+
+// catalog maps error code to locale to message. English entries are generic
+// versions of the messages the service produces; the original, more specific
+// English message is kept when the response is served in English.
+var catalog = map[string]map[string]string{
+	apierrors.ErrCodeProductNotFound: {
+		"en": "The requested product was not found.",
+		"es": "No se encontró el producto solicitado.",
+		"de": "Das angeforderte Produkt wurde nicht gefunden.",
+		"fr": "Le produit demandé est introuvable.",
+	},
+	apierrors.ErrCodeInsufficientStock: {
+		"en": "There is not enough stock to complete the purchase.",
+		"es": "No hay suficiente stock para completar la compra.",
+		"de": "Der Lagerbestand reicht für diesen Kauf nicht aus.",
+		"fr": "Le stock est insuffisant pour finaliser l'achat.",
+	},
+	apierrors.ErrCodeInvalidProductData: {
+		"en": "The product data is invalid.",
+		"es": "Los datos del producto no son válidos.",
+		"de": "Die Produktdaten sind ungültig.",
+		"fr": "Les données du produit ne sont pas valides.",
+	},
+	apierrors.ErrCodeOrderLimitExceeded: {
+		"en": "The order exceeds the allowed quantity.",
+		"es": "El pedido supera la cantidad permitida.",
+		"de": "Die Bestellung überschreitet die erlaubte Menge.",
+		"fr": "La commande dépasse la quantité autorisée.",
+	},
+	apierrors.ErrCodePriceMismatch: {
+		"en": "The price has changed since it was displayed.",
+		"es": "El precio ha cambiado desde que se mostró.",
+		"de": "Der Preis hat sich seit der Anzeige geändert.",
+		"fr": "Le prix a changé depuis son affichage.",
+	},
+	apierrors.ErrCodeConflict: {
+		"en": "The product was changed by another request. Please retry.",
+		"es": "Otra solicitud modificó el producto. Vuelva a intentarlo.",
+		"de": "Das Produkt wurde durch eine andere Anfrage geändert. Bitte erneut versuchen.",
+		"fr": "Le produit a été modifié par une autre requête. Veuillez réessayer.",
+	},
+	apierrors.ErrCodeRequestValidation: {
+		"en": "The request is invalid.",
+		"es": "La solicitud no es válida.",
+		"de": "Die Anfrage ist ungültig.",
+		"fr": "La requête n'est pas valide.",
+	},
+	apierrors.ErrCodeMalformedData: {
+		"en": "The request data is malformed.",
+		"es": "Los datos de la solicitud tienen un formato incorrecto.",
+		"de": "Die Anfragedaten sind fehlerhaft.",
+		"fr": "Les données de la requête sont mal formées.",
+	},
+	apierrors.ErrCodeRequestTimeout: {
+		"en": "The request timed out.",
+		"es": "La solicitud excedió el tiempo de espera.",
+		"de": "Die Anfrage hat das Zeitlimit überschritten.",
+		"fr": "Le délai de la requête a expiré.",
+	},
+	apierrors.ErrCodeResourceConstraint: {
+		"en": "Too many requests. Please try again later.",
+		"es": "Demasiadas solicitudes. Inténtelo más tarde.",
+		"de": "Zu viele Anfragen. Bitte später erneut versuchen.",
+		"fr": "Trop de requêtes. Veuillez réessayer plus tard.",
+	},
+	apierrors.ErrCodeServiceUnavailable: {
+		"en": "The service is temporarily unavailable.",
+		"es": "El servicio no está disponible temporalmente.",
+		"de": "Der Dienst ist vorübergehend nicht verfügbar.",
+		"fr": "Le service est temporairement indisponible.",
+	},
+	apierrors.ErrCodeUnknown: {
+		"en": "An unexpected error occurred. Please try again later.",
+		"es": "Se produjo un error inesperado. Inténtelo más tarde.",
+		"de": "Ein unerwarteter Fehler ist aufgetreten. Bitte später erneut versuchen.",
+		"fr": "Une erreur inattendue s'est produite. Veuillez réessayer plus tard.",
+	},
+}
+
+// Lookup returns the catalog message for code in locale, falling back to English.
+// ok is false when the code has no catalog entry at all.
+func Lookup(code, locale string) (message string, ok bool) {
+	byLocale, found := catalog[code]
+	if !found {
+		return "", false
+	}
+	if message, found = byLocale[locale]; found {
+		return message, true
+	}
+	message, ok = byLocale[DefaultLocale]
+	return message, ok
+}
+
+// Negotiate picks the best locale for an Accept-Language header from the enabled
+// locales, honouring q-values and matching "es-MX" against "es". It returns
+// DefaultLocale when nothing matches.
+func Negotiate(acceptLanguage string, enabled []string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		base, _, _ := strings.Cut(c.tag, "-")
+		for _, locale := range enabled {
+			if locale == c.tag || locale == base {
+				return locale
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+// ParseLocales splits a comma-separated locale list such as "en,es,de".
+func ParseLocales(raw string) []string {
+	var locales []string
+	for _, part := range strings.Split(raw, ",") {
+		if locale := strings.ToLower(strings.TrimSpace(part)); locale != "" {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
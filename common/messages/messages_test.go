@@ -0,0 +1,76 @@
+package messages
+
+import (
+	"reflect"
+	"testing"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+func TestNegotiate(t *testing.T) {
+	enabled := []string{"en", "es", "de"}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "es", want: "es"},
+		{header: "es-MX", want: "es"},
+		{header: "DE-at", want: "de"},
+		{header: "fr, de;q=0.5", want: "de"},
+		{header: "en;q=0.3, es;q=0.8", want: "es"},
+		{header: "de;q=0, es;q=0.1", want: "es"},
+		{header: "ja, zh", want: DefaultLocale},
+		{header: "", want: DefaultLocale},
+		{header: "es;q=abc", want: "es"},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header, enabled); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		code, locale string
+		want         string
+		wantOK       bool
+	}{
+		{code: apierrors.ErrCodeProductNotFound, locale: "es", want: "No se encontró el producto solicitado.", wantOK: true},
+		{code: apierrors.ErrCodeProductNotFound, locale: "fr", want: "Le produit demandé est introuvable.", wantOK: true},
+		{code: apierrors.ErrCodeProductNotFound, locale: "ja", want: "The requested product was not found.", wantOK: true},
+		{code: apierrors.ErrCodeDatabaseAccess, locale: "es", wantOK: false},
+	}
+	for _, tt := range tests {
+		got, ok := Lookup(tt.code, tt.locale)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Lookup(%s, %s) = %q, %v, want %q, %v", tt.code, tt.locale, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCatalogCoversTheDefaultLocales(t *testing.T) {
+	for code, byLocale := range catalog {
+		for _, locale := range []string{"en", "es", "de", "fr"} {
+			if byLocale[locale] == "" {
+				t.Errorf("%s has no %s message", code, locale)
+			}
+		}
+	}
+}
+
+func TestParseLocales(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{raw: "en,es,de,fr", want: []string{"en", "es", "de", "fr"}},
+		{raw: " EN , es,,", want: []string{"en", "es"}},
+		{raw: "", want: nil},
+	}
+	for _, tt := range tests {
+		if got := ParseLocales(tt.raw); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseLocales(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/globals"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AccessLogMiddleware emits one structured line per request with method, route,
+// status, duration and trace ID. Requests to sampled routes are logged at the
+// configured rate; error responses are always logged. With AccessLogSampledTraceIDOnly
+// the trace ID is replaced by trace_sampled=false when the trace was not sampled.
+// Register it after otelfiber so the trace ID is available.
+func AccessLogMiddleware() fiber.Handler {
+	cfg := globals.Cfg()
+	logger := globals.Logger()
+
+	level := slog.LevelInfo
+	if err := level.UnmarshalText([]byte(strings.ToLower(cfg.AccessLogLevel))); err != nil {
+		logger.Warn("Invalid access log level, defaulting to INFO", slog.String("level", cfg.AccessLogLevel))
+		level = slog.LevelInfo
+	}
+
+	sampledRoutes := make(map[string]bool)
+	for _, route := range strings.Split(cfg.AccessLogSampledRoutes, ",") {
+		if route = strings.TrimSpace(route); route != "" {
+			sampledRoutes[route] = true
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		if !cfg.AccessLogEnabled {
+			return c.Next()
+		}
+
+		start := time.Now()
+		// Run the error handler here so the logged status is the one sent to the client
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().Config().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(http.StatusInternalServerError)
+			}
+		}
+		duration := time.Since(start)
+
+		status := c.Response().StatusCode()
+		route := c.Route().Path
+		if status < http.StatusBadRequest && sampledRoutes[route] && rand.Float64() >= cfg.AccessLogSampleRate {
+			return nil
+		}
+
+		ctx := c.UserContext()
+		attrs := []slog.Attr{
+			slog.String("component", "access_log"),
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.String("route", route),
+			slog.Int("status", status),
+			slog.Int64("duration_ms", duration.Milliseconds()),
+		}
+		spanCtx := trace.SpanContextFromContext(ctx)
+		if cfg.AccessLogSampledTraceIDOnly && !spanCtx.IsSampled() {
+			attrs = append(attrs, slog.Bool("trace_sampled", false))
+		} else {
+			attrs = append(attrs, slog.String("trace_id", spanCtx.TraceID().String()))
+		}
+		logger.LogAttrs(ctx, level, "Access", attrs...)
+		return nil
+	}
+}
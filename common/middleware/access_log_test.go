@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/config"
+	"github.com/narender/common/globals"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// accessLogApp returns an app with AccessLogMiddleware in front of GET /products,
+// GET /products/:name and GET /fail (a 500), and the buffer its logs go to.
+func accessLogApp(t *testing.T, overrides ...func(*config.Config)) (*fiber.App, *bytes.Buffer) {
+	t.Helper()
+	var logs *bytes.Buffer
+	app := newTestApp(t, func(app *fiber.App) {
+		logs = globals.CaptureLogsForTest(t)
+		app.Use(AccessLogMiddleware())
+		app.Get("/products", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+		app.Get("/products/:name", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+		app.Get("/fail", func(c *fiber.Ctx) error { return fiber.ErrInternalServerError })
+	}, append([]func(*config.Config){func(c *config.Config) {
+		c.AccessLogSampledRoutes = "/products"
+	}}, overrides...)...)
+	return app, logs
+}
+
+// accessLines decodes the access-log records in logs.
+func accessLines(t *testing.T, logs *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range bytes.Split(logs.Bytes(), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		if record["component"] == "access_log" {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func TestAccessLogWritesOneRecordPerRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		level      string
+		target     string
+		wantRoute  string
+		wantStatus float64
+		wantLevel  string
+	}{
+		{name: "success", level: "info", target: "/products/Coffee%20Mug", wantRoute: "/products/:name", wantStatus: 200, wantLevel: "INFO"},
+		{name: "server error", level: "info", target: "/fail", wantRoute: "/fail", wantStatus: 500, wantLevel: "INFO"},
+		{name: "configured level", level: "debug", target: "/products/Teapot", wantRoute: "/products/:name", wantStatus: 200, wantLevel: "DEBUG"},
+		{name: "invalid level falls back to info", level: "loud", target: "/products/Teapot", wantRoute: "/products/:name", wantStatus: 200, wantLevel: "INFO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, logs := accessLogApp(t, func(c *config.Config) { c.AccessLogLevel = tt.level })
+
+			send(t, app, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			records := accessLines(t, logs)
+			if len(records) != 1 {
+				t.Fatalf("wrote %d access-log records, want 1", len(records))
+			}
+			record := records[0]
+			want := map[string]any{
+				"level":    tt.wantLevel,
+				"msg":      "Access",
+				"method":   http.MethodGet,
+				"path":     tt.target,
+				"route":    tt.wantRoute,
+				"status":   tt.wantStatus,
+				"trace_id": requestSpan(t).SpanContext.TraceID().String(),
+			}
+			for key, value := range want {
+				if record[key] != value {
+					t.Errorf("%s = %v, want %v", key, record[key], value)
+				}
+			}
+			if _, ok := record["duration_ms"].(float64); !ok {
+				t.Errorf("duration_ms = %v, want a number", record["duration_ms"])
+			}
+		})
+	}
+}
+
+func TestAccessLogDisabled(t *testing.T) {
+	app, logs := accessLogApp(t, func(c *config.Config) { c.AccessLogEnabled = false })
+
+	send(t, app, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	if records := accessLines(t, logs); len(records) != 0 {
+		t.Errorf("wrote %d access-log records with the access log disabled, want none", len(records))
+	}
+}
+
+func TestAccessLogSamplingReducesVolume(t *testing.T) {
+	const requests = 400
+
+	tests := []struct {
+		name     string
+		rate     float64
+		target   string
+		min, max int
+	}{
+		{name: "sampled route at full rate", rate: 1, target: "/products", min: requests, max: requests},
+		{name: "sampled route never logged", rate: 0, target: "/products", min: 0, max: 0},
+		{name: "sampled route at half rate", rate: 0.5, target: "/products", min: requests / 4, max: requests * 3 / 4},
+		{name: "unsampled route always logged", rate: 0, target: "/products/Teapot", min: requests, max: requests},
+		{name: "errors always logged", rate: 0, target: "/fail", min: requests, max: requests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, logs := accessLogApp(t, func(c *config.Config) { c.AccessLogSampleRate = tt.rate })
+
+			for i := 0; i < requests; i++ {
+				send(t, app, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			}
+
+			if got := len(accessLines(t, logs)); got < tt.min || got > tt.max {
+				t.Errorf("wrote %d access-log records for %d requests, want between %d and %d", got, requests, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestAccessLogSampledTraceIDOnly(t *testing.T) {
+	traceID := trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	tests := []struct {
+		name        string
+		sampledOnly bool
+		sampled     bool
+		wantTraceID bool
+	}{
+		{name: "sampled trace", sampledOnly: true, sampled: true, wantTraceID: true},
+		{name: "unsampled trace", sampledOnly: true, sampled: false},
+		{name: "unsampled trace with the option off", sampledOnly: false, sampled: false, wantTraceID: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var flags trace.TraceFlags
+			if tt.sampled {
+				flags = trace.FlagsSampled
+			}
+			var logs *bytes.Buffer
+			app := newTestApp(t, func(app *fiber.App) {
+				logs = globals.CaptureLogsForTest(t)
+				// Stands in for otelfiber with a sampler that made this decision
+				app.Use(func(c *fiber.Ctx) error {
+					c.SetUserContext(trace.ContextWithSpanContext(c.UserContext(), trace.NewSpanContext(trace.SpanContextConfig{
+						TraceID: traceID, SpanID: trace.SpanID{1}, TraceFlags: flags,
+					})))
+					return c.Next()
+				})
+				app.Use(AccessLogMiddleware())
+				app.Get("/products", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+			}, func(c *config.Config) { c.AccessLogSampledTraceIDOnly = tt.sampledOnly })
+
+			send(t, app, httptest.NewRequest(http.MethodGet, "/products", nil))
+
+			records := accessLines(t, logs)
+			if len(records) != 1 {
+				t.Fatalf("wrote %d access-log records, want 1", len(records))
+			}
+			record := records[0]
+			if tt.wantTraceID {
+				if record["trace_id"] != traceID.String() {
+					t.Errorf("trace_id = %v, want %s", record["trace_id"], traceID)
+				}
+				if _, ok := record["trace_sampled"]; ok {
+					t.Errorf("trace_sampled = %v, want it absent", record["trace_sampled"])
+				}
+				return
+			}
+			if _, ok := record["trace_id"]; ok {
+				t.Errorf("trace_id = %v for an unsampled trace, want it omitted", record["trace_id"])
+			}
+			if record["trace_sampled"] != false {
+				t.Errorf("trace_sampled = %v, want false", record["trace_sampled"])
+			}
+		})
+	}
+}
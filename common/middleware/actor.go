@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ActorHeader carries the identity of the caller performing an operation.
+const ActorHeader = "X-Actor"
+
+// AttrEndUserID is the span attribute recording the acting user.
+const AttrEndUserID = "enduser.id"
+
+type actorKey struct{}
+
+// ActorMiddleware reads the optional X-Actor header, stores it on the request
+// context and records it on the request span. Requests without it stay anonymous.
+func ActorMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if actor := c.Get(ActorHeader); actor != "" {
+			ctx := context.WithValue(c.UserContext(), actorKey{}, actor)
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String(AttrEndUserID, actor))
+			c.SetUserContext(ctx)
+		}
+		return c.Next()
+	}
+}
+
+// ActorFromContext returns the actor stored by ActorMiddleware, or "anonymous".
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return "anonymous"
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestActorMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		wantActor string
+		wantAttr  string
+	}{
+		{name: "actor header", header: "alice", wantActor: "alice", wantAttr: "alice"},
+		{name: "no header stays anonymous", wantActor: "anonymous"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actor string
+			app := newTestApp(t, func(app *fiber.App) {
+				app.Use(ActorMiddleware())
+				app.Post("/products/buy", func(c *fiber.Ctx) error {
+					actor = ActorFromContext(c.UserContext())
+					return c.SendStatus(http.StatusOK)
+				})
+			})
+			req := httptest.NewRequest(http.MethodPost, "/products/buy", nil)
+			if tt.header != "" {
+				req.Header.Set(ActorHeader, tt.header)
+			}
+
+			send(t, app, req)
+
+			if actor != tt.wantActor {
+				t.Errorf("ActorFromContext = %q, want %q", actor, tt.wantActor)
+			}
+			if got := spanAttribute(requestSpan(t), AttrEndUserID); got != tt.wantAttr {
+				t.Errorf("span %s = %q, want %q", AttrEndUserID, got, tt.wantAttr)
+			}
+		})
+	}
+}
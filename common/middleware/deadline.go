@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/globals"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+const (
+	// DeadlineHeader carries the caller's remaining time budget in milliseconds so
+	// the service can stop work whose result would be discarded.
+	DeadlineHeader = "X-Deadline-Ms"
+	// AttrDeadlineMs records the caller's remaining budget applied to the request.
+	AttrDeadlineMs = "request.deadline_ms"
+)
+
+// DeadlineMiddleware applies the caller's DeadlineHeader as a deadline on the
+// request context, so work stops once the caller has given up. Requests whose budget
+// is already spent are rejected before any work is done. Missing or malformed headers
+// leave the context unchanged.
+func DeadlineMiddleware() fiber.Handler {
+	enabled := globals.Cfg().DeadlinePropagationEnabled
+	return func(c *fiber.Ctx) error {
+		header := c.Get(DeadlineHeader)
+		if !enabled || header == "" {
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+		remainingMs, err := strconv.ParseInt(header, 10, 64)
+		if err != nil {
+			globals.Logger().DebugContext(ctx, "Ignoring malformed deadline header",
+				slog.String("component", "deadline_middleware"),
+				slog.String("header", header))
+			return c.Next()
+		}
+
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Int64(AttrDeadlineMs, remainingMs))
+		if remainingMs <= 0 {
+			return apierrors.NewApplicationError(
+				apierrors.ErrCodeRequestTimeout,
+				"The caller's deadline expired before the request was processed",
+				nil)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, time.Duration(remainingMs)*time.Millisecond)
+		defer cancel()
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/config"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+func TestDeadlineMiddleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       string
+		disabled     bool
+		wantDeadline time.Duration // 0 expects no deadline
+		wantCode     int
+		wantAttr     string
+	}{
+		{name: "deadline applied", header: "1500", wantDeadline: 1500 * time.Millisecond, wantCode: http.StatusOK, wantAttr: "1500"},
+		{name: "no header", wantCode: http.StatusOK},
+		{name: "malformed header ignored", header: "soon", wantCode: http.StatusOK},
+		{name: "propagation disabled", header: "1500", disabled: true, wantCode: http.StatusOK},
+		{name: "expired budget rejected", header: "0", wantCode: http.StatusRequestTimeout, wantAttr: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadline time.Time
+			var hasDeadline, ran bool
+			app := newTestApp(t, func(app *fiber.App) {
+				app.Use(DeadlineMiddleware())
+				app.Get("/products", func(c *fiber.Ctx) error {
+					ran = true
+					deadline, hasDeadline = c.UserContext().Deadline()
+					return c.SendStatus(http.StatusOK)
+				})
+			}, func(c *config.Config) { c.DeadlinePropagationEnabled = !tt.disabled })
+			req := httptest.NewRequest(http.MethodGet, "/products", nil)
+			if tt.header != "" {
+				req.Header.Set(DeadlineHeader, tt.header)
+			}
+
+			sent := time.Now()
+			resp, body := send(t, app, req)
+			elapsed := time.Since(sent)
+
+			if resp.StatusCode != tt.wantCode {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusRequestTimeout {
+				if ran || body.Error.Code != apierrors.ErrCodeRequestTimeout {
+					t.Errorf("handler ran = %v, error code = %s; want rejection with %s before the handler", ran, body.Error.Code, apierrors.ErrCodeRequestTimeout)
+				}
+			}
+			if hasDeadline != (tt.wantDeadline > 0) {
+				t.Fatalf("handler context has deadline = %v, want %v", hasDeadline, tt.wantDeadline > 0)
+			}
+			if hasDeadline {
+				if remaining := deadline.Sub(sent); remaining < tt.wantDeadline || remaining > tt.wantDeadline+elapsed {
+					t.Errorf("handler deadline in %s, want about %s", remaining, tt.wantDeadline)
+				}
+			}
+			if got := spanAttribute(requestSpan(t), AttrDeadlineMs); got != tt.wantAttr {
+				t.Errorf("span %s = %q, want %q", AttrDeadlineMs, got, tt.wantAttr)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/globals"
+	"github.com/narender/common/telemetry/metric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// Bodies larger than this are not parsed for error span attributes
+	maxErrorContextBodyBytes = 4096
+	// Longer values are truncated so one field cannot bloat the span
+	maxErrorContextValueLen = 256
+)
+
+// recordRequestContext adds the route, query params and top-level scalar JSON body
+// fields of the request to its span, so an error trace shows what was asked for
+// without a log join. Keys matching the denylist are skipped.
+func recordRequestContext(c *fiber.Ctx) {
+	cfg := globals.Cfg()
+	if !cfg.ErrorSpanRequestContext {
+		return
+	}
+	span := trace.SpanFromContext(c.UserContext())
+	if !span.IsRecording() {
+		return
+	}
+
+	denylist := parseDenylist(cfg.ErrorSpanDenylist)
+	attrs := []attribute.KeyValue{attribute.String(metric.AttrHTTPRoute, c.Route().Path)}
+
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		if !denied(string(key), denylist) {
+			attrs = append(attrs, attribute.String("request.query."+string(key), truncateValue(string(value))))
+		}
+	})
+
+	body := c.Body()
+	if len(body) > 0 && len(body) <= maxErrorContextBodyBytes && strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(body, &fields); err == nil {
+			for key, value := range fields {
+				if denied(key, denylist) {
+					continue
+				}
+				// Nested objects, arrays and nulls are left out to keep attributes flat
+				switch v := value.(type) {
+				case string:
+					attrs = append(attrs, attribute.String("request.body."+key, truncateValue(v)))
+				case float64:
+					attrs = append(attrs, attribute.Float64("request.body."+key, v))
+				case bool:
+					attrs = append(attrs, attribute.Bool("request.body."+key, v))
+				}
+			}
+		}
+	}
+
+	span.SetAttributes(attrs...)
+}
+
+func parseDenylist(spec string) []string {
+	var words []string
+	for _, word := range strings.Split(spec, ",") {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// denied reports whether key contains any denylisted word, ignoring case.
+func denied(key string, denylist []string) bool {
+	key = strings.ToLower(key)
+	for _, word := range denylist {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateValue(v string) string {
+	if len(v) <= maxErrorContextValueLen {
+		return v
+	}
+	return v[:maxErrorContextValueLen] + "..."
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/config"
+	"github.com/narender/common/telemetry/metric"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+func TestErrorSpansCarryRequestContext(t *testing.T) {
+	outOfStock := apierrors.NewBusinessError(apierrors.ErrCodeInsufficientStock, "not enough stock", nil)
+	tests := []struct {
+		name     string
+		target   string
+		body     string
+		fail     bool
+		disabled bool
+		want     map[string]string
+		absent   []string
+	}{
+		{name: "failed buy", target: "/products/buy?dryRun=true", body: `{"name": "Coffee Mug", "quantity": 3, "gift": false}`, fail: true,
+			want: map[string]string{metric.AttrHTTPRoute: "/products/buy", "request.body.name": "Coffee Mug", "request.body.quantity": "3",
+				"request.body.gift": "false", "request.query.dryRun": "true"}},
+		{name: "denylisted keys skipped", target: "/products/buy?token=abc", body: `{"name": "Coffee Mug", "Email": "a@b.c", "cardNumber": "4111"}`, fail: true,
+			want:   map[string]string{"request.body.name": "Coffee Mug"},
+			absent: []string{"request.body.Email", "request.body.cardNumber", "request.query.token"}},
+		{name: "nested values skipped", target: "/products/buy", body: `{"name": "Coffee Mug", "items": [1, 2], "meta": {"a": 1}, "note": null}`, fail: true,
+			want:   map[string]string{"request.body.name": "Coffee Mug"},
+			absent: []string{"request.body.items", "request.body.meta", "request.body.note"}},
+		{name: "long values truncated", target: "/products/buy", body: `{"name": "` + strings.Repeat("x", 300) + `"}`, fail: true,
+			want: map[string]string{"request.body.name": strings.Repeat("x", 256) + "..."}},
+		{name: "oversized body not parsed", target: "/products/buy", body: `{"name": "Coffee Mug", "pad": "` + strings.Repeat("x", 4096) + `"}`, fail: true,
+			want:   map[string]string{metric.AttrHTTPRoute: "/products/buy"},
+			absent: []string{"request.body.name"}},
+		{name: "successful request", target: "/products/buy", body: `{"name": "Coffee Mug", "quantity": 3}`,
+			absent: []string{metric.AttrHTTPRoute, "request.body.name", "request.body.quantity"}},
+		{name: "disabled", target: "/products/buy", body: `{"name": "Coffee Mug", "quantity": 3}`, fail: true, disabled: true,
+			absent: []string{"request.body.name", "request.body.quantity"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, func(app *fiber.App) {
+				app.Post("/products/buy", func(c *fiber.Ctx) error {
+					if tt.fail {
+						return outOfStock
+					}
+					return c.SendStatus(http.StatusOK)
+				})
+			}, func(c *config.Config) {
+				c.ErrorSpanRequestContext = !tt.disabled
+			})
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			send(t, app, req)
+
+			span := requestSpan(t)
+			for key, want := range tt.want {
+				if got := spanAttribute(span, key); got != want {
+					t.Errorf("span %s = %q, want %q", key, got, want)
+				}
+			}
+			for _, key := range tt.absent {
+				if got := spanAttribute(span, key); got != "" {
+					t.Errorf("span %s = %q, want it left out", key, got)
+				}
+			}
+		})
+	}
+}
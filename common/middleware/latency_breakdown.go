@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/globals"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// breakdownLayers fixes the order of the latency breakdown attributes and log fields.
+var breakdownLayers = []string{
+	commontrace.LayerHandler,
+	commontrace.LayerService,
+	commontrace.LayerRepository,
+	commontrace.LayerDatabase,
+	commontrace.LayerDownstream,
+}
+
+// LatencyBreakdownMiddleware summarises where a request spent its time. Each layer's
+// self time (handler, service, repo, db, downstream) is set on the request span as
+// latency.<layer>_ms and logged at debug level. It must run after otelfiber.
+func LatencyBreakdownMiddleware() fiber.Handler {
+	logger := globals.Logger()
+
+	return func(c *fiber.Ctx) error {
+		ctx, breakdown := commontrace.WithLatencyBreakdown(c.UserContext())
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		self := breakdown.SelfTimes()
+		attrs := make([]attribute.KeyValue, 0, len(breakdownLayers))
+		logAttrs := make([]any, 0, len(breakdownLayers)+1)
+		logAttrs = append(logAttrs, slog.String("route", c.Route().Path))
+		for _, layer := range breakdownLayers {
+			ms := float64(self[layer].Microseconds()) / 1000.0
+			attrs = append(attrs, attribute.Float64("latency."+layer+"_ms", ms))
+			logAttrs = append(logAttrs, slog.Float64(layer+"_ms", ms))
+		}
+		trace.SpanFromContext(ctx).SetAttributes(attrs...)
+		logger.DebugContext(ctx, "Request latency breakdown", logAttrs...)
+
+		return err
+	}
+}
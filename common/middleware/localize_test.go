@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/narender/common/config"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+func TestErrorHandlerLocalizesMessages(t *testing.T) {
+	notFound := apierrors.NewBusinessError(apierrors.ErrCodeProductNotFound, "Product 'Teapot' not found", nil)
+	tests := []struct {
+		name           string
+		err            error
+		acceptLanguage string
+		locales        string
+		wantMessage    string
+		wantLocale     string
+	}{
+		{name: "supported locale", err: notFound, acceptLanguage: "es-ES,es;q=0.9", locales: "en,es,de,fr",
+			wantMessage: "No se encontró el producto solicitado.", wantLocale: "es"},
+		{name: "english keeps the specific message", err: notFound, acceptLanguage: "en-GB", locales: "en,es,de,fr",
+			wantMessage: "Product 'Teapot' not found", wantLocale: "en"},
+		{name: "unsupported locale falls back to english", err: notFound, acceptLanguage: "ja", locales: "en,es,de,fr",
+			wantMessage: "Product 'Teapot' not found", wantLocale: "en"},
+		{name: "locale not enabled", err: notFound, acceptLanguage: "de", locales: "en,es",
+			wantMessage: "Product 'Teapot' not found", wantLocale: "en"},
+		{name: "code without a translation", err: apierrors.NewApplicationError(apierrors.ErrCodeDatabaseAccess, "read failed", nil),
+			acceptLanguage: "fr", locales: "en,es,de,fr", wantMessage: "read failed", wantLocale: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, failingRoute("/fail", tt.err), func(c *config.Config) {
+				c.ERROR_MESSAGE_LOCALES = tt.locales
+			})
+			req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+
+			resp, body := send(t, app, req)
+
+			if body.Error.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", body.Error.Message, tt.wantMessage)
+			}
+			if got := resp.Header.Get("Content-Language"); got != tt.wantLocale {
+				t.Errorf("Content-Language = %q, want %q", got, tt.wantLocale)
+			}
+			if got := spanAttribute(requestSpan(t), "response.locale"); got != tt.wantLocale {
+				t.Errorf("span response.locale = %q, want %q", got, tt.wantLocale)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/narender/common/featureflags"
+	"github.com/narender/common/globals"
+	"github.com/narender/common/telemetry/metric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// AttrMaintenanceMode records whether maintenance mode was on when a write arrived.
+const AttrMaintenanceMode = "maintenance.mode"
+
+// MaintenanceGuard rejects the route with 503 and a Retry-After header while the
+// maintenance_mode flag is on. Attach it to write routes only so reads keep serving.
+func MaintenanceGuard() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		enabled := featureflags.IsEnabled(featureflags.MaintenanceMode)
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Bool(AttrMaintenanceMode, enabled))
+		if !enabled {
+			return c.Next()
+		}
+
+		route := c.Route().Path
+		metric.IncrementMaintenanceBlocked(ctx, route, utils.CopyString(c.Method()))
+		globals.Logger().WarnContext(ctx, "Write rejected: maintenance mode is on",
+			slog.String("component", "maintenance_guard"),
+			slog.String("path", route),
+			slog.String("method", c.Method()),
+			slog.String("actor", ActorFromContext(ctx)))
+
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(globals.Cfg().MaintenanceRetryAfterSec))
+		return apierrors.NewApplicationError(
+			apierrors.ErrCodeServiceUnavailable,
+			"The service is in maintenance mode; writes are temporarily disabled",
+			nil)
+	}
+}
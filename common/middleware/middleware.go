@@ -12,14 +12,22 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/narender/common/globals"
+	"github.com/narender/common/messages"
+	"github.com/narender/common/telemetry/metric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	// Import common packages
 	apierrors "github.com/narender/common/apierrors"
 	apiresponses "github.com/narender/common/apiresponses"
 )
 
-// RecoverMiddleware handles panics gracefully
+// RecoverMiddleware handles panics gracefully. Register it after the otelfiber,
+// access log and route metrics middleware so the panic is recorded on the still
+// open request span and the resulting 500 is logged and counted like any other.
 func RecoverMiddleware() fiber.Handler {
 	logger := globals.Logger()
 
@@ -33,6 +41,12 @@ func RecoverMiddleware() fiber.Handler {
 
 				stack := string(debug.Stack())
 
+				ctx := c.UserContext()
+				span := trace.SpanFromContext(ctx)
+				span.RecordError(err, trace.WithStackTrace(true))
+				span.SetStatus(codes.Error, err.Error())
+				metric.IncrementPanicRecovered(ctx, c.Route().Path, utils.CopyString(c.Method()))
+
 				logger.ErrorContext(c.UserContext(), "CRITICAL: Unhandled panic recovered",
 					slog.String("error", err.Error()),
 					slog.String("stack", stack),
@@ -53,6 +67,28 @@ func RecoverMiddleware() fiber.Handler {
 	}
 }
 
+// RouteMetricsMiddleware labels the request span and request counter with the
+// matched route template (c.Route().Path) rather than the concrete path.
+func RouteMetricsMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Run the error handler here so the final status code is known before recording
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().Config().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(http.StatusInternalServerError)
+			}
+		}
+
+		// The matched route is only known once routing has run
+		route := c.Route().Path
+		trace.SpanFromContext(c.UserContext()).SetAttributes(attribute.String(metric.AttrHTTPRoute, route))
+		// c.Method() points into fasthttp's request buffer, which the next request
+		// reuses; metric attributes outlive the request, so they need their own copy
+		metric.IncrementHTTPRequestCount(c.UserContext(), route, utils.CopyString(c.Method()), c.Response().StatusCode())
+		recordRequest(c.Response().StatusCode())
+		return nil
+	}
+}
+
 // ErrorHandler creates a Fiber error handler middleware.
 func ErrorHandler() fiber.ErrorHandler {
 	logger := globals.Logger()
@@ -62,17 +98,21 @@ func ErrorHandler() fiber.ErrorHandler {
 		var statusCode int = http.StatusInternalServerError
 		var errCode string = apierrors.ErrCodeUnknown
 		var message string = "An unexpected error occurred. Please try again later."
+		var details map[string]interface{}
 
 		if errors.As(err, &appErr) {
 			// Handle our custom AppError
 			errCode = appErr.Code
 			message = appErr.Message
+			details = appErr.Details
 
 			// Map AppError Code to HTTP Status Code based on category and code
 			if appErr.Category == apierrors.CategoryBusiness {
 				switch appErr.Code {
 				case apierrors.ErrCodeProductNotFound:
 					statusCode = http.StatusNotFound
+				case apierrors.ErrCodeConflict:
+					statusCode = http.StatusConflict
 				case apierrors.ErrCodeInsufficientStock,
 					apierrors.ErrCodeInvalidProductData,
 					apierrors.ErrCodeOrderLimitExceeded,
@@ -116,15 +156,32 @@ func ErrorHandler() fiber.ErrorHandler {
 					slog.String("category", string(appErr.Category)),
 					slog.String("message", appErr.Message),
 					slog.Any("cause", appErr.Unwrap()),
+					slog.Any("breadcrumbs", appErr.Breadcrumbs()),
 					slog.String("path", c.Path()),
 				)
 			}
+
+			notifyIfCritical(c, appErr)
+
+			if (appErr.Code == apierrors.ErrCodeRequestValidation || appErr.Code == apierrors.ErrCodeMalformedData) && !appErr.Simulated() {
+				metric.IncrementValidationFailures(c.UserContext(), c.Route().Path, utils.CopyString(c.Method()))
+			}
 		} else {
 			// Handle unexpected errors with better classification
 			var netErr net.Error
 			var jsonErr *json.SyntaxError
+			var fiberErr *fiber.Error
 
 			switch {
+			case errors.As(err, &fiberErr):
+				// Framework errors (unknown route, method not allowed, body too large)
+				// keep their status; 4xx are reported as request errors.
+				statusCode = fiberErr.Code
+				message = fiberErr.Message
+				if statusCode < http.StatusInternalServerError {
+					errCode = apierrors.ErrCodeRequestValidation
+				}
+
 			case errors.As(err, &netErr):
 				errCode = apierrors.ErrCodeNetworkError
 				statusCode = http.StatusServiceUnavailable
@@ -134,6 +191,7 @@ func ErrorHandler() fiber.ErrorHandler {
 				errCode = apierrors.ErrCodeMalformedData
 				statusCode = http.StatusBadRequest
 				message = "Invalid data format in request"
+				metric.IncrementValidationFailures(c.UserContext(), c.Route().Path, utils.CopyString(c.Method()))
 
 			case errors.Is(err, context.DeadlineExceeded):
 				errCode = apierrors.ErrCodeRequestTimeout
@@ -159,6 +217,25 @@ func ErrorHandler() fiber.ErrorHandler {
 			)
 		}
 
+		// Record the triage class so dashboards can split client, business and system errors
+		errorClass := classifyError(appErr, errCode, statusCode)
+		metric.IncrementErrorsByClass(c.UserContext(), errorClass, errCode)
+		trace.SpanFromContext(c.UserContext()).SetAttributes(attribute.String(metric.AttrErrorClass, errorClass))
+		recordRequestContext(c)
+
+		// Serve the message in the client's language when the catalog has a translation;
+		// English responses keep the specific message produced by the error site
+		locale := messages.Negotiate(c.Get(fiber.HeaderAcceptLanguage), messages.ParseLocales(globals.Cfg().ERROR_MESSAGE_LOCALES))
+		if locale != messages.DefaultLocale {
+			if localized, ok := messages.Lookup(errCode, locale); ok {
+				message = localized
+			} else {
+				locale = messages.DefaultLocale
+			}
+		}
+		trace.SpanFromContext(c.UserContext()).SetAttributes(attribute.String("response.locale", locale))
+		c.Set(fiber.HeaderContentLanguage, locale)
+
 		// Send standardized JSON error response
 		c.Status(statusCode)
 		return c.JSON(apiresponses.ErrorResponse{
@@ -167,7 +244,26 @@ func ErrorHandler() fiber.ErrorHandler {
 				Code:      errCode,
 				Message:   message,
 				Timestamp: time.Now().UTC().Format(time.RFC3339),
+				Details:   details,
 			},
 		})
 	}
 }
+
+// classifyError derives the triage class (client, business or system) of an error
+// from its category, code and resulting HTTP status.
+func classifyError(appErr *apierrors.AppError, errCode string, statusCode int) string {
+	switch {
+	case statusCode >= http.StatusInternalServerError:
+		return "system"
+	case appErr != nil && appErr.Category == apierrors.CategoryBusiness:
+		return "business"
+	case errCode == apierrors.ErrCodeRequestValidation,
+		errCode == apierrors.ErrCodeMalformedData:
+		return "client"
+	case statusCode >= http.StatusBadRequest:
+		return "client"
+	default:
+		return "system"
+	}
+}
@@ -0,0 +1,379 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/config"
+	"github.com/narender/common/globals"
+	"github.com/narender/common/telemetry/metric"
+	"github.com/narender/common/telemetry/telemetrytest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	apierrors "github.com/narender/common/apierrors"
+	apiresponses "github.com/narender/common/apiresponses"
+)
+
+var harness *telemetrytest.Harness
+
+func TestMain(m *testing.M) {
+	harness = telemetrytest.NewTestHarness()
+	code := m.Run()
+	harness.Teardown()
+	os.Exit(code)
+}
+
+// newTestApp returns an app using ErrorHandler in which every request runs inside
+// a "request" span. As otelfiber does, errors are handled before the span ends.
+// setup registers the routes.
+func newTestApp(t *testing.T, setup func(app *fiber.App), overrides ...func(*config.Config)) *fiber.App {
+	t.Helper()
+	globals.InitForTest(t, overrides...)
+	harness.Reset()
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler()})
+	app.Use(func(c *fiber.Ctx) error {
+		ctx, span := otel.Tracer("middleware_test").Start(c.UserContext(), "request")
+		defer span.End()
+		c.SetUserContext(ctx)
+		if err := c.Next(); err != nil {
+			return c.App().Config().ErrorHandler(c, err)
+		}
+		return nil
+	})
+	setup(app)
+	return app
+}
+
+// failingRoute registers GET path returning err.
+func failingRoute(path string, err error) func(app *fiber.App) {
+	return func(app *fiber.App) {
+		app.Get(path, func(c *fiber.Ctx) error { return err })
+	}
+}
+
+// send performs a request and decodes the error envelope, if any.
+func send(t *testing.T, app *fiber.App, req *http.Request) (*http.Response, apiresponses.ErrorResponse) {
+	t.Helper()
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+	var body apiresponses.ErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	return resp, body
+}
+
+// requestSpan returns the single "request" span recorded.
+func requestSpan(t *testing.T) tracetest.SpanStub {
+	t.Helper()
+	spans := harness.SpansNamed("request")
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d request spans, want 1", len(spans))
+	}
+	return spans[0]
+}
+
+// spanAttribute returns the value of key on span, or "" if it is not set.
+func spanAttribute(span tracetest.SpanStub, key string) string {
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value.Emit()
+		}
+	}
+	return ""
+}
+
+// metricValue returns the current value of the named metric for attrs, or 0.
+func metricValue(name string, attrs ...attribute.KeyValue) float64 {
+	value, _ := harness.MetricValueWith(name, attrs...)
+	return value
+}
+
+func TestErrorHandlerCountsErrorsByClass(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantClass  string
+	}{
+		{name: "validation", err: apierrors.NewApplicationError(apierrors.ErrCodeRequestValidation, "quantity must be positive", nil),
+			wantStatus: http.StatusBadRequest, wantCode: apierrors.ErrCodeRequestValidation, wantClass: "client"},
+		{name: "malformed JSON", err: &json.SyntaxError{Offset: 3},
+			wantStatus: http.StatusBadRequest, wantCode: apierrors.ErrCodeMalformedData, wantClass: "client"},
+		{name: "body too large", err: fiber.ErrRequestEntityTooLarge,
+			wantStatus: http.StatusRequestEntityTooLarge, wantCode: apierrors.ErrCodeRequestValidation, wantClass: "client"},
+		{name: "product not found", err: apierrors.NewBusinessError(apierrors.ErrCodeProductNotFound, "no such product", nil),
+			wantStatus: http.StatusNotFound, wantCode: apierrors.ErrCodeProductNotFound, wantClass: "business"},
+		{name: "insufficient stock", err: apierrors.NewBusinessError(apierrors.ErrCodeInsufficientStock, "only 2 left", nil),
+			wantStatus: http.StatusBadRequest, wantCode: apierrors.ErrCodeInsufficientStock, wantClass: "business"},
+		{name: "stock conflict", err: apierrors.NewAppError(apierrors.ErrCodeConflict, "stock changed", nil),
+			wantStatus: http.StatusConflict, wantCode: apierrors.ErrCodeConflict, wantClass: "business"},
+		{name: "database failure", err: apierrors.NewApplicationError(apierrors.ErrCodeDatabaseAccess, "read failed", errors.New("disk")),
+			wantStatus: http.StatusInternalServerError, wantCode: apierrors.ErrCodeDatabaseAccess, wantClass: "system"},
+		{name: "downstream unavailable", err: apierrors.NewApplicationError(apierrors.ErrCodeServiceUnavailable, "down", nil),
+			wantStatus: http.StatusServiceUnavailable, wantCode: apierrors.ErrCodeServiceUnavailable, wantClass: "system"},
+		{name: "method not allowed", err: fiber.ErrMethodNotAllowed,
+			wantStatus: http.StatusMethodNotAllowed, wantCode: apierrors.ErrCodeRequestValidation, wantClass: "client"},
+		{name: "framework 5xx", err: fiber.ErrServiceUnavailable,
+			wantStatus: http.StatusServiceUnavailable, wantCode: apierrors.ErrCodeUnknown, wantClass: "system"},
+		{name: "wrapped application error", err: fmt.Errorf("load catalog: %w", apierrors.NewApplicationError(apierrors.ErrCodeDatabaseAccess, "read failed", nil)),
+			wantStatus: http.StatusInternalServerError, wantCode: apierrors.ErrCodeDatabaseAccess, wantClass: "system"},
+		{name: "unclassified error", err: errors.New("boom"),
+			wantStatus: http.StatusInternalServerError, wantCode: apierrors.ErrCodeUnknown, wantClass: "system"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, failingRoute("/fail", tt.err))
+			labels := []attribute.KeyValue{
+				attribute.String(metric.AttrErrorClass, tt.wantClass),
+				attribute.String(metric.AttrErrorCode, tt.wantCode),
+			}
+			before := metricValue(metric.AppErrorsTotalMetric, labels...)
+
+			resp, body := send(t, app, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if body.Error.Code != tt.wantCode {
+				t.Errorf("error code = %q, want %q", body.Error.Code, tt.wantCode)
+			}
+			if got := metricValue(metric.AppErrorsTotalMetric, labels...) - before; got != 1 {
+				t.Errorf("%s{class=%s, code=%s} rose by %v, want 1", metric.AppErrorsTotalMetric, tt.wantClass, tt.wantCode, got)
+			}
+			if got := spanAttribute(requestSpan(t), metric.AttrErrorClass); got != tt.wantClass {
+				t.Errorf("span %s = %q, want %q", metric.AttrErrorClass, got, tt.wantClass)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerCountsUnknownRoutesAsClientErrors(t *testing.T) {
+	app := newTestApp(t, func(app *fiber.App) {})
+	labels := []attribute.KeyValue{
+		attribute.String(metric.AttrErrorClass, "client"),
+		attribute.String(metric.AttrErrorCode, apierrors.ErrCodeRequestValidation),
+	}
+	before := metricValue(metric.AppErrorsTotalMetric, labels...)
+
+	resp, body := send(t, app, httptest.NewRequest(http.MethodGet, "/no/such/route", nil))
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+	if !strings.Contains(body.Error.Message, "/no/such/route") {
+		t.Errorf("message = %q, want the framework message naming the route", body.Error.Message)
+	}
+	if got := metricValue(metric.AppErrorsTotalMetric, labels...) - before; got != 1 {
+		t.Errorf("client errors rose by %v, want 1", got)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	business := apierrors.NewBusinessError(apierrors.ErrCodeInsufficientStock, "", nil)
+	application := apierrors.NewApplicationError(apierrors.ErrCodeDatabaseAccess, "", nil)
+
+	tests := []struct {
+		name   string
+		appErr *apierrors.AppError
+		code   string
+		status int
+		want   string
+	}{
+		{name: "business rejection", appErr: business, code: business.Code, status: http.StatusBadRequest, want: "business"},
+		{name: "business error ending in a 5xx is a system error", appErr: business, code: business.Code, status: http.StatusInternalServerError, want: "system"},
+		{name: "application 5xx", appErr: application, code: application.Code, status: http.StatusInternalServerError, want: "system"},
+		{name: "validation code", code: apierrors.ErrCodeRequestValidation, status: http.StatusBadRequest, want: "client"},
+		{name: "other 4xx", code: apierrors.ErrCodeRequestTimeout, status: http.StatusRequestTimeout, want: "client"},
+		{name: "no error status", code: apierrors.ErrCodeUnknown, status: http.StatusOK, want: "system"},
+	}
+	for _, tt := range tests {
+		if got := classifyError(tt.appErr, tt.code, tt.status); got != tt.want {
+			t.Errorf("%s: classifyError = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRouteMetricsMiddlewareLabelsWithTheRouteTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		wantRoute string
+		wantCode  int
+	}{
+		{name: "parameterized route", target: "/products/Coffee%20Mug", wantRoute: "/products/:name", wantCode: http.StatusOK},
+		{name: "error on a parameterized route", target: "/products/Missing%20Mug", wantRoute: "/products/:name", wantCode: http.StatusNotFound},
+		{name: "static route", target: "/products", wantRoute: "/products", wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, func(app *fiber.App) {
+				app.Use(RouteMetricsMiddleware())
+				app.Get("/products", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+				app.Get("/products/:name", func(c *fiber.Ctx) error {
+					if c.Params("name") == "Missing%20Mug" {
+						return apierrors.NewBusinessError(apierrors.ErrCodeProductNotFound, "no such product", nil)
+					}
+					return c.SendStatus(http.StatusOK)
+				})
+			})
+			labels := []attribute.KeyValue{
+				attribute.String(metric.AttrHTTPRoute, tt.wantRoute),
+				attribute.String(metric.AttrHTTPMethod, http.MethodGet),
+				attribute.Int(metric.AttrHTTPStatusCode, tt.wantCode),
+			}
+			before := metricValue(metric.AppHTTPRequestCountMetric, labels...)
+
+			resp, _ := send(t, app, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if resp.StatusCode != tt.wantCode {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+			if got := spanAttribute(requestSpan(t), metric.AttrHTTPRoute); got != tt.wantRoute {
+				t.Errorf("span %s = %q, want %q", metric.AttrHTTPRoute, got, tt.wantRoute)
+			}
+			if got := metricValue(metric.AppHTTPRequestCountMetric, labels...) - before; got != 1 {
+				t.Errorf("%s{route=%s} rose by %v, want 1", metric.AppHTTPRequestCountMetric, tt.wantRoute, got)
+			}
+			if tt.target != tt.wantRoute {
+				if _, found := harness.MetricValueWith(metric.AppHTTPRequestCountMetric, attribute.String(metric.AttrHTTPRoute, tt.target)); found {
+					t.Errorf("%s labelled with the concrete path %q", metric.AppHTTPRequestCountMetric, tt.target)
+				}
+			}
+		})
+	}
+}
+
+func TestRouteMetricsMiddlewareKeepsEachRequestsMethod(t *testing.T) {
+	app := newTestApp(t, func(app *fiber.App) {
+		app.Use(RouteMetricsMiddleware())
+		app.All("/products/stock", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+	})
+	count := func(method string) float64 {
+		return metricValue(metric.AppHTTPRequestCountMetric,
+			attribute.String(metric.AttrHTTPRoute, "/products/stock"),
+			attribute.String(metric.AttrHTTPMethod, method))
+	}
+	methods := []string{http.MethodPatch, http.MethodPost, http.MethodGet}
+	before := make(map[string]float64)
+	for _, method := range methods {
+		before[method] = count(method)
+	}
+
+	// Fiber reuses the request buffer between requests, so a label that aliases it
+	// would turn an earlier PATCH series into one for a later method
+	for _, method := range methods {
+		send(t, app, httptest.NewRequest(method, "/products/stock", nil))
+	}
+
+	for _, method := range methods {
+		if got := count(method) - before[method]; got != 1 {
+			t.Errorf("%s{method=%s} rose by %v, want 1", metric.AppHTTPRequestCountMetric, method, got)
+		}
+	}
+}
+
+func TestRecoverMiddlewareCountsPanicsPerMethod(t *testing.T) {
+	app := newTestApp(t, func(app *fiber.App) {
+		app.Use(RecoverMiddleware())
+		app.All("/debug/panic", func(c *fiber.Ctx) error { panic("boom") })
+	})
+	count := func(method string) float64 {
+		return metricValue(metric.PanicRecoveredMetric,
+			attribute.String(metric.AttrHTTPRoute, "/debug/panic"),
+			attribute.String(metric.AttrHTTPMethod, method))
+	}
+	methods := []string{http.MethodPost, http.MethodGet}
+	before := make(map[string]float64)
+	for _, method := range methods {
+		before[method] = count(method)
+	}
+
+	for _, method := range methods {
+		if resp, _ := send(t, app, httptest.NewRequest(method, "/debug/panic", nil)); resp.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("%s: status = %d, want %d", method, resp.StatusCode, http.StatusInternalServerError)
+		}
+	}
+
+	for _, method := range methods {
+		if got := count(method) - before[method]; got != 1 {
+			t.Errorf("%s{method=%s} rose by %v, want 1", metric.PanicRecoveredMetric, method, got)
+		}
+	}
+}
+
+func TestErrorHandlerCountsValidationFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantCount float64
+	}{
+		{name: "validation error", err: apierrors.NewApplicationError(apierrors.ErrCodeRequestValidation, "quantity must be positive", nil), wantCount: 1},
+		{name: "malformed JSON", err: &json.SyntaxError{Offset: 3}, wantCount: 1},
+		{name: "simulated validation error", err: apierrors.NewApplicationError(apierrors.ErrCodeRequestValidation, "injected", nil).
+			WithContext(apierrors.ContextSimulated, true), wantCount: 0},
+		{name: "business error", err: apierrors.NewBusinessError(apierrors.ErrCodeProductNotFound, "no such product", nil), wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, failingRoute("/products/buy", tt.err))
+			labels := []attribute.KeyValue{
+				attribute.String(metric.AttrHTTPRoute, "/products/buy"),
+				attribute.String(metric.AttrHTTPMethod, http.MethodGet),
+			}
+			before := metricValue(metric.ValidationFailuresMetric, labels...)
+
+			send(t, app, httptest.NewRequest(http.MethodGet, "/products/buy", nil))
+
+			if got := metricValue(metric.ValidationFailuresMetric, labels...) - before; got != tt.wantCount {
+				t.Errorf("%s rose by %v, want %v", metric.ValidationFailuresMetric, got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerLogsTheBreadcrumbTrail(t *testing.T) {
+	globals.InitForTest(t)
+	logs := globals.CaptureLogsForTest(t)
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler()})
+	dbFailure := apierrors.NewApplicationError(apierrors.ErrCodeDatabaseAccess, "read failed", errors.New("disk"))
+	app.Get("/products", func(c *fiber.Ctx) error {
+		return apierrors.WithOp(apierrors.WithOp(dbFailure, "product_repository.get_all"), "product_service.get_all")
+	})
+
+	resp, body := send(t, app, httptest.NewRequest(http.MethodGet, "/products", nil))
+
+	if resp.StatusCode != http.StatusInternalServerError || body.Error.Code != apierrors.ErrCodeDatabaseAccess {
+		t.Fatalf("got %d %s, want %d %s", resp.StatusCode, body.Error.Code, http.StatusInternalServerError, apierrors.ErrCodeDatabaseAccess)
+	}
+	var record struct {
+		Msg         string   `json:"msg"`
+		Breadcrumbs []string `json:"breadcrumbs"`
+	}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if strings.Contains(line, `"msg":"Error occurred"`) {
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("decode log line: %v", err)
+			}
+		}
+	}
+	want := []string{"product_service.get_all", "product_repository.get_all"}
+	if strings.Join(record.Breadcrumbs, ",") != strings.Join(want, ",") {
+		t.Errorf("logged breadcrumbs %q, want %q:\n%s", record.Breadcrumbs, want, logs.String())
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/globals"
+	"github.com/narender/common/notify"
+	"go.opentelemetry.io/otel/trace"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+var (
+	notifierOnce     sync.Once
+	criticalNotifier notify.Notifier
+
+	dbErrorsMu    sync.Mutex
+	dbErrorTimes  []time.Time
+	dbErrorWindow = time.Minute
+)
+
+func getNotifier() notify.Notifier {
+	notifierOnce.Do(func() {
+		cfg := globals.Cfg()
+		criticalNotifier = notify.New(cfg.NotifyWebhookURL, time.Duration(cfg.NotifyMinIntervalSec)*time.Second)
+	})
+	return criticalNotifier
+}
+
+// isCritical reports whether appErr warrants an out-of-band notification:
+// any recovered panic, or database access errors repeating within a minute.
+func isCritical(appErr *apierrors.AppError, now time.Time) bool {
+	switch appErr.Code {
+	case apierrors.ErrCodeSystemPanic:
+		return true
+	case apierrors.ErrCodeDatabaseAccess:
+		dbErrorsMu.Lock()
+		defer dbErrorsMu.Unlock()
+		recent := dbErrorTimes[:0]
+		for _, t := range dbErrorTimes {
+			if now.Sub(t) < dbErrorWindow {
+				recent = append(recent, t)
+			}
+		}
+		dbErrorTimes = append(recent, now)
+		return len(dbErrorTimes) >= globals.Cfg().NotifyDBErrorThreshold
+	default:
+		return false
+	}
+}
+
+// notifyIfCritical sends a notification carrying the trace ID for critical errors.
+// Delivery happens in the background so the response is never delayed.
+func notifyIfCritical(c *fiber.Ctx, appErr *apierrors.AppError) {
+	now := time.Now()
+	if !isCritical(appErr, now) {
+		return
+	}
+
+	ctx := c.UserContext()
+	n := notify.Notification{
+		Code:      appErr.Code,
+		Message:   appErr.Message,
+		TraceID:   trace.SpanContextFromContext(ctx).TraceID().String(),
+		Path:      c.Path(),
+		Service:   globals.Cfg().SERVICE_NAME,
+		Timestamp: now,
+	}
+
+	notifier := getNotifier()
+	go func() {
+		notifyCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := notifier.Notify(notifyCtx, n); err != nil {
+			globals.Logger().WarnContext(notifyCtx, "Failed to send critical error notification",
+				slog.String("error_code", n.Code),
+				slog.String("error", err.Error()))
+		}
+	}()
+}
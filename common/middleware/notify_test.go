@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/narender/common/config"
+	"github.com/narender/common/notify"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// channelNotifier passes notifications to a channel, since they are sent in the
+// background.
+type channelNotifier chan notify.Notification
+
+func (c channelNotifier) Notify(ctx context.Context, n notify.Notification) error {
+	c <- n
+	return nil
+}
+
+// useNotifier makes next the notifier behind notifyIfCritical for this test and
+// forgets earlier database errors.
+func useNotifier(t *testing.T, next notify.Notifier) {
+	t.Helper()
+	notifierOnce.Do(func() {})
+	previous := criticalNotifier
+	criticalNotifier = next
+	dbErrorTimes = nil
+	t.Cleanup(func() {
+		criticalNotifier = previous
+		dbErrorTimes = nil
+	})
+}
+
+// received collects the notifications delivered within a short wait.
+func received(ch channelNotifier) []notify.Notification {
+	var got []notify.Notification
+	for {
+		select {
+		case n := <-ch:
+			got = append(got, n)
+		case <-time.After(200 * time.Millisecond):
+			return got
+		}
+	}
+}
+
+func TestCriticalErrorsNotify(t *testing.T) {
+	dbErr := apierrors.NewApplicationError(apierrors.ErrCodeDatabaseAccess, "read failed", errors.New("disk"))
+	tests := []struct {
+		name         string
+		err          error
+		requests     int
+		threshold    int
+		wantNotified int
+	}{
+		{name: "panic notifies", err: apierrors.NewApplicationError(apierrors.ErrCodeSystemPanic, "panic", nil), requests: 1, threshold: 5, wantNotified: 1},
+		{name: "isolated database errors stay quiet", err: dbErr, requests: 4, threshold: 5, wantNotified: 0},
+		{name: "repeated database errors notify", err: dbErr, requests: 5, threshold: 5, wantNotified: 1},
+		{name: "business errors never notify", err: apierrors.NewBusinessError(apierrors.ErrCodeInsufficientStock, "only 2 left", nil), requests: 10, threshold: 1, wantNotified: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, failingRoute("/fail", tt.err), func(c *config.Config) { c.NotifyDBErrorThreshold = tt.threshold })
+			ch := make(channelNotifier, 16)
+			// The production rate limiter, so floods past the threshold collapse into one
+			useNotifier(t, notify.NewRateLimitedNotifier(ch, time.Minute))
+
+			for i := 0; i < tt.requests; i++ {
+				send(t, app, httptest.NewRequest(http.MethodGet, "/fail", nil))
+			}
+
+			got := received(ch)
+			if len(got) != tt.wantNotified {
+				t.Fatalf("received %d notifications, want %d: %+v", len(got), tt.wantNotified, got)
+			}
+			traceIDs := requestSpanTraceIDs(t)
+			for _, n := range got {
+				if !traceIDs[n.TraceID] {
+					t.Errorf("notification trace ID %s is not one of the request traces", n.TraceID)
+				}
+				if n.Path != "/fail" {
+					t.Errorf("notification path = %q, want /fail", n.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestPanicFloodIsSuppressed(t *testing.T) {
+	app := newTestApp(t, failingRoute("/fail", apierrors.NewApplicationError(apierrors.ErrCodeSystemPanic, "panic", nil)))
+	ch := make(channelNotifier, 64)
+	useNotifier(t, notify.NewRateLimitedNotifier(ch, time.Minute))
+
+	for i := 0; i < 20; i++ {
+		send(t, app, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	}
+
+	if got := received(ch); len(got) != 1 {
+		t.Errorf("received %d notifications for 20 panics within a minute, want 1", len(got))
+	}
+}
+
+// requestSpanTraceIDs returns the trace IDs of the recorded request spans.
+func requestSpanTraceIDs(t *testing.T) map[string]bool {
+	t.Helper()
+	ids := make(map[string]bool)
+	for _, span := range harness.SpansNamed("request") {
+		ids[span.SpanContext.TraceID().String()] = true
+	}
+	return ids
+}
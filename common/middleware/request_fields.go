@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/telemetry"
+	"github.com/narender/common/telemetry/metric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader lets callers supply their own request ID for log correlation.
+const RequestIDHeader = "X-Request-ID"
+
+// OperationIDHeader returns the operation ID so clients can quote it in bug reports.
+const OperationIDHeader = "X-Operation-ID"
+
+// RequestLogFieldsMiddleware stores the request_id and operation.id log fields on the
+// request context, so every log call made while serving the request carries them.
+// The request ID comes from the X-Request-ID header, or the trace ID when the header
+// is absent. The operation ID is always generated here, once per request, and is
+// also set on the server span. It must run after otelfiber.
+func RequestLogFieldsMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, operationID := telemetry.WithOperationID(c.UserContext())
+		c.SetUserContext(ctx)
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String(metric.AttrOperationID, operationID))
+		c.Set(OperationIDHeader, operationID)
+
+		requestID := c.Get(RequestIDHeader)
+		if requestID == "" {
+			if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.HasTraceID() {
+				requestID = spanCtx.TraceID().String()
+			}
+		}
+		if requestID != "" {
+			c.SetUserContext(telemetry.WithLogFields(ctx, slog.String("request_id", requestID)))
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/globals"
+	"github.com/narender/common/telemetry"
+	"github.com/narender/common/telemetry/metric"
+)
+
+func TestRequestLogFieldsMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        string
+		wantRequestID string // empty means the request span's trace ID
+	}{
+		{name: "caller supplied request ID", header: "req-42", wantRequestID: "req-42"},
+		{name: "falls back to the trace ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := make(map[string]string)
+			app := newTestApp(t, func(app *fiber.App) {
+				app.Use(RequestLogFieldsMiddleware())
+				app.Get("/products", func(c *fiber.Ctx) error {
+					for _, field := range telemetry.LogFields(c.UserContext()) {
+						fields[field.Key] = field.Value.String()
+					}
+					return c.SendStatus(http.StatusOK)
+				})
+			})
+			req := httptest.NewRequest(http.MethodGet, "/products", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+
+			send(t, app, req)
+
+			want := tt.wantRequestID
+			if want == "" {
+				want = requestSpan(t).SpanContext.TraceID().String()
+			}
+			if fields["request_id"] != want {
+				t.Errorf("request_id log field = %q, want %q", fields["request_id"], want)
+			}
+		})
+	}
+}
+
+// operationIDApp serves GET /products, logging twice through a context handler
+// writing JSON lines to logs. traced wraps each request in a "request" span.
+func operationIDApp(t *testing.T, traced bool, logs *bytes.Buffer) *fiber.App {
+	t.Helper()
+	logger := slog.New(telemetry.NewContextHandler(slog.NewJSONHandler(logs, nil)))
+	routes := func(app *fiber.App) {
+		app.Use(RequestLogFieldsMiddleware())
+		app.Get("/products", func(c *fiber.Ctx) error {
+			ctx := c.UserContext()
+			logger.InfoContext(ctx, "Fetching products")
+			logger.InfoContext(telemetry.WithOperation(ctx, "product_repository", "get_all"), "Reading data file")
+			return c.SendStatus(http.StatusOK)
+		})
+	}
+	if traced {
+		return newTestApp(t, routes)
+	}
+	globals.InitForTest(t)
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler()})
+	routes(app)
+	return app
+}
+
+// operationIDs returns the operation.id of each JSON log line in logs.
+func operationIDs(t *testing.T, logs *bytes.Buffer) []string {
+	t.Helper()
+	var ids []string
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		id, _ := record[metric.AttrOperationID].(string)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestLogsWithinARequestShareTheOperationID(t *testing.T) {
+	for _, traced := range []bool{true, false} {
+		t.Run(fmt.Sprintf("traced=%v", traced), func(t *testing.T) {
+			var logs bytes.Buffer
+			app := operationIDApp(t, traced, &logs)
+			seen := make(map[string]bool)
+
+			for i := 0; i < 2; i++ {
+				logs.Reset()
+				if traced {
+					harness.Reset()
+				}
+				resp, _ := send(t, app, httptest.NewRequest(http.MethodGet, "/products", nil))
+
+				id := resp.Header.Get(OperationIDHeader)
+				if len(id) != 16 {
+					t.Fatalf("%s = %q, want a 16 hex digit ID", OperationIDHeader, id)
+				}
+				if seen[id] {
+					t.Errorf("request %d reused operation ID %s", i, id)
+				}
+				seen[id] = true
+				if got := operationIDs(t, &logs); !slices.Equal(got, []string{id, id}) {
+					t.Errorf("request %d: log operation IDs = %q, want %s on both lines", i, got, id)
+				}
+				if traced {
+					span := requestSpan(t)
+					if got := spanAttribute(span, metric.AttrOperationID); got != id {
+						t.Errorf("span %s = %q, want %q", metric.AttrOperationID, got, id)
+					}
+					if id == span.SpanContext.TraceID().String() {
+						t.Errorf("operation ID equals the trace ID")
+					}
+				}
+			}
+		})
+	}
+}
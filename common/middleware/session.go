@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/narender/common/globals"
+	"github.com/narender/common/telemetry/metric"
+)
+
+// Totals for the current process, maintained by RouteMetricsMiddleware.
+var (
+	requestsServed atomic.Int64
+	requestErrors  atomic.Int64
+)
+
+func recordRequest(statusCode int) {
+	requestsServed.Add(1)
+	if statusCode >= http.StatusBadRequest {
+		requestErrors.Add(1)
+	}
+}
+
+// SessionSummary logs uptime, requests served and error responses for this process.
+// Register it as a shutdown hook that runs after the HTTP server stops and before
+// telemetry is flushed, so the final counts are complete and still exported.
+func SessionSummary(ctx context.Context) error {
+	globals.Logger().InfoContext(ctx, "Session summary",
+		slog.String("component", "session"),
+		slog.Duration("uptime", metric.Uptime()),
+		slog.Int64("requests_served", requestsServed.Load()),
+		slog.Int64("request_errors", requestErrors.Load()))
+	return nil
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/globals"
+	"github.com/narender/common/telemetry/metric"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+func TestSessionSummaryReflectsServedRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		targets    []string
+		wantServed float64
+		wantErrors float64
+	}{
+		{name: "no requests"},
+		{name: "successes only", targets: []string{"/ok", "/ok", "/ok"}, wantServed: 3},
+		{name: "client and server errors", targets: []string{"/ok", "/missing", "/fail", "/ok"}, wantServed: 4, wantErrors: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, func(app *fiber.App) {
+				app.Use(RouteMetricsMiddleware())
+				app.Get("/ok", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+				app.Get("/missing", func(c *fiber.Ctx) error {
+					return apierrors.NewBusinessError(apierrors.ErrCodeProductNotFound, "no such product", nil)
+				})
+				app.Get("/fail", func(c *fiber.Ctx) error { return fiber.ErrInternalServerError })
+			})
+			logs := globals.CaptureLogsForTest(t)
+			requestsServed.Store(0)
+			requestErrors.Store(0)
+
+			for _, target := range tt.targets {
+				send(t, app, httptest.NewRequest(http.MethodGet, target, nil))
+			}
+			if err := SessionSummary(context.Background()); err != nil {
+				t.Fatalf("SessionSummary: %v", err)
+			}
+
+			var summary map[string]any
+			if err := json.Unmarshal(logs.Bytes(), &summary); err != nil {
+				t.Fatalf("decode summary %q: %v", logs.String(), err)
+			}
+			if summary["msg"] != "Session summary" {
+				t.Fatalf("logged %q, want the session summary", summary["msg"])
+			}
+			if summary["requests_served"] != tt.wantServed || summary["request_errors"] != tt.wantErrors {
+				t.Errorf("summary requests_served = %v, request_errors = %v, want %v and %v",
+					summary["requests_served"], summary["request_errors"], tt.wantServed, tt.wantErrors)
+			}
+			if uptime, ok := summary["uptime"].(float64); !ok || uptime <= 0 {
+				t.Errorf("summary uptime = %v, want a positive duration", summary["uptime"])
+			}
+			if _, found := harness.MetricValue(metric.ProcessUptimeMetric); !found {
+				t.Errorf("%s not reported", metric.ProcessUptimeMetric)
+			}
+		})
+	}
+}
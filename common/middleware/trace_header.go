@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/globals"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceResponseHeaderMiddleware echoes the trace ID of the request span in the
+// configured response header, so a client-reported problem can be matched to its
+// trace. It must run after otelfiber. An empty header name disables it.
+func TraceResponseHeaderMiddleware() fiber.Handler {
+	header := globals.Cfg().TRACE_RESPONSE_HEADER
+	return func(c *fiber.Ctx) error {
+		if header != "" {
+			if spanCtx := trace.SpanContextFromContext(c.UserContext()); spanCtx.HasTraceID() {
+				c.Set(header, spanCtx.TraceID().String())
+			}
+		}
+		return c.Next()
+	}
+}
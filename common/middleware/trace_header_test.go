@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/config"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+func TestTraceResponseHeaderMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		path       string
+		wantHeader string
+	}{
+		{name: "default header", header: "X-Trace-Id", path: "/ok", wantHeader: "X-Trace-Id"},
+		{name: "configured header", header: "Traceresponse", path: "/ok", wantHeader: "Traceresponse"},
+		{name: "error responses carry it too", header: "X-Trace-Id", path: "/fail", wantHeader: "X-Trace-Id"},
+		{name: "empty name disables it", header: "", path: "/ok"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, func(app *fiber.App) {
+				app.Use(TraceResponseHeaderMiddleware())
+				app.Get("/ok", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+				app.Get("/fail", func(c *fiber.Ctx) error {
+					return apierrors.NewApplicationError(apierrors.ErrCodeNotFound, "product not found", nil)
+				})
+			}, func(c *config.Config) {
+				c.TRACE_RESPONSE_HEADER = tt.header
+			})
+
+			resp, _ := send(t, app, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if tt.wantHeader == "" {
+				for _, name := range []string{"X-Trace-Id", "Traceresponse"} {
+					if got := resp.Header.Get(name); got != "" {
+						t.Errorf("%s = %q with the header disabled, want none", name, got)
+					}
+				}
+				return
+			}
+			want := requestSpan(t).SpanContext.TraceID().String()
+			if got := resp.Header.Get(tt.wantHeader); got != want {
+				t.Errorf("%s = %q, want the request span's trace ID %q", tt.wantHeader, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// ToCents converts a display amount to integer minor units, rounding to the nearest cent.
+// Money is aggregated in cents so repeated additions do not accumulate float drift.
+func ToCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// FromCents converts integer minor units back to a display amount.
+func FromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
+
+// Money is an amount in integer minor units (cents). It is encoded in JSON as a
+// decimal number (e.g. 19.99), so the wire and file formats match a float64 price
+// while arithmetic on it stays exact.
+type Money int64
+
+// MoneyFromFloat converts a display amount to Money, rounding to the nearest cent.
+func MoneyFromFloat(amount float64) Money {
+	return Money(ToCents(amount))
+}
+
+// Cents returns the amount in minor units.
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Float64 returns the amount as a display value, for logs and span attributes.
+func (m Money) Float64() float64 {
+	return FromCents(int64(m))
+}
+
+// String formats the amount as a decimal without trailing zeros, e.g. "19.99", "12.5", "10".
+func (m Money) String() string {
+	cents := int64(m)
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	whole, frac := cents/100, cents%100
+	switch {
+	case frac == 0:
+		return fmt.Sprintf("%s%d", sign, whole)
+	case frac%10 == 0:
+		return fmt.Sprintf("%s%d.%d", sign, whole, frac/10)
+	default:
+		return fmt.Sprintf("%s%d.%02d", sign, whole, frac)
+	}
+}
+
+// MarshalJSON encodes the amount as a JSON number built from the integer cents,
+// so no float rounding is involved.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number, rounding to the nearest cent.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var amount float64
+	if err := json.Unmarshal(data, &amount); err != nil {
+		return fmt.Errorf("invalid money amount %s: %w", data, err)
+	}
+	*m = MoneyFromFloat(amount)
+	return nil
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoneyRoundTripsExactly(t *testing.T) {
+	tests := []struct {
+		wire      string
+		wantCents int64
+		wantWire  string
+	}{
+		{wire: "19.99", wantCents: 1999, wantWire: "19.99"},
+		{wire: "12.5", wantCents: 1250, wantWire: "12.5"},
+		{wire: "12.50", wantCents: 1250, wantWire: "12.5"},
+		{wire: "10", wantCents: 1000, wantWire: "10"},
+		{wire: "0.07", wantCents: 7, wantWire: "0.07"},
+		{wire: "-3.05", wantCents: -305, wantWire: "-3.05"},
+		{wire: "0.30000000000000004", wantCents: 30, wantWire: "0.3"},
+		{wire: "50.00000001", wantCents: 5000, wantWire: "50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wire, func(t *testing.T) {
+			var m Money
+			if err := json.Unmarshal([]byte(tt.wire), &m); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", tt.wire, err)
+			}
+			if m.Cents() != tt.wantCents {
+				t.Errorf("Unmarshal(%s) = %d cents, want %d", tt.wire, m.Cents(), tt.wantCents)
+			}
+			out, err := json.Marshal(m)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(out) != tt.wantWire {
+				t.Errorf("Marshal = %s, want %s", out, tt.wantWire)
+			}
+
+			// A second round trip must not move the amount
+			var again Money
+			if err := json.Unmarshal(out, &again); err != nil || again != m {
+				t.Errorf("second round trip = %d cents (err %v), want %d", again.Cents(), err, m.Cents())
+			}
+		})
+	}
+}
+
+func TestMoneyUnmarshalRejectsNonNumbers(t *testing.T) {
+	for _, wire := range []string{`"19.99"`, `true`, `{}`} {
+		var m Money
+		if err := json.Unmarshal([]byte(wire), &m); err == nil {
+			t.Errorf("Unmarshal(%s) = %d cents, want an error", wire, m.Cents())
+		}
+	}
+	m := MoneyFromFloat(4.2)
+	if err := json.Unmarshal([]byte("null"), &m); err != nil || m.Cents() != 420 {
+		t.Errorf("Unmarshal(null) = %d cents (err %v), want the amount unchanged", m.Cents(), err)
+	}
+}
+
+func TestProductPriceWireFormatIsStable(t *testing.T) {
+	const wire = `{"name":"Coffee Mug","description":"Ceramic mug","price":9.5,"stock":20,"category":"Kitchenware"}`
+
+	var p Product
+	if err := json.Unmarshal([]byte(wire), &p); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	out, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(out) != wire {
+		t.Errorf("Marshal = %s, want %s", out, wire)
+	}
+}
+
+func TestMoneyArithmeticIsExact(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    float64
+		quantity int64
+		times    int
+		want     string
+	}{
+		// 0.1 added ten times is 0.9999999999999999 in float64
+		{name: "repeated dimes", price: 0.1, quantity: 1, times: 10, want: "1"},
+		{name: "bulk purchase", price: 19.99, quantity: 3, times: 1, want: "59.97"},
+		{name: "many sales", price: 74.99, quantity: 1, times: 1000, want: "74990"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price := MoneyFromFloat(tt.price)
+			var total Money
+			for i := 0; i < tt.times; i++ {
+				total += Money(price.Cents() * tt.quantity)
+			}
+			if got := total.String(); got != tt.want {
+				t.Errorf("total = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+const (
+	JSONFieldStock = "stock"
+)
+const (
+	JSONDataField       = "data"
+	JSONPaginationField = "pagination"
+)
+
+// Product is the canonical product representation shared by all services.
+// Its json tags define the wire format used between services and in the data file.
+type Product struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       Money  `json:"price"`
+	Stock       int    `json:"stock"`
+	Category    string `json:"category"`
+	// Soft-deleted products stay in the data file but are hidden from normal queries
+	Deleted   bool       `json:"deleted,omitempty"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+// ProductUpdate is a sparse change to a product; nil fields are left untouched.
+type ProductUpdate struct {
+	Price       *Money
+	Description *string
+	Category    *string
+	Stock       *int
+}
+
+// IsEmpty reports whether the update changes nothing.
+func (u ProductUpdate) IsEmpty() bool {
+	return u.Price == nil && u.Description == nil && u.Category == nil && u.Stock == nil
+}
+
+// SearchResult is a product matched by a search, with the field that matched
+// and a snippet of the surrounding text with the match wrapped in <mark> tags.
+type SearchResult struct {
+	Product      Product `json:"product"`
+	MatchedField string  `json:"matchedField"`
+	Highlight    string  `json:"highlight"`
+}
+
+// ProductComparison is a side-by-side view of several products. Requested names
+// that do not exist are listed in NotFound rather than failing the comparison.
+type ProductComparison struct {
+	Products []ComparedProduct `json:"products"`
+	NotFound []string          `json:"notFound"`
+	Cheapest string            `json:"cheapest,omitempty"`
+}
+
+// ComparedProduct is one product in a comparison with values computed against the
+// cheapest product compared.
+type ComparedProduct struct {
+	Product    Product `json:"product"`
+	PriceDelta Money   `json:"priceDelta"`
+	InStock    bool    `json:"inStock"`
+}
@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Notification describes a critical error that operators should hear about immediately.
+type Notification struct {
+	Code      string    `json:"code"`
+	Message   string    `json:"message"`
+	TraceID   string    `json:"trace_id,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Service   string    `json:"service,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Notifier delivers critical error notifications out of band.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// NoopNotifier discards all notifications.
+type NoopNotifier struct{}
+
+// Notify implements Notifier.
+func (NoopNotifier) Notify(ctx context.Context, n Notification) error {
+	return nil
+}
+
+// WebhookNotifier posts notifications as JSON to a webhook URL.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a WebhookNotifier posting to url.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Notify implements Notifier.
+func (w *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook notification: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// RateLimitedNotifier forwards at most one notification per error code per interval.
+type RateLimitedNotifier struct {
+	next     Notifier
+	interval time.Duration
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+// NewRateLimitedNotifier wraps next so floods of the same error code are suppressed.
+func NewRateLimitedNotifier(next Notifier, interval time.Duration) *RateLimitedNotifier {
+	return &RateLimitedNotifier{
+		next:     next,
+		interval: interval,
+		lastSent: make(map[string]time.Time),
+	}
+}
+
+// Notify implements Notifier. Suppressed notifications return nil.
+func (r *RateLimitedNotifier) Notify(ctx context.Context, n Notification) error {
+	r.mu.Lock()
+	if last, ok := r.lastSent[n.Code]; ok && n.Timestamp.Sub(last) < r.interval {
+		r.mu.Unlock()
+		return nil
+	}
+	r.lastSent[n.Code] = n.Timestamp
+	r.mu.Unlock()
+
+	return r.next.Notify(ctx, n)
+}
+
+// New returns a rate-limited webhook notifier, or a NoopNotifier when webhookURL is empty.
+func New(webhookURL string, interval time.Duration) Notifier {
+	if webhookURL == "" {
+		return NoopNotifier{}
+	}
+	return NewRateLimitedNotifier(NewWebhookNotifier(webhookURL), interval)
+}
@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// recordingNotifier keeps the notifications it receives.
+type recordingNotifier struct {
+	received []Notification
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, n Notification) error {
+	r.received = append(r.received, n)
+	return nil
+}
+
+func TestRateLimitedNotifier(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	sends := []struct {
+		code     string
+		after    time.Duration
+		wantSent bool
+	}{
+		{code: "SYSTEM_PANIC", after: 0, wantSent: true},
+		{code: "SYSTEM_PANIC", after: 10 * time.Second, wantSent: false},
+		{code: "DATABASE_ACCESS_ERROR", after: 20 * time.Second, wantSent: true},
+		{code: "SYSTEM_PANIC", after: 59 * time.Second, wantSent: false},
+		{code: "SYSTEM_PANIC", after: 60 * time.Second, wantSent: true},
+		{code: "SYSTEM_PANIC", after: 61 * time.Second, wantSent: false},
+	}
+
+	next := &recordingNotifier{}
+	limited := NewRateLimitedNotifier(next, time.Minute)
+	for i, send := range sends {
+		before := len(next.received)
+		n := Notification{Code: send.code, Timestamp: start.Add(send.after)}
+		if err := limited.Notify(context.Background(), n); err != nil {
+			t.Fatalf("send %d: Notify: %v", i, err)
+		}
+		if sent := len(next.received) > before; sent != send.wantSent {
+			t.Errorf("send %d (%s at +%v): forwarded = %v, want %v", i, send.code, send.after, sent, send.wantSent)
+		}
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "accepted", status: http.StatusNoContent},
+		{name: "rejected", status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Notification
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("Content-Type = %q, want application/json", ct)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("decode webhook payload: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			sent := Notification{Code: "SYSTEM_PANIC", Message: "panic", TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", Path: "/products/buy"}
+			err := NewWebhookNotifier(server.URL).Notify(context.Background(), sent)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Notify error = %v, want error %v", err, tt.wantErr)
+			}
+			if got.TraceID != sent.TraceID || got.Code != sent.Code || got.Path != sent.Path {
+				t.Errorf("webhook received %+v, want %+v", got, sent)
+			}
+		})
+	}
+}
+
+func TestNewWithoutWebhookIsNoop(t *testing.T) {
+	if _, ok := New("", time.Minute).(NoopNotifier); !ok {
+		t.Errorf("New without a webhook URL did not return a NoopNotifier")
+	}
+	if _, ok := New("http://alerts.internal/hook", time.Minute).(*RateLimitedNotifier); !ok {
+		t.Errorf("New with a webhook URL did not return a rate-limited notifier")
+	}
+}
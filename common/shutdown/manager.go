@@ -0,0 +1,139 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/narender/common/globals"
+)
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+type reloadHook struct {
+	name string
+	fn   func() error
+}
+
+// Manager runs registered shutdown hooks when the process receives SIGINT or SIGTERM,
+// and registered reload hooks on SIGHUP.
+// Each signal has its own time budget: SIGTERM usually comes from an orchestrator
+// with a grace period, while SIGINT is typically a developer wanting a quick exit.
+type Manager struct {
+	hooks          []hook
+	reloadHooks    []reloadHook
+	sigtermTimeout time.Duration
+	sigintTimeout  time.Duration
+	logger         *slog.Logger
+
+	// Hooks run once however many times shutdown is triggered
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+// NewManager creates a Manager using the configured per-signal timeouts.
+func NewManager() *Manager {
+	cfg := globals.Cfg()
+	return &Manager{
+		sigtermTimeout: cfg.ShutdownSigtermTimeout,
+		sigintTimeout:  cfg.ShutdownSigintTimeout,
+		logger:         globals.Logger(),
+	}
+}
+
+// Register adds a hook. Hooks run in reverse registration order.
+func (m *Manager) Register(name string, fn func(ctx context.Context) error) {
+	m.hooks = append(m.hooks, hook{name: name, fn: fn})
+}
+
+// RegisterReload adds a hook run on SIGHUP. Reload hooks run in registration order;
+// a failing hook is logged and does not stop the process.
+func (m *Manager) RegisterReload(name string, fn func() error) {
+	m.reloadHooks = append(m.reloadHooks, reloadHook{name: name, fn: fn})
+}
+
+// TimeoutFor returns the shutdown budget for the given signal.
+func (m *Manager) TimeoutFor(sig os.Signal) time.Duration {
+	if sig == os.Interrupt {
+		return m.sigintTimeout
+	}
+	return m.sigtermTimeout
+}
+
+// Wait blocks until SIGINT or SIGTERM is received and then runs the shutdown hooks.
+// SIGHUP runs the reload hooks and keeps waiting; without reload hooks SIGHUP is
+// left to its default behaviour.
+func (m *Manager) Wait() error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	if len(m.reloadHooks) > 0 {
+		signal.Notify(signals, syscall.SIGHUP)
+	}
+	defer signal.Stop(signals)
+
+	for sig := range signals {
+		if sig == syscall.SIGHUP {
+			m.Reload()
+			continue
+		}
+		return m.Shutdown(sig)
+	}
+	return nil
+}
+
+// Reload runs the reload hooks.
+func (m *Manager) Reload() {
+	m.logger.Info("Reload signal received", slog.Int("hooks", len(m.reloadHooks)))
+	for _, h := range m.reloadHooks {
+		if err := h.fn(); err != nil {
+			m.logger.Error("Reload hook failed",
+				slog.String("hook", h.name),
+				slog.Any("error", err))
+			continue
+		}
+		m.logger.Info("Reload hook completed", slog.String("hook", h.name))
+	}
+}
+
+// Shutdown runs the hooks within the budget for sig, returning all hook errors joined.
+// Only the first call runs the hooks; concurrent and later calls wait for it and
+// return the same result.
+func (m *Manager) Shutdown(sig os.Signal) error {
+	m.shutdownOnce.Do(func() {
+		m.shutdownErr = m.runHooks(sig)
+	})
+	return m.shutdownErr
+}
+
+func (m *Manager) runHooks(sig os.Signal) error {
+	timeout := m.TimeoutFor(sig)
+	m.logger.Info("Shutdown signal received",
+		slog.String("signal", sig.String()),
+		slog.Duration("timeout", timeout))
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(m.hooks) - 1; i >= 0; i-- {
+		h := m.hooks[i]
+		if err := h.fn(ctx); err != nil {
+			m.logger.Error("Shutdown hook failed",
+				slog.String("hook", h.name),
+				slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			continue
+		}
+		m.logger.Info("Shutdown hook completed", slog.String("hook", h.name))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,213 @@
+package shutdown
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/narender/common/config"
+	"github.com/narender/common/globals"
+	commonLog "github.com/narender/common/log"
+)
+
+// newTestManager returns a Manager with a 30s SIGTERM and 2s SIGINT budget, and
+// its captured log output.
+func newTestManager(t *testing.T) (*Manager, *bytes.Buffer) {
+	t.Helper()
+	globals.InitForTest(t, func(c *config.Config) {
+		c.ShutdownSigtermTimeout = 30 * time.Second
+		c.ShutdownSigintTimeout = 2 * time.Second
+	})
+	logs := globals.CaptureLogsForTest(t)
+	return NewManager(), logs
+}
+
+func TestShutdownUsesTheSignalsTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		sig     os.Signal
+		want    time.Duration
+		wantLog string
+	}{
+		{name: "SIGTERM", sig: syscall.SIGTERM, want: 30 * time.Second, wantLog: `"signal":"terminated","timeout":30000000000`},
+		{name: "SIGINT", sig: os.Interrupt, want: 2 * time.Second, wantLog: `"signal":"interrupt","timeout":2000000000`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, logs := newTestManager(t)
+
+			var remaining time.Duration
+			m.Register("flush", func(ctx context.Context) error {
+				deadline, ok := ctx.Deadline()
+				if !ok {
+					t.Fatalf("hook context has no deadline")
+				}
+				remaining = time.Until(deadline)
+				return nil
+			})
+
+			if err := m.Shutdown(tt.sig); err != nil {
+				t.Fatalf("Shutdown: %v", err)
+			}
+			if remaining > tt.want || remaining < tt.want-time.Second {
+				t.Errorf("hook had %s left, want just under %s", remaining, tt.want)
+			}
+			if !strings.Contains(logs.String(), tt.wantLog) {
+				t.Errorf("log does not record the signal and its timeout %s:\n%s", tt.wantLog, logs.String())
+			}
+		})
+	}
+}
+
+func TestShutdownRunsHooksOnceInReverseOrder(t *testing.T) {
+	m, _ := newTestManager(t)
+	var mu sync.Mutex
+	var order []string
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return err
+		}
+	}
+	m.Register("telemetry", record("telemetry", nil))
+	m.Register("database", record("database", errors.New("locked")))
+	m.Register("server", record("server", nil))
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = m.Shutdown(syscall.SIGTERM)
+		}(i)
+	}
+	wg.Wait()
+
+	if got := strings.Join(order, ","); got != "server,database,telemetry" {
+		t.Errorf("hooks ran as %s, want server,database,telemetry once each", got)
+	}
+	for i, err := range errs {
+		if err == nil || !strings.Contains(err.Error(), "database: locked") {
+			t.Errorf("Shutdown call %d returned %v, want the database hook error", i, err)
+		}
+	}
+}
+
+func TestReloadContinuesPastFailingHooks(t *testing.T) {
+	m, _ := newTestManager(t)
+	var ran []string
+	m.RegisterReload("config", func() error { ran = append(ran, "config"); return errors.New("bad file") })
+	m.RegisterReload("flags", func() error { ran = append(ran, "flags"); return nil })
+
+	m.Reload()
+
+	if got := strings.Join(ran, ","); got != "config,flags" {
+		t.Errorf("reload hooks ran as %s, want config,flags", got)
+	}
+}
+
+func TestSIGHUPReloadsTheLogLevel(t *testing.T) {
+	m, _ := newTestManager(t)
+	previous := commonLog.Level()
+	t.Cleanup(func() { commonLog.SetLevel(previous) })
+	commonLog.SetLevel(slog.LevelInfo)
+	m.RegisterReload("config", globals.Reload)
+
+	// Keep SIGHUP from killing the test process before Wait has subscribed to it
+	hangups := make(chan os.Signal, 8)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+
+	done := make(chan error, 1)
+	go func() { done <- m.Wait() }()
+
+	t.Setenv("LOG_LEVEL", "debug")
+	deadline := time.Now().Add(5 * time.Second)
+	for commonLog.Level() != slog.LevelDebug {
+		if time.Now().After(deadline) {
+			t.Fatalf("log level still %s 5s after SIGHUP, want DEBUG", commonLog.Level())
+		}
+		syscall.Kill(os.Getpid(), syscall.SIGHUP)
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// SIGHUP must not end Wait; SIGTERM does
+	select {
+	case err := <-done:
+		t.Fatalf("Wait returned after SIGHUP: %v", err)
+	default:
+	}
+	syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Wait: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Wait did not return after SIGTERM")
+	}
+}
+
+func TestSignalDuringProgrammaticShutdownRunsHooksOnce(t *testing.T) {
+	m, _ := newTestManager(t)
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var runs atomic.Int32
+	m.Register("server", func(context.Context) error {
+		if runs.Add(1) == 1 {
+			close(entered)
+		}
+		<-release
+		return errors.New("drain incomplete")
+	})
+
+	// Keep SIGTERM from killing the test process before Wait has subscribed to it
+	terms := make(chan os.Signal, 8)
+	signal.Notify(terms, syscall.SIGTERM)
+	defer signal.Stop(terms)
+
+	waited := make(chan error, 1)
+	go func() { waited <- m.Wait() }()
+	stopped := make(chan error, 1)
+	go func() { stopped <- m.Shutdown(os.Interrupt) }()
+	<-entered
+
+	// The signal arrives while the programmatic shutdown is still running its hooks
+	for i := 0; i < 5; i++ {
+		syscall.Kill(os.Getpid(), syscall.SIGTERM)
+		time.Sleep(20 * time.Millisecond)
+	}
+	select {
+	case err := <-waited:
+		t.Fatalf("Wait returned before the running shutdown finished: %v", err)
+	default:
+	}
+	close(release)
+
+	for name, done := range map[string]chan error{"Shutdown": stopped, "Wait": waited} {
+		select {
+		case err := <-done:
+			if err == nil || !strings.Contains(err.Error(), "server: drain incomplete") {
+				t.Errorf("%s returned %v, want the server hook error", name, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s did not return after the hooks finished", name)
+		}
+	}
+	if got := runs.Load(); got != 1 {
+		t.Errorf("server hook ran %d times, want 1", got)
+	}
+}
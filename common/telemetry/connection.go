@@ -0,0 +1,82 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/narender/common/telemetry/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// collectorConns holds one gRPC connection per collector endpoint, shared by the
+// OTLP exporters sending to it. The exporters do not close connections they were
+// given, so Shutdown closes them.
+type collectorConns struct {
+	mu       sync.Mutex
+	opts     []grpc.DialOption
+	byTarget map[string]*grpc.ClientConn
+}
+
+var otlpConns = &collectorConns{byTarget: make(map[string]*grpc.ClientConn)}
+
+// get returns the connection for endpoint, creating it on first use. Creating a
+// connection does not dial; that happens on the first export or on warm-up.
+func (c *collectorConns) get(endpoint string) (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if conn, ok := c.byTarget[endpoint]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.NewClient(endpoint, c.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC connection to %s: %w", endpoint, err)
+	}
+	c.byTarget[endpoint] = conn
+	return conn, nil
+}
+
+func (c *collectorConns) closeAll() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	for endpoint, conn := range c.byTarget {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close connection to %s: %w", endpoint, err))
+		}
+		delete(c.byTarget, endpoint)
+	}
+	return errors.Join(errs...)
+}
+
+// watchConnection keeps the connected gauge for signal in step with conn until the
+// connection is closed. With warmup it starts connecting right away, without
+// blocking the caller, and logs once the collector is reachable.
+func watchConnection(conn *grpc.ClientConn, signal string, warmup bool) {
+	if warmup {
+		conn.Connect()
+	}
+	start := time.Now()
+	announced := false
+
+	state := conn.GetState()
+	for {
+		ready := state == connectivity.Ready
+		metric.SetExporterConnected(signal, ready)
+		if ready && warmup && !announced {
+			announced = true
+			log.Printf("OTLP %s exporter connected to %s after %s.\n", signal, conn.Target(), time.Since(start).Round(time.Millisecond))
+		}
+		if state == connectivity.Shutdown {
+			return
+		}
+		if !conn.WaitForStateChange(context.Background(), state) {
+			return
+		}
+		state = conn.GetState()
+	}
+}
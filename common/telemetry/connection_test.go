@@ -0,0 +1,119 @@
+package telemetry
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/narender/common/telemetry/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestCollectorConnsSharePerEndpoint(t *testing.T) {
+	conns := &collectorConns{
+		opts:     []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+		byTarget: make(map[string]*grpc.ClientConn),
+	}
+	t.Cleanup(func() { conns.closeAll() })
+
+	signals := []struct {
+		signal   string
+		endpoint string
+	}{
+		{signal: "traces", endpoint: "traces.collector:4317"},
+		{signal: "metrics", endpoint: "collector:4317"},
+		{signal: "logs", endpoint: "collector:4317"},
+	}
+
+	got := make(map[string]*grpc.ClientConn)
+	for _, s := range signals {
+		conn, err := conns.get(s.endpoint)
+		if err != nil {
+			t.Fatalf("get(%q): %v", s.endpoint, err)
+		}
+		if conn.Target() != s.endpoint {
+			t.Errorf("%s connection targets %q, want %q", s.signal, conn.Target(), s.endpoint)
+		}
+		got[s.signal] = conn
+	}
+
+	if got["metrics"] != got["logs"] {
+		t.Errorf("metrics and logs use separate connections to the same endpoint")
+	}
+	if got["traces"] == got["metrics"] {
+		t.Errorf("traces share a connection with metrics despite their own endpoint")
+	}
+
+	if err := conns.closeAll(); err != nil {
+		t.Fatalf("closeAll: %v", err)
+	}
+	if len(conns.byTarget) != 0 {
+		t.Errorf("%d connections left after closeAll", len(conns.byTarget))
+	}
+}
+
+// waitForConnected polls the connected state recorded for signal until it is want.
+func waitForConnected(t *testing.T, signal string, want bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for metric.ExporterConnections()[signal] != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s connected = %v after 5s, want %v", signal, !want, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatchConnectionTracksTheCollector(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	tests := []struct {
+		name   string
+		signal string
+		warmup bool
+	}{
+		{name: "warm-up connects without an export", signal: "warmup_test", warmup: true},
+		{name: "without warm-up the connection stays idle", signal: "idle_test", warmup: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				t.Fatalf("create connection: %v", err)
+			}
+			metric.SetExporterConnected(tt.signal, false)
+			done := make(chan struct{})
+			go func() {
+				watchConnection(conn, tt.signal, tt.warmup)
+				close(done)
+			}()
+
+			if tt.warmup {
+				waitForConnected(t, tt.signal, true)
+			} else {
+				time.Sleep(200 * time.Millisecond)
+				if metric.ExporterConnections()[tt.signal] {
+					t.Errorf("%s connected without warm-up or an export", tt.signal)
+				}
+			}
+
+			conn.Close()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("watcher still running after the connection closed")
+			}
+			if metric.ExporterConnections()[tt.signal] {
+				t.Errorf("%s still reported connected after the connection closed", tt.signal)
+			}
+		})
+	}
+}
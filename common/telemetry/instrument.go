@@ -0,0 +1,49 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	apierrors "github.com/narender/common/apierrors"
+	"github.com/narender/common/telemetry/metric"
+	commontrace "github.com/narender/common/telemetry/trace"
+)
+
+// Instrument runs fn inside a span, records its duration in the operation
+// duration histogram and counts returned errors. operation is expected in the
+// form "component.operation" (e.g. "product_service.get_by_category"); the part
+// before the first dot is used as the component.
+func Instrument[T any](ctx context.Context, operation string, fn func(context.Context) (T, error)) (result T, err error) {
+	component, opName := splitOperation(operation)
+
+	ctx, span := commontrace.StartSpan(ctx, component, opName)
+	start := time.Now()
+	defer func() {
+		metric.RecordOperationDuration(ctx, float64(time.Since(start).Microseconds())/1000.0, opName, component)
+		if err != nil {
+			metric.IncrementErrorCount(ctx, errorCode(err), opName, component)
+		}
+		commontrace.EndSpan(span, &err, nil)
+	}()
+
+	return fn(ctx)
+}
+
+// splitOperation separates "component.operation" into its parts.
+func splitOperation(operation string) (component, opName string) {
+	if i := strings.Index(operation, "."); i > 0 {
+		return operation[:i], operation[i+1:]
+	}
+	return "instrumented", operation
+}
+
+// errorCode returns the AppError code for err, or ErrCodeUnknown.
+func errorCode(err error) string {
+	var appErr *apierrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return apierrors.ErrCodeUnknown
+}
@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	apierrors "github.com/narender/common/apierrors"
+	"github.com/narender/common/telemetry/metric"
+	"github.com/narender/common/telemetry/telemetrytest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var harness *telemetrytest.Harness
+
+func TestMain(m *testing.M) {
+	harness = telemetrytest.NewTestHarness()
+	code := m.Run()
+	harness.Teardown()
+	os.Exit(code)
+}
+
+func TestInstrument(t *testing.T) {
+	tests := []struct {
+		name       string
+		operation  string
+		err        error
+		wantStatus codes.Code
+		wantCode   string
+	}{
+		{name: "success", operation: "instrument_test.succeed", wantStatus: codes.Ok},
+		{name: "application error", operation: "instrument_test.fail_db",
+			err:        apierrors.NewApplicationError(apierrors.ErrCodeDatabaseAccess, "read failed", errors.New("disk")),
+			wantStatus: codes.Error, wantCode: apierrors.ErrCodeDatabaseAccess},
+		{name: "plain error", operation: "instrument_test.fail_plain",
+			err: errors.New("boom"), wantStatus: codes.Error, wantCode: apierrors.ErrCodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			harness.Reset()
+			component, opName := splitOperation(tt.operation)
+
+			got, err := Instrument(context.Background(), tt.operation, func(ctx context.Context) (int, error) {
+				return 42, tt.err
+			})
+			if got != 42 || err != tt.err {
+				t.Fatalf("Instrument = %d, %v, want 42, %v", got, err, tt.err)
+			}
+
+			spans := harness.SpansNamed(component + " :: " + opName)
+			if len(spans) != 1 {
+				t.Fatalf("recorded %d spans, want 1", len(spans))
+			}
+			if spans[0].Status.Code != tt.wantStatus {
+				t.Errorf("span status = %v, want %v", spans[0].Status.Code, tt.wantStatus)
+			}
+
+			op := attribute.String(metric.AttrOperation, opName)
+			if _, ok := harness.MetricValueWith(metric.AppOperationDurationMetric, op); !ok {
+				t.Errorf("no %s recorded for %s", metric.AppOperationDurationMetric, opName)
+			}
+			count, counted := harness.MetricValueWith(metric.AppErrorCountMetric, op)
+			switch {
+			case tt.wantCode == "" && counted:
+				t.Errorf("%s = %v for a successful call, want none", metric.AppErrorCountMetric, count)
+			case tt.wantCode != "":
+				byCode, _ := harness.MetricValueWith(metric.AppErrorCountMetric, op,
+					attribute.String(metric.AttrErrorType, tt.wantCode),
+					attribute.String(metric.AttrComponent, component))
+				if byCode != 1 {
+					t.Errorf("%s{error.type=%s} = %v, want 1", metric.AppErrorCountMetric, tt.wantCode, byCode)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitOperation(t *testing.T) {
+	tests := []struct {
+		operation     string
+		wantComponent string
+		wantOp        string
+	}{
+		{operation: "product_service.get_by_category", wantComponent: "product_service", wantOp: "get_by_category"},
+		{operation: "cache.load.products", wantComponent: "cache", wantOp: "load.products"},
+		{operation: "reindex", wantComponent: "instrumented", wantOp: "reindex"},
+		{operation: ".leading_dot", wantComponent: "instrumented", wantOp: ".leading_dot"},
+	}
+	for _, tt := range tests {
+		component, op := splitOperation(tt.operation)
+		if component != tt.wantComponent || op != tt.wantOp {
+			t.Errorf("splitOperation(%q) = %q, %q, want %q, %q", tt.operation, component, op, tt.wantComponent, tt.wantOp)
+		}
+	}
+}
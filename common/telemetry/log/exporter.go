@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/narender/common/config"
+	"github.com/narender/common/telemetry/pipeline"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
 	logger "go.opentelemetry.io/otel/log/global"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
@@ -13,23 +14,23 @@ import (
 	"google.golang.org/grpc"
 )
 
-func SetupOtlpLogExporter(ctx context.Context, cfg *config.Config, connOpts []grpc.DialOption, res *sdkresource.Resource) error {
+func SetupOtlpLogExporter(ctx context.Context, cfg *config.Config, conn *grpc.ClientConn, res *sdkresource.Resource) error {
 	logExporter, err := otlploggrpc.New(ctx,
-		otlploggrpc.WithEndpoint(cfg.OTEL_ENDPOINT),
-		otlploggrpc.WithDialOption(connOpts...),
-		otlploggrpc.WithInsecure(),
+		otlploggrpc.WithGRPCConn(conn),
+		otlploggrpc.WithHeaders(cfg.OtlpHeaders()),
+		otlploggrpc.WithTimeout(cfg.OTEL_EXPORT_TIMEOUT),
 	)
-	fmt.Println("OTEL_ENDPOINT :: ", cfg.OTEL_ENDPOINT)
 	if err != nil {
 		return fmt.Errorf("failed to create OTLP log exporter: %w", err)
 	}
 
-	logProcessor := sdklog.NewBatchProcessor(logExporter)
+	logProcessor := sdklog.NewBatchProcessor(pipeline.WrapLogExporter(logExporter))
 	loggerProvider := sdklog.NewLoggerProvider(
 		sdklog.WithResource(res),
+		sdklog.WithProcessor(pipeline.LogCounter{}),
 		sdklog.WithProcessor(logProcessor),
 	)
 	logger.SetLoggerProvider(loggerProvider)
-	log.Println("OTel LoggerProvider initialized and set globally.")
+	log.Printf("OTel LoggerProvider initialized and set globally (endpoint %s).\n", cfg.LogsEndpoint())
 	return nil
 }
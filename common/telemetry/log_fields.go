@@ -0,0 +1,101 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+)
+
+type logFieldsKey struct{}
+
+// WithLogFields returns a context carrying attrs for every log call made with it
+// (or a context derived from it) through a handler wrapped by NewContextHandler.
+// A field replaces an earlier one with the same key.
+func WithLogFields(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing := LogFields(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	for _, field := range existing {
+		if !hasKey(attrs, field.Key) {
+			merged = append(merged, field)
+		}
+	}
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, logFieldsKey{}, merged)
+}
+
+// WithOperation stores the component and operation log fields on ctx, so log calls
+// below it no longer need to repeat them.
+func WithOperation(ctx context.Context, component, operation string) context.Context {
+	return WithLogFields(ctx,
+		slog.String("component", component),
+		slog.String("operation", operation))
+}
+
+// LogFields returns the log fields stored on ctx.
+func LogFields(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(logFieldsKey{}).([]slog.Attr)
+	return fields
+}
+
+// contextHandler adds the log fields stored on the record's context. Attributes
+// set explicitly on the record or the logger take precedence over context fields.
+type contextHandler struct {
+	next       slog.Handler
+	loggerKeys map[string]bool
+}
+
+// NewContextHandler wraps next so records carry the fields stored with WithLogFields.
+func NewContextHandler(next slog.Handler) slog.Handler {
+	return &contextHandler{next: next}
+}
+
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := LogFields(ctx)
+	if len(fields) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	present := make(map[string]bool, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		present[a.Key] = true
+		return true
+	})
+
+	r = r.Clone()
+	for _, field := range fields {
+		if !present[field.Key] && !h.loggerKeys[field.Key] {
+			r.AddAttrs(field)
+		}
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	keys := make(map[string]bool, len(h.loggerKeys)+len(attrs))
+	for key := range h.loggerKeys {
+		keys[key] = true
+	}
+	for _, a := range attrs {
+		keys[a.Key] = true
+	}
+	return &contextHandler{next: h.next.WithAttrs(attrs), loggerKeys: keys}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{next: h.next.WithGroup(name), loggerKeys: h.loggerKeys}
+}
+
+func hasKey(attrs []slog.Attr, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
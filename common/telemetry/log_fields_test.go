@@ -0,0 +1,94 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+// logOnce logs msg through a context handler and returns the record's fields,
+// leaving out time, level and msg.
+func logOnce(t *testing.T, log func(logger *slog.Logger)) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+	log(logger)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode log record %q: %v", buf.String(), err)
+	}
+	delete(record, "time")
+	delete(record, "level")
+	delete(record, "msg")
+	return record
+}
+
+func TestContextHandlerAddsLogFields(t *testing.T) {
+	request := WithLogFields(context.Background(), slog.String("request_id", "req-1"))
+	service := WithOperation(request, "product_service", "buy")
+
+	tests := []struct {
+		name string
+		log  func(logger *slog.Logger)
+		want map[string]any
+	}{
+		{
+			name: "no fields",
+			log:  func(l *slog.Logger) { l.InfoContext(context.Background(), "hello") },
+			want: map[string]any{},
+		},
+		{
+			name: "request fields",
+			log:  func(l *slog.Logger) { l.InfoContext(request, "hello") },
+			want: map[string]any{"request_id": "req-1"},
+		},
+		{
+			name: "operation added below the request",
+			log:  func(l *slog.Logger) { l.InfoContext(service, "hello") },
+			want: map[string]any{"request_id": "req-1", "component": "product_service", "operation": "buy"},
+		},
+		{
+			name: "nested operation replaces the outer one",
+			log: func(l *slog.Logger) {
+				l.InfoContext(WithOperation(service, "product_repository", "update_stock"), "hello")
+			},
+			want: map[string]any{"request_id": "req-1", "component": "product_repository", "operation": "update_stock"},
+		},
+		{
+			name: "record attributes win",
+			log:  func(l *slog.Logger) { l.InfoContext(service, "hello", slog.String("operation", "validate")) },
+			want: map[string]any{"request_id": "req-1", "component": "product_service", "operation": "validate"},
+		},
+		{
+			name: "logger attributes win",
+			log:  func(l *slog.Logger) { l.With(slog.String("component", "file_database")).InfoContext(service, "hello") },
+			want: map[string]any{"request_id": "req-1", "component": "file_database", "operation": "buy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logOnce(t, tt.log); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("log fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithLogFieldsLeavesTheParentUnchanged(t *testing.T) {
+	parent := WithOperation(context.Background(), "product_service", "buy")
+	WithOperation(parent, "product_repository", "update_stock")
+
+	got := make(map[string]string)
+	for _, field := range LogFields(parent) {
+		got[field.Key] = field.Value.String()
+	}
+	want := map[string]string{"component": "product_service", "operation": "buy"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parent fields = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package metric
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// OverflowValue replaces attribute values beyond the cardinality limit.
+const OverflowValue = "other"
+
+// defaultCardinalityLimit is used until SetCardinalityLimit is called.
+const defaultCardinalityLimit = 100
+
+var (
+	cardinalityMu     sync.Mutex
+	cardinalityLimit  = defaultCardinalityLimit
+	cardinalitySeen   = make(map[string]map[string]struct{})
+	cardinalityWarned = make(map[string]bool)
+)
+
+// SetCardinalityLimit sets the number of distinct values kept per guarded attribute.
+// A non-positive limit disables the guard.
+func SetCardinalityLimit(limit int) {
+	cardinalityMu.Lock()
+	defer cardinalityMu.Unlock()
+	cardinalityLimit = limit
+}
+
+// guardCardinality returns value if it is already known for key or the key is still
+// under the limit; otherwise it returns OverflowValue and warns once per key.
+func guardCardinality(key, value string) string {
+	cardinalityMu.Lock()
+	defer cardinalityMu.Unlock()
+
+	if cardinalityLimit <= 0 {
+		return value
+	}
+
+	seen, ok := cardinalitySeen[key]
+	if !ok {
+		seen = make(map[string]struct{})
+		cardinalitySeen[key] = seen
+	}
+	if _, ok := seen[value]; ok {
+		return value
+	}
+	if len(seen) < cardinalityLimit {
+		seen[value] = struct{}{}
+		return value
+	}
+
+	if !cardinalityWarned[key] {
+		cardinalityWarned[key] = true
+		slog.Warn("Metric attribute cardinality limit reached; further values are reported as \"other\"",
+			slog.String("attribute", key),
+			slog.Int("limit", cardinalityLimit))
+	}
+	return OverflowValue
+}
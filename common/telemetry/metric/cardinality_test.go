@@ -0,0 +1,99 @@
+package metric
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// freshCardinalityGuard clears the values seen by the guard and sets limit for the
+// rest of the test. Warnings logged by the guard go to the returned buffer.
+func freshCardinalityGuard(t *testing.T, limit int) *bytes.Buffer {
+	t.Helper()
+	reset := func() {
+		cardinalityMu.Lock()
+		defer cardinalityMu.Unlock()
+		cardinalitySeen = make(map[string]map[string]struct{})
+		cardinalityWarned = make(map[string]bool)
+	}
+	reset()
+	SetCardinalityLimit(limit)
+
+	logs := &bytes.Buffer{}
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(logs, nil)))
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		reset()
+		SetCardinalityLimit(defaultCardinalityLimit)
+	})
+	return logs
+}
+
+func TestGuardCardinality(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		values []string
+		want   []string
+	}{
+		{name: "under the limit", limit: 3, values: []string{"a", "b", "a"}, want: []string{"a", "b", "a"}},
+		{name: "overflow collapses to other", limit: 2, values: []string{"a", "b", "c", "d"}, want: []string{"a", "b", OverflowValue, OverflowValue}},
+		{name: "known values keep reporting", limit: 2, values: []string{"a", "b", "c", "a", "b"}, want: []string{"a", "b", OverflowValue, "a", "b"}},
+		{name: "guard disabled", limit: 0, values: []string{"a", "b", "c"}, want: []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			freshCardinalityGuard(t, tt.limit)
+			for i, value := range tt.values {
+				if got := guardCardinality(AttrProductName, value); got != tt.want[i] {
+					t.Errorf("value %d: guardCardinality(%q) = %q, want %q", i, value, got, tt.want[i])
+				}
+			}
+			// Each attribute has its own budget
+			if got := guardCardinality("other.attribute", "z"); got != "z" {
+				t.Errorf("guardCardinality on a second attribute = %q, want %q", got, "z")
+			}
+		})
+	}
+}
+
+func TestSaleMetricsCollapseExcessProductNames(t *testing.T) {
+	const limit = 5
+	logs := freshCardinalityGuard(t, limit)
+	ctx := context.Background()
+
+	names := make([]string, limit+3)
+	for i := range names {
+		names[i] = fmt.Sprintf("Cardinality Product %d", i)
+	}
+	otherRevenue, _ := harness.MetricValueWith(AppRevenueTotalMetric, attribute.String(AttrProductName, OverflowValue))
+	otherSold, _ := harness.MetricValueWith(AppItemsSoldCountMetric, attribute.String(AttrProductName, OverflowValue))
+
+	for _, name := range names {
+		IncrementRevenueTotal(ctx, 100, name, "Guarded")
+		IncrementItemsSoldCount(ctx, 1, name, "Guarded")
+	}
+
+	for i, name := range names {
+		_, found := harness.MetricValueWith(AppItemsSoldCountMetric, attribute.String(AttrProductName, name))
+		if wantFound := i < limit; found != wantFound {
+			t.Errorf("%s reported for %q = %v, want %v", AppItemsSoldCountMetric, name, found, wantFound)
+		}
+	}
+	if got, _ := harness.MetricValueWith(AppItemsSoldCountMetric, attribute.String(AttrProductName, OverflowValue)); got-otherSold != 3 {
+		t.Errorf("%s{%s=%q} rose by %v, want 3", AppItemsSoldCountMetric, AttrProductName, OverflowValue, got-otherSold)
+	}
+	if got, _ := harness.MetricValueWith(AppRevenueTotalMetric, attribute.String(AttrProductName, OverflowValue)); got-otherRevenue != 3 {
+		t.Errorf("%s{%s=%q} rose by %v, want 3", AppRevenueTotalMetric, AttrProductName, OverflowValue, got-otherRevenue)
+	}
+	if warnings := strings.Count(logs.String(), "cardinality limit reached"); warnings != 1 {
+		t.Errorf("logged %d cardinality warnings, want 1:\n%s", warnings, logs.String())
+	}
+}
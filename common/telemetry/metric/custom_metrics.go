@@ -11,10 +11,30 @@ const (
 	floatCounterType    metricType = "float_counter"
 
 	// Define metric names as constants for type safety and easier refactoring
-	ProductStockCountMetric = "app.product.stock.count"
-	AppRevenueTotalMetric   = "app.revenue.total"
-	AppItemsSoldCountMetric = "app.items.sold.count"
-	AppErrorCountMetric     = "app.error.count"
+	ProductStockCountMetric    = "app.product.stock.count"
+	AppRevenueTotalMetric      = "app.revenue.total"
+	AppItemsSoldCountMetric    = "app.items.sold.count"
+	AppErrorCountMetric        = "app.error.count"
+	AppOperationDurationMetric = "app.operation.duration"
+	AppErrorsTotalMetric       = "app.errors.total"
+	ProductsPerCategoryMetric  = "app.product.category.count"
+	AppHTTPRequestCountMetric  = "app.http.request.count"
+	DBFileAgeMetric            = "app.db.file.age"
+	ExportChunkedMetric        = "otel.export.chunked" // exported to Prometheus as otel_export_chunked_total
+	MaintenanceBlockedMetric   = "app.maintenance.blocked.count"
+	CatalogSizeMetric          = "app.catalog.size"
+	PanicRecoveredMetric       = "app.panic.recovered" // exported to Prometheus as app_panic_recovered_total
+	ProcessUptimeMetric        = "app.process.uptime"
+	ProductsByPriceBandMetric  = "app.products.by_price_band" // exported to Prometheus as app_products_by_price_band
+	OpenSpansMetric            = "otel.spans.open"
+	ExporterConnectedMetric    = "otel.exporter.connected"
+	LowStockProductsMetric     = "app.products.below_threshold"
+	ValidationFailuresMetric   = "app.request.validation.failures" // exported to Prometheus as app_request_validation_failures_total
+	BuyRequestsMetric          = "app.buy.requests"                // exported to Prometheus as app_buy_requests_total
+	BuyValidatedMetric         = "app.buy.validated"               // exported to Prometheus as app_buy_validated_total
+	BuyStockAvailableMetric    = "app.buy.stock_available"         // exported to Prometheus as app_buy_stock_available_total
+	BuyCompletedMetric         = "app.buy.completed"               // exported to Prometheus as app_buy_completed_total
+	SinceLastSaleMetric        = "app.product.seconds_since_last_sale"
 
 	// Standard attribute names
 	AttrProductName     = "product.name"
@@ -26,6 +46,16 @@ const (
 	AttrOperation       = "operation"
 	AttrComponent       = "component"
 	AttrCustomMetric    = "custom.metric"
+	AttrErrorClass      = "error.class"
+	AttrErrorCode       = "error.code"
+	AttrHTTPRoute       = "http.route"
+	AttrHTTPMethod      = "http.request.method"
+	AttrHTTPStatusCode  = "http.response.status_code"
+	AttrDBFilePath      = "db.file.path"
+	AttrSignal          = "otel.signal"
+	AttrPriceBand       = "product.price_band"
+	// Per-request correlation ID for logs and spans; dropped from every metric stream
+	AttrOperationID = "operation.id"
 )
 
 // --- Metric Configuration Types ---
@@ -45,6 +75,51 @@ var metricDefinitions = map[string]metricConfig{
 		Unit:        "{item}",
 		Type:        observableGaugeType,
 	},
+	ProductsPerCategoryMetric: {
+		Description: "Number of distinct products in each category. Attributes: product.category",
+		Unit:        "{product}",
+		Type:        observableGaugeType,
+	},
+	DBFileAgeMetric: {
+		Description: "Seconds since the data file was last modified. Attributes: db.file.path",
+		Unit:        "s",
+		Type:        observableGaugeType,
+	},
+	CatalogSizeMetric: {
+		Description: "Number of products in the catalog as of the last full read; zero flags an empty or wiped data file",
+		Unit:        "{product}",
+		Type:        observableGaugeType,
+	},
+	ProductsByPriceBandMetric: {
+		Description: "Number of products in each price band as of the last full catalog read. Attributes: product.price_band",
+		Unit:        "{product}",
+		Type:        observableGaugeType,
+	},
+	OpenSpansMetric: {
+		Description: "Spans started but not yet ended; steady growth means a span is never ended",
+		Unit:        "{span}",
+		Type:        observableGaugeType,
+	},
+	ExporterConnectedMetric: {
+		Description: "1 while the OTLP exporter has a ready collector connection, else 0. Attributes: otel.signal",
+		Unit:        "1",
+		Type:        observableGaugeType,
+	},
+	LowStockProductsMetric: {
+		Description: "Products whose stock is below their category's low-stock threshold. Attributes: product.category",
+		Unit:        "{product}",
+		Type:        observableGaugeType,
+	},
+	SinceLastSaleMetric: {
+		Description: "Seconds since each product last sold, or since process start if it has not sold yet; high values flag stale inventory. Attributes: product.name, product.category",
+		Unit:        "s",
+		Type:        observableGaugeType,
+	},
+	ProcessUptimeMetric: {
+		Description: "Seconds since the process started; the last value before shutdown gives the session length",
+		Unit:        "s",
+		Type:        observableGaugeType,
+	},
 	AppRevenueTotalMetric: {
 		Description: "Total revenue generated from product sales. Attributes: product.name, product.category, currency_code",
 		Unit:        "1",
@@ -60,4 +135,59 @@ var metricDefinitions = map[string]metricConfig{
 		Unit:        "{error}",
 		Type:        counterType,
 	},
+	AppErrorsTotalMetric: {
+		Description: "Count of errors returned to clients by triage class (client, business, system). Attributes: error.class, error.code",
+		Unit:        "{error}",
+		Type:        counterType,
+	},
+	AppHTTPRequestCountMetric: {
+		Description: "Count of HTTP requests by route template, method and status. Attributes: http.route, http.request.method, http.response.status_code",
+		Unit:        "{request}",
+		Type:        counterType,
+	},
+	ExportChunkedMetric: {
+		Description: "Export batches split into smaller chunks to stay under the payload limit. Attributes: otel.signal",
+		Unit:        "{batch}",
+		Type:        counterType,
+	},
+	MaintenanceBlockedMetric: {
+		Description: "Write requests rejected while maintenance mode is on. Attributes: http.route, http.request.method",
+		Unit:        "{request}",
+		Type:        counterType,
+	},
+	ValidationFailuresMetric: {
+		Description: "Requests rejected for malformed or invalid input. Attributes: http.route, http.request.method",
+		Unit:        "{request}",
+		Type:        counterType,
+	},
+	BuyRequestsMetric: {
+		Description: "Purchase funnel: purchase requests received by the service",
+		Unit:        "{request}",
+		Type:        counterType,
+	},
+	BuyValidatedMetric: {
+		Description: "Purchase funnel: purchases whose quantity passed validation",
+		Unit:        "{request}",
+		Type:        counterType,
+	},
+	BuyStockAvailableMetric: {
+		Description: "Purchase funnel: validated purchases of an existing product with enough stock",
+		Unit:        "{request}",
+		Type:        counterType,
+	},
+	BuyCompletedMetric: {
+		Description: "Purchase funnel: purchases completed with stock updated and revenue recorded",
+		Unit:        "{request}",
+		Type:        counterType,
+	},
+	PanicRecoveredMetric: {
+		Description: "Panics caught by the recovery middleware. Attributes: http.route, http.request.method",
+		Unit:        "{panic}",
+		Type:        counterType,
+	},
+	AppOperationDurationMetric: {
+		Description: "Duration of instrumented operations. Attributes: operation, component",
+		Unit:        "ms",
+		Type:        histogramType,
+	},
 }
@@ -7,29 +7,43 @@ import (
 	"time"
 
 	"github.com/narender/common/config"
+	"github.com/narender/common/telemetry/pipeline"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
 	"google.golang.org/grpc"
 )
 
-func SetupOtlpMetricExporter(ctx context.Context, cfg *config.Config, connOpts []grpc.DialOption, res *sdkresource.Resource) error {
+// dropCorrelationAttributes strips per-request correlation IDs from every metric
+// stream, so a stray attribute can never turn into a series per request.
+var dropCorrelationAttributes = sdkmetric.NewView(
+	sdkmetric.Instrument{Name: "*"},
+	sdkmetric.Stream{AttributeFilter: attribute.NewDenyKeysFilter(AttrOperationID)},
+)
+
+func SetupOtlpMetricExporter(ctx context.Context, cfg *config.Config, conn *grpc.ClientConn, res *sdkresource.Resource) error {
 	metricExporter, err := otlpmetricgrpc.New(ctx,
-		otlpmetricgrpc.WithEndpoint(cfg.OTEL_ENDPOINT),
-		otlpmetricgrpc.WithDialOption(connOpts...),
-		otlpmetricgrpc.WithInsecure(),
+		otlpmetricgrpc.WithGRPCConn(conn),
+		otlpmetricgrpc.WithHeaders(cfg.OtlpHeaders()),
+		otlpmetricgrpc.WithTimeout(cfg.OTEL_EXPORT_TIMEOUT),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create OTLP metric exporter: %w", err)
 	}
 
-	reader := sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(15*time.Second))
+	reader := sdkmetric.NewPeriodicReader(pipeline.WrapMetricExporter(metricExporter),
+		sdkmetric.WithInterval(15*time.Second),
+		sdkmetric.WithTimeout(cfg.OTEL_READER_TIMEOUT),
+	)
 	mp := sdkmetric.NewMeterProvider(
 		sdkmetric.WithResource(res),
 		sdkmetric.WithReader(reader),
+		sdkmetric.WithView(dropCorrelationAttributes),
 	)
 	otel.SetMeterProvider(mp)
+	Rebind()
 	log.Println("OTel MeterProvider initialized and set globally.")
 	return nil
 }
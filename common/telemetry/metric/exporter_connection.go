@@ -0,0 +1,58 @@
+package metric
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	exporterConnectedMu sync.RWMutex
+	// Connection state per signal; signals without an OTLP exporter are not reported
+	exporterConnected = make(map[string]bool)
+)
+
+// SetExporterConnected records whether the OTLP exporter for signal has a ready
+// connection to the collector.
+func SetExporterConnected(signal string, connected bool) {
+	exporterConnectedMu.Lock()
+	defer exporterConnectedMu.Unlock()
+	exporterConnected[signal] = connected
+}
+
+func observeExporterConnected(ctx context.Context, observer metric.Observer) error {
+	gauge, ok := gauges[ExporterConnectedMetric]
+	if !ok {
+		slog.ErrorContext(ctx, "Failed to find gauge instrument in callback", slog.String("metric", ExporterConnectedMetric))
+		return nil
+	}
+
+	exporterConnectedMu.RLock()
+	defer exporterConnectedMu.RUnlock()
+	for signal, connected := range exporterConnected {
+		var value int64
+		if connected {
+			value = 1
+		}
+		attrs := attribute.NewSet(
+			attribute.String(AttrSignal, signal),
+			attribute.String(AttrCustomMetric, "true"),
+		)
+		observer.ObserveInt64(gauge, value, metric.WithAttributeSet(attrs))
+	}
+	return nil
+}
+
+// ExporterConnections returns a copy of the connection state per signal.
+func ExporterConnections() map[string]bool {
+	exporterConnectedMu.RLock()
+	defer exporterConnectedMu.RUnlock()
+	snapshot := make(map[string]bool, len(exporterConnected))
+	for signal, connected := range exporterConnected {
+		snapshot[signal] = connected
+	}
+	return snapshot
+}
@@ -0,0 +1,90 @@
+package metric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caarlos0/env/v10"
+	"github.com/narender/common/config"
+	"github.com/narender/common/telemetry/telemetrytest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestMetricReaderUsesTheReaderTimeout(t *testing.T) {
+	cfg := &config.Config{}
+	if err := env.Parse(cfg); err != nil {
+		t.Fatalf("parse configuration: %v", err)
+	}
+	cfg.OTEL_EXPORT_TIMEOUT = time.Minute
+	cfg.OTEL_READER_TIMEOUT = 300 * time.Millisecond
+
+	// Without connect parameters gRPC keeps trying to connect for 20s, and the
+	// export timeout is longer still, so only the reader timeout can end it early
+	conn, err := grpc.NewClient(telemetrytest.SilentCollector(t), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	defer conn.Close()
+
+	previous := otel.GetMeterProvider()
+	if err := SetupOtlpMetricExporter(context.Background(), cfg, conn, sdkresource.Empty()); err != nil {
+		t.Fatalf("SetupOtlpMetricExporter: %v", err)
+	}
+	mp := otel.GetMeterProvider().(*sdkmetric.MeterProvider)
+	defer func() {
+		// Instruments follow the global provider, so rebind them to the harness
+		otel.SetMeterProvider(previous)
+		Rebind()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		mp.Shutdown(ctx)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	start := time.Now()
+	err = mp.ForceFlush(ctx)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Errorf("ForceFlush succeeded against a collector that never answers")
+	}
+	if elapsed > 3*time.Second {
+		t.Errorf("collection gave up after %s, want about the 300ms reader timeout", elapsed)
+	}
+}
+
+func TestOperationIDIsDroppedFromMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithView(dropCorrelationAttributes))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	counter, err := provider.Meter("exporter_test").Int64Counter("requests")
+	if err != nil {
+		t.Fatalf("create counter: %v", err)
+	}
+
+	for _, id := range []string{"5f2b9c1d0a7e4e33", "9c0d3e8a1b2f4c55"} {
+		counter.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String(AttrHTTPRoute, "/products"),
+			attribute.String(AttrOperationID, id)))
+	}
+
+	// Both operations land in the same series, which keeps only the route
+	points := dataPoints(t, reader, "requests")
+	if len(points) != 1 || points[0].Value != 2 {
+		t.Fatalf("requests = %+v, want one data point of 2", points)
+	}
+	if _, found := points[0].Attributes.Value(AttrOperationID); found {
+		t.Errorf("data point still carries %s", AttrOperationID)
+	}
+	if route, _ := points[0].Attributes.Value(AttrHTTPRoute); route.AsString() != "/products" {
+		t.Errorf("data point %s = %q, want /products", AttrHTTPRoute, route.AsString())
+	}
+}
@@ -3,8 +3,12 @@ package metric
 import (
 	"context"
 	"log/slog"
+	"math"
+	"os"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -17,63 +21,152 @@ type productStockDetail struct {
 	StockLevel      int64
 	ProductName     string
 	ProductCategory string
+	// Zero until the product sells in this process
+	LastSale time.Time
 }
 
+// meterName is the instrumentation scope of all instruments in this package.
+const meterName = "common/telemetry/metric"
+
 var (
-	meter           = otel.Meter("common/telemetry/metric")
-	counters        = make(map[string]metric.Int64Counter)
-	float64Counters = make(map[string]metric.Float64Counter)
-	histograms      = make(map[string]metric.Float64Histogram)
-	gauges          = make(map[string]metric.Int64ObservableGauge)
+	counters        map[string]metric.Int64Counter
+	float64Counters map[string]metric.Float64Counter
+	histograms      map[string]metric.Float64Histogram
+	gauges          map[string]metric.Int64ObservableGauge
+
+	// Provider the instruments above were created on, and their gauge callbacks
+	bindMu        sync.Mutex
+	boundProvider metric.MeterProvider
+	registrations []metric.Registration
 
 	// Storage for latest product stock levels for the observable gauge
 	// Key is productName
 	latestProductStock      = make(map[string]productStockDetail)
 	latestProductStockMutex sync.RWMutex
+
+	// Path of the data file whose age is reported by the file age gauge
+	dbFilePath      string
+	dbFilePathMutex sync.RWMutex
+
+	// Revenue recorded by this process in integer cents
+	revenueTotalCents atomic.Int64
+
+	// Process start time for the uptime gauge
+	processStart = time.Now()
+
+	// Catalog size from the last full read; not reported until known
+	catalogSize      atomic.Int64
+	catalogSizeKnown atomic.Bool
 )
 
 // --- Initialization ---
 
 func init() {
+	bindInstruments(otel.GetMeterProvider())
+}
+
+// Rebind registers all instruments against the current global MeterProvider. Call it
+// after installing the SDK provider and before serving traffic: instruments created at
+// package init are bound to whatever provider existed then. Rebinding to the provider
+// already in use is a no-op.
+func Rebind() {
+	bindInstruments(otel.GetMeterProvider())
+}
+
+// bindInstruments creates every instrument in metricDefinitions on a meter from
+// provider and swaps them in, unregistering gauge callbacks from the previous binding
+// so observations are not reported twice.
+func bindInstruments(provider metric.MeterProvider) {
+	bindMu.Lock()
+	defer bindMu.Unlock()
+
+	if boundProvider != nil && provider == boundProvider {
+		return
+	}
+	for _, registration := range registrations {
+		if err := registration.Unregister(); err != nil {
+			slog.Warn("Failed to unregister gauge callback", slog.Any("error", err))
+		}
+	}
+
+	meter := provider.Meter(meterName)
+	newCounters := make(map[string]metric.Int64Counter)
+	newFloat64Counters := make(map[string]metric.Float64Counter)
+	newHistograms := make(map[string]metric.Float64Histogram)
+	newGauges := make(map[string]metric.Int64ObservableGauge)
+	var newRegistrations []metric.Registration
+
 	for name, cfg := range metricDefinitions { // metricDefinitions is defined in custom_metrics.go
 		switch cfg.Type {
 		case counterType: // counterType is defined in custom_metrics.go
-			counter := createInt64Counter(name, cfg.Description, cfg.Unit)
+			counter := createInt64Counter(meter, name, cfg.Description, cfg.Unit)
 			if counter != nil {
-				counters[name] = counter
+				newCounters[name] = counter
 			}
 		case histogramType: // histogramType is defined in custom_metrics.go
-			histogram := createFloat64Histogram(name, cfg.Description, cfg.Unit)
+			histogram := createFloat64Histogram(meter, name, cfg.Description, cfg.Unit)
 			if histogram != nil {
-				histograms[name] = histogram
+				newHistograms[name] = histogram
 			}
 		case observableGaugeType: // observableGaugeType is defined in custom_metrics.go
-			gauge := createInt64ObservableGauge(name, cfg.Description, cfg.Unit)
+			gauge := createInt64ObservableGauge(meter, name, cfg.Description, cfg.Unit)
 			if gauge != nil {
-				gauges[name] = gauge
-				if name == ProductStockCountMetric {
-					_, err := meter.RegisterCallback(observeProductStock, gauge)
+				newGauges[name] = gauge
+				var callback metric.Callback
+				switch name {
+				case ProductStockCountMetric:
+					callback = observeProductStock
+				case ProductsPerCategoryMetric:
+					callback = observeProductsPerCategory
+				case DBFileAgeMetric:
+					callback = observeDBFileAge
+				case CatalogSizeMetric:
+					callback = observeCatalogSize
+				case ProcessUptimeMetric:
+					callback = observeProcessUptime
+				case ProductsByPriceBandMetric:
+					callback = observeProductsByPriceBand
+				case OpenSpansMetric:
+					callback = observeOpenSpans
+				case ExporterConnectedMetric:
+					callback = observeExporterConnected
+				case LowStockProductsMetric:
+					callback = observeLowStockProducts
+				case SinceLastSaleMetric:
+					callback = observeSinceLastSale
+				}
+				if callback != nil {
+					registration, err := meter.RegisterCallback(callback, gauge)
 					if err != nil {
 						slog.Error("Failed to register callback for gauge", slog.String("metric", name), slog.Any("error", err))
+					} else {
+						newRegistrations = append(newRegistrations, registration)
 					}
 				}
 			}
 		case floatCounterType: // New case
-			counter := createFloat64Counter(name, cfg.Description, cfg.Unit)
+			counter := createFloat64Counter(meter, name, cfg.Description, cfg.Unit)
 			if counter != nil {
-				float64Counters[name] = counter
+				newFloat64Counters[name] = counter
 			}
 		default:
 			slog.Warn("Unknown metric type in configuration", slog.String("metric", name), slog.String("type", string(cfg.Type)))
 		}
 	}
+
+	counters = newCounters
+	float64Counters = newFloat64Counters
+	histograms = newHistograms
+	gauges = newGauges
+	registrations = newRegistrations
+	boundProvider = provider
 }
 
 // --- Public Functions / Constructors ---
 
 // --- Helper Functions ---
 
-func createInt64Counter(name, description, unit string) metric.Int64Counter {
+func createInt64Counter(meter metric.Meter, name, description, unit string) metric.Int64Counter {
 	counter, err := meter.Int64Counter(
 		name,
 		metric.WithDescription(description),
@@ -85,7 +178,7 @@ func createInt64Counter(name, description, unit string) metric.Int64Counter {
 	return counter
 }
 
-func createFloat64Histogram(name, description, unit string) metric.Float64Histogram {
+func createFloat64Histogram(meter metric.Meter, name, description, unit string) metric.Float64Histogram {
 	histogram, err := meter.Float64Histogram(
 		name,
 		metric.WithDescription(description),
@@ -97,7 +190,7 @@ func createFloat64Histogram(name, description, unit string) metric.Float64Histog
 	return histogram
 }
 
-func createInt64ObservableGauge(name, description, unit string) metric.Int64ObservableGauge {
+func createInt64ObservableGauge(meter metric.Meter, name, description, unit string) metric.Int64ObservableGauge {
 	gauge, err := meter.Int64ObservableGauge(
 		name,
 		metric.WithDescription(description),
@@ -109,7 +202,7 @@ func createInt64ObservableGauge(name, description, unit string) metric.Int64Obse
 	return gauge
 }
 
-func createFloat64Counter(name, description, unit string) metric.Float64Counter {
+func createFloat64Counter(meter metric.Meter, name, description, unit string) metric.Float64Counter {
 	counter, err := meter.Float64Counter(
 		name,
 		metric.WithDescription(description),
@@ -147,6 +240,152 @@ func observeProductStock(ctx context.Context, observer metric.Observer) error {
 	return nil
 }
 
+// observeSinceLastSale reports, per product in the stock snapshot, the seconds since
+// its last sale. Products that have not sold in this process report the uptime.
+func observeSinceLastSale(ctx context.Context, observer metric.Observer) error {
+	latestProductStockMutex.RLock()
+	defer latestProductStockMutex.RUnlock()
+
+	gauge, ok := gauges[SinceLastSaleMetric]
+	if !ok {
+		slog.ErrorContext(ctx, "Failed to find gauge instrument in callback", slog.String("metric", SinceLastSaleMetric))
+		return nil
+	}
+
+	now := time.Now()
+	for productNameKey, detail := range latestProductStock {
+		since := processStart
+		if !detail.LastSale.IsZero() {
+			since = detail.LastSale
+		}
+		attrs := attribute.NewSet(
+			attribute.String(AttrProductName, productNameKey),
+			attribute.String(AttrProductCategory, detail.ProductCategory),
+			attribute.String(AttrCustomMetric, "true"),
+		)
+		observer.ObserveInt64(gauge, int64(now.Sub(since).Seconds()), metric.WithAttributeSet(attrs))
+	}
+	return nil
+}
+
+// observeProductsPerCategory is the callback function for the products-per-category gauge.
+// It counts distinct products per category from the latest stock snapshot.
+func observeProductsPerCategory(ctx context.Context, observer metric.Observer) error {
+	latestProductStockMutex.RLock()
+	defer latestProductStockMutex.RUnlock()
+
+	gauge, ok := gauges[ProductsPerCategoryMetric]
+	if !ok {
+		slog.ErrorContext(ctx, "Failed to find gauge instrument in callback", slog.String("metric", ProductsPerCategoryMetric))
+		return nil
+	}
+
+	counts := make(map[string]int64)
+	for _, detail := range latestProductStock {
+		counts[detail.ProductCategory]++
+	}
+
+	for category, count := range counts {
+		attrs := attribute.NewSet(
+			attribute.String(AttrProductCategory, category),
+			attribute.String(AttrCustomMetric, "true"),
+		)
+		observer.ObserveInt64(gauge, count, metric.WithAttributeSet(attrs))
+	}
+	return nil
+}
+
+// observeDBFileAge is the callback function for the data file age gauge.
+// It reports the time since the configured data file was last modified.
+func observeDBFileAge(ctx context.Context, observer metric.Observer) error {
+	dbFilePathMutex.RLock()
+	path := dbFilePath
+	dbFilePathMutex.RUnlock()
+	if path == "" {
+		return nil
+	}
+
+	gauge, ok := gauges[DBFileAgeMetric]
+	if !ok {
+		slog.ErrorContext(ctx, "Failed to find gauge instrument in callback", slog.String("metric", DBFileAgeMetric))
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to stat data file for age gauge", slog.String("file_path", path), slog.Any("error", err))
+		return nil
+	}
+
+	attrs := attribute.NewSet(
+		attribute.String(AttrDBFilePath, path),
+		attribute.String(AttrCustomMetric, "true"),
+	)
+	observer.ObserveInt64(gauge, int64(time.Since(info.ModTime()).Seconds()), metric.WithAttributeSet(attrs))
+	return nil
+}
+
+// SetDBFilePath sets the data file whose age is reported by the file age gauge.
+func SetDBFilePath(path string) {
+	dbFilePathMutex.Lock()
+	defer dbFilePathMutex.Unlock()
+	dbFilePath = path
+}
+
+// observeCatalogSize is the callback function for the catalog size gauge.
+func observeCatalogSize(ctx context.Context, observer metric.Observer) error {
+	if !catalogSizeKnown.Load() {
+		return nil
+	}
+
+	gauge, ok := gauges[CatalogSizeMetric]
+	if !ok {
+		slog.ErrorContext(ctx, "Failed to find gauge instrument in callback", slog.String("metric", CatalogSizeMetric))
+		return nil
+	}
+
+	attrs := attribute.NewSet(attribute.String(AttrCustomMetric, "true"))
+	observer.ObserveInt64(gauge, catalogSize.Load(), metric.WithAttributeSet(attrs))
+	return nil
+}
+
+// observeProcessUptime is the callback function for the process uptime gauge.
+func observeProcessUptime(ctx context.Context, observer metric.Observer) error {
+	gauge, ok := gauges[ProcessUptimeMetric]
+	if !ok {
+		slog.ErrorContext(ctx, "Failed to find gauge instrument in callback", slog.String("metric", ProcessUptimeMetric))
+		return nil
+	}
+
+	attrs := attribute.NewSet(attribute.String(AttrCustomMetric, "true"))
+	observer.ObserveInt64(gauge, int64(Uptime().Seconds()), metric.WithAttributeSet(attrs))
+	return nil
+}
+
+// Uptime returns the time since the process started.
+func Uptime() time.Duration {
+	return time.Since(processStart)
+}
+
+// SetCatalogSize reconciles the catalog size counter with a full catalog read and
+// returns how far the incrementally maintained count had drifted from it.
+func SetCatalogSize(size int64) (drift int64) {
+	previous := catalogSize.Swap(size)
+	if catalogSizeKnown.Swap(true) {
+		drift = previous - size
+	}
+	return drift
+}
+
+// AddCatalogSize adjusts the catalog size counter when a product is created or
+// deleted, so the gauge stays current between full reads. It is a no-op until the
+// first full read has established a baseline.
+func AddCatalogSize(delta int64) {
+	if catalogSizeKnown.Load() {
+		catalogSize.Add(delta)
+	}
+}
+
 // UpdateProductStockLevels updates the in-memory store of product stock levels.
 // This function is called when new stock data is available.
 // productName is the map key and also stored in the detail struct.
@@ -157,10 +396,37 @@ func UpdateProductStockLevels(ctx context.Context, productName, productCategory
 		StockLevel:      stockLevel,
 		ProductName:     productName,
 		ProductCategory: productCategory,
+		LastSale:        latestProductStock[productName].LastSale,
+	}
+}
+
+// RecordProductSale marks productName as sold now, resetting its time-since-last-sale
+// gauge. Products not yet in the stock snapshot are skipped rather than added with
+// an unknown stock level; they appear with the next catalog read.
+func RecordProductSale(productName string) {
+	latestProductStockMutex.Lock()
+	defer latestProductStockMutex.Unlock()
+	detail, ok := latestProductStock[productName]
+	if !ok {
+		return
 	}
+	detail.LastSale = time.Now()
+	latestProductStock[productName] = detail
+}
+
+// RemoveProductStockLevel stops reporting stock for a product, e.g. once it is deleted.
+func RemoveProductStockLevel(productName string) {
+	latestProductStockMutex.Lock()
+	defer latestProductStockMutex.Unlock()
+	delete(latestProductStock, productName)
 }
 
-func IncrementRevenueTotal(ctx context.Context, revenue float64, productName, productCategory string) {
+// IncrementRevenueTotal records a sale's revenue given in cents. The running total is
+// kept in integer cents; the OTel counter receives the same amount converted for display.
+func IncrementRevenueTotal(ctx context.Context, revenueCents int64, productName, productCategory string) {
+	addRevenueCents(ctx, revenueCents)
+
+	revenue := float64(revenueCents) / 100
 	counter, ok := float64Counters[AppRevenueTotalMetric]
 	if !ok {
 		slog.WarnContext(ctx, "Failed to find counter", slog.String("metric", AppRevenueTotalMetric))
@@ -168,7 +434,7 @@ func IncrementRevenueTotal(ctx context.Context, revenue float64, productName, pr
 	}
 	attrs := attribute.NewSet(
 		attribute.String(AttrRevenue, strconv.FormatFloat(revenue, 'f', -1, 64)),
-		attribute.String(AttrProductName, productName),
+		attribute.String(AttrProductName, guardCardinality(AttrProductName, productName)),
 		attribute.String(AttrProductCategory, productCategory),
 		attribute.String(AttrCustomMetric, "true"),
 	)
@@ -182,7 +448,7 @@ func IncrementItemsSoldCount(ctx context.Context, quantity int64, productName, p
 		return
 	}
 	attrs := attribute.NewSet(
-		attribute.String(AttrProductName, productName),
+		attribute.String(AttrProductName, guardCardinality(AttrProductName, productName)),
 		attribute.String(AttrProductCategory, productCategory),
 		attribute.String(AttrQuantity, strconv.FormatInt(quantity, 10)),
 		attribute.String(AttrCustomMetric, "true"),
@@ -205,3 +471,150 @@ func IncrementErrorCount(ctx context.Context, errorType, operation, component st
 	)
 	counter.Add(ctx, 1, metric.WithAttributeSet(attrs))
 }
+
+// RecordOperationDuration records the duration of an operation in milliseconds.
+func RecordOperationDuration(ctx context.Context, durationMs float64, operation, component string) {
+	histogram, ok := histograms[AppOperationDurationMetric]
+	if !ok {
+		slog.WarnContext(ctx, "Failed to find histogram", slog.String("metric", AppOperationDurationMetric))
+		return
+	}
+	attrs := attribute.NewSet(
+		attribute.String(AttrOperation, operation),
+		attribute.String(AttrComponent, component),
+		attribute.String(AttrCustomMetric, "true"),
+	)
+	histogram.Record(ctx, durationMs, metric.WithAttributeSet(attrs))
+}
+
+// IncrementErrorsByClass tracks errors returned to clients by triage class.
+func IncrementErrorsByClass(ctx context.Context, errorClass, errorCode string) {
+	counter, ok := counters[AppErrorsTotalMetric]
+	if !ok {
+		slog.WarnContext(ctx, "Failed to find counter", slog.String("metric", AppErrorsTotalMetric))
+		return
+	}
+	attrs := attribute.NewSet(
+		attribute.String(AttrErrorClass, errorClass),
+		attribute.String(AttrErrorCode, errorCode),
+		attribute.String(AttrCustomMetric, "true"),
+	)
+	counter.Add(ctx, 1, metric.WithAttributeSet(attrs))
+}
+
+// IncrementHTTPRequestCount tracks requests by route template. route must be the
+// template (e.g. "/products/:name"), never the concrete path, to bound cardinality.
+func IncrementHTTPRequestCount(ctx context.Context, route, method string, statusCode int) {
+	counter, ok := counters[AppHTTPRequestCountMetric]
+	if !ok {
+		slog.WarnContext(ctx, "Failed to find counter", slog.String("metric", AppHTTPRequestCountMetric))
+		return
+	}
+	attrs := attribute.NewSet(
+		attribute.String(AttrHTTPRoute, route),
+		attribute.String(AttrHTTPMethod, method),
+		attribute.Int(AttrHTTPStatusCode, statusCode),
+		attribute.String(AttrCustomMetric, "true"),
+	)
+	counter.Add(ctx, 1, metric.WithAttributeSet(attrs))
+}
+
+// IncrementMaintenanceBlocked counts a write request rejected by maintenance mode.
+func IncrementMaintenanceBlocked(ctx context.Context, route, method string) {
+	counter, ok := counters[MaintenanceBlockedMetric]
+	if !ok {
+		slog.WarnContext(ctx, "Failed to find counter", slog.String("metric", MaintenanceBlockedMetric))
+		return
+	}
+	attrs := attribute.NewSet(
+		attribute.String(AttrHTTPRoute, route),
+		attribute.String(AttrHTTPMethod, method),
+		attribute.String(AttrCustomMetric, "true"),
+	)
+	counter.Add(ctx, 1, metric.WithAttributeSet(attrs))
+}
+
+// IncrementPanicRecovered counts a panic recovered while serving a request.
+func IncrementPanicRecovered(ctx context.Context, route, method string) {
+	counter, ok := counters[PanicRecoveredMetric]
+	if !ok {
+		slog.WarnContext(ctx, "Failed to find counter", slog.String("metric", PanicRecoveredMetric))
+		return
+	}
+	attrs := attribute.NewSet(
+		attribute.String(AttrHTTPRoute, route),
+		attribute.String(AttrHTTPMethod, method),
+		attribute.String(AttrCustomMetric, "true"),
+	)
+	counter.Add(ctx, 1, metric.WithAttributeSet(attrs))
+}
+
+// IncrementValidationFailures tracks requests rejected for invalid input, per route.
+func IncrementValidationFailures(ctx context.Context, route, method string) {
+	counter, ok := counters[ValidationFailuresMetric]
+	if !ok {
+		slog.WarnContext(ctx, "Failed to find counter", slog.String("metric", ValidationFailuresMetric))
+		return
+	}
+	attrs := attribute.NewSet(
+		attribute.String(AttrHTTPRoute, route),
+		attribute.String(AttrHTTPMethod, method),
+		attribute.String(AttrCustomMetric, "true"),
+	)
+	counter.Add(ctx, 1, metric.WithAttributeSet(attrs))
+}
+
+// IncrementBuyFunnel counts a purchase reaching a funnel stage, one of BuyRequestsMetric,
+// BuyValidatedMetric, BuyStockAvailableMetric or BuyCompletedMetric. Drop-off between
+// consecutive stages shows where purchases fail.
+func IncrementBuyFunnel(ctx context.Context, stage string) {
+	counter, ok := counters[stage]
+	if !ok {
+		slog.WarnContext(ctx, "Failed to find counter", slog.String("metric", stage))
+		return
+	}
+	attrs := attribute.NewSet(attribute.String(AttrCustomMetric, "true"))
+	counter.Add(ctx, 1, metric.WithAttributeSet(attrs))
+}
+
+// IncrementExportChunked counts an export batch that had to be split for the given signal.
+func IncrementExportChunked(ctx context.Context, signal string) {
+	counter, ok := counters[ExportChunkedMetric]
+	if !ok {
+		slog.WarnContext(ctx, "Failed to find counter", slog.String("metric", ExportChunkedMetric))
+		return
+	}
+	attrs := attribute.NewSet(
+		attribute.String(AttrSignal, signal),
+		attribute.String(AttrCustomMetric, "true"),
+	)
+	counter.Add(ctx, 1, metric.WithAttributeSet(attrs))
+}
+
+// addRevenueCents adds to the in-process revenue total, saturating at math.MaxInt64
+// instead of wrapping around.
+func addRevenueCents(ctx context.Context, cents int64) {
+	for {
+		current := revenueTotalCents.Load()
+		next := current + cents
+		if cents > 0 && next < current {
+			slog.WarnContext(ctx, "Revenue total saturated; further revenue is not aggregated",
+				slog.Int64("revenue_total_cents", current))
+			next = math.MaxInt64
+		}
+		if revenueTotalCents.CompareAndSwap(current, next) {
+			return
+		}
+	}
+}
+
+// RevenueTotalCents returns the revenue recorded by this process, in cents.
+func RevenueTotalCents() int64 {
+	return revenueTotalCents.Load()
+}
+
+// ResetAggregates zeroes the in-process aggregates such as the revenue total. The
+// OTel instruments are cumulative by design and keep their values.
+func ResetAggregates() {
+	revenueTotalCents.Store(0)
+}
@@ -0,0 +1,270 @@
+package metric
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/narender/common/telemetry/telemetrytest"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var harness *telemetrytest.Harness
+
+func TestMain(m *testing.M) {
+	harness = telemetrytest.NewTestHarness()
+	code := m.Run()
+	harness.Teardown()
+	os.Exit(code)
+}
+
+// stockSnapshot replaces the stock snapshot with products, given as name to
+// category, for the rest of the test.
+func stockSnapshot(t *testing.T, products map[string]string) {
+	t.Helper()
+	for name, category := range products {
+		UpdateProductStockLevels(context.Background(), name, category, 10)
+	}
+	t.Cleanup(func() {
+		for name := range products {
+			RemoveProductStockLevel(name)
+		}
+	})
+}
+
+func TestProductsPerCategoryGauge(t *testing.T) {
+	stockSnapshot(t, map[string]string{
+		"Coffee Mug":     "Kitchen",
+		"Teapot":         "Kitchen",
+		"Chef Knife":     "Kitchen",
+		"Desk Lamp":      "Office",
+		"Notebook":       "Office",
+		"Running Shoes":  "Sports",
+		"Unsorted Gizmo": "",
+	})
+	// Stock updates for a known product must not count it twice
+	UpdateProductStockLevels(context.Background(), "Teapot", "Kitchen", 3)
+
+	tests := []struct {
+		category string
+		want     float64
+		found    bool
+	}{
+		{category: "Kitchen", want: 3, found: true},
+		{category: "Office", want: 2, found: true},
+		{category: "Sports", want: 1, found: true},
+		{category: "", want: 1, found: true},
+		{category: "Garden", want: 0, found: false},
+	}
+	for _, tt := range tests {
+		t.Run("category "+tt.category, func(t *testing.T) {
+			got, found := harness.MetricValueWith(ProductsPerCategoryMetric, attribute.String(AttrProductCategory, tt.category))
+			if got != tt.want || found != tt.found {
+				t.Errorf("%s{category=%q} = %v, %v, want %v, %v", ProductsPerCategoryMetric, tt.category, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestProductsPerCategoryFollowsTheSnapshot(t *testing.T) {
+	stockSnapshot(t, map[string]string{"Coffee Mug": "Kitchen", "Teapot": "Kitchen"})
+	kitchen := attribute.String(AttrProductCategory, "Kitchen")
+	dining := attribute.String(AttrProductCategory, "Dining")
+
+	UpdateProductStockLevels(context.Background(), "Teapot", "Dining", 10)
+	RemoveProductStockLevel("Coffee Mug")
+
+	if _, found := harness.MetricValueWith(ProductsPerCategoryMetric, kitchen); found {
+		t.Errorf("Kitchen still reported after its last product moved or was removed")
+	}
+	if got, _ := harness.MetricValueWith(ProductsPerCategoryMetric, dining); got != 1 {
+		t.Errorf("%s{category=Dining} = %v, want 1", ProductsPerCategoryMetric, got)
+	}
+}
+
+func TestDBFileAgeGauge(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "stale.json")
+	fresh := filepath.Join(dir, "fresh.json")
+	for _, path := range []string{stale, fresh} {
+		if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+			t.Fatalf("write data file: %v", err)
+		}
+	}
+	modified := time.Now().Add(-90 * time.Minute)
+	if err := os.Chtimes(stale, modified, modified); err != nil {
+		t.Fatalf("set modification time: %v", err)
+	}
+	t.Cleanup(func() { SetDBFilePath("") })
+
+	tests := []struct {
+		name  string
+		path  string
+		want  float64
+		found bool
+	}{
+		{name: "stale file", path: stale, want: 90 * 60, found: true},
+		{name: "just written", path: fresh, want: 0, found: true},
+		{name: "missing file", path: filepath.Join(dir, "missing.json"), found: false},
+		{name: "no file configured", path: "", found: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDBFilePath(tt.path)
+
+			got, found := harness.MetricValue(DBFileAgeMetric)
+			if found != tt.found {
+				t.Fatalf("%s reported = %v, want %v", DBFileAgeMetric, found, tt.found)
+			}
+			// Allow for the seconds elapsed while the test runs
+			if got < tt.want || got > tt.want+5 {
+				t.Errorf("%s = %v, want %v", DBFileAgeMetric, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevenueAggregatesInWholeCents(t *testing.T) {
+	ResetAggregates()
+	t.Cleanup(ResetAggregates)
+	before, _ := harness.MetricValue(AppRevenueTotalMetric)
+
+	// 0.10 has no exact float64 form, so summing it as a float drifts
+	const buyers, salesEach = 8, 12_500
+	var wg sync.WaitGroup
+	for b := 0; b < buyers; b++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < salesEach; i++ {
+				IncrementRevenueTotal(context.Background(), 10, "Sticker", "Stationery")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := RevenueTotalCents(); got != buyers*salesEach*10 {
+		t.Errorf("RevenueTotalCents() = %d, want %d", got, buyers*salesEach*10)
+	}
+	after, _ := harness.MetricValue(AppRevenueTotalMetric)
+	if got := after - before; math.Abs(got-10_000) > 1e-6 {
+		t.Errorf("%s rose by %v, want 10000 to agree with the aggregate", AppRevenueTotalMetric, got)
+	}
+}
+
+func TestRevenueAggregateSaturates(t *testing.T) {
+	ResetAggregates()
+	t.Cleanup(ResetAggregates)
+
+	addRevenueCents(context.Background(), math.MaxInt64-5)
+	addRevenueCents(context.Background(), 10)
+
+	if got := RevenueTotalCents(); got != math.MaxInt64 {
+		t.Errorf("RevenueTotalCents() = %d after overflowing, want it held at %d", got, int64(math.MaxInt64))
+	}
+}
+
+func TestCatalogSizeGauge(t *testing.T) {
+	// The gauge stays silent until a full read has reported a size
+	if _, found := harness.MetricValue(CatalogSizeMetric); found {
+		t.Fatalf("%s reported before any catalog read", CatalogSizeMetric)
+	}
+
+	for _, size := range []int64{12, 0, 3} {
+		SetCatalogSize(size)
+		if got, found := harness.MetricValue(CatalogSizeMetric); !found || got != float64(size) {
+			t.Errorf("after SetCatalogSize(%d): %s = %v (found %v), want %d", size, CatalogSizeMetric, got, found, size)
+		}
+	}
+}
+
+func TestCatalogSizeCounterTracksDeletesAndReconciles(t *testing.T) {
+	size, known := catalogSize.Load(), catalogSizeKnown.Load()
+	t.Cleanup(func() {
+		catalogSize.Store(size)
+		catalogSizeKnown.Store(known)
+	})
+	catalogSize.Store(0)
+	catalogSizeKnown.Store(false)
+
+	steps := []struct {
+		name      string
+		apply     func() int64 // returns the drift reported, if any
+		wantSize  int64
+		wantKnown bool
+		wantDrift int64
+	}{
+		{name: "delete before the first read is ignored", apply: func() int64 { AddCatalogSize(-1); return 0 }},
+		{name: "first read sets the baseline without drift", apply: func() int64 { return SetCatalogSize(5) }, wantSize: 5, wantKnown: true},
+		{name: "delete", apply: func() int64 { AddCatalogSize(-1); return 0 }, wantSize: 4, wantKnown: true},
+		{name: "create", apply: func() int64 { AddCatalogSize(2); return 0 }, wantSize: 6, wantKnown: true},
+		{name: "read agreeing with the counter", apply: func() int64 { return SetCatalogSize(6) }, wantSize: 6, wantKnown: true},
+		{name: "read reconciling a missed delete", apply: func() int64 { return SetCatalogSize(5) }, wantSize: 5, wantKnown: true, wantDrift: 1},
+		{name: "read reconciling a missed create", apply: func() int64 { return SetCatalogSize(8) }, wantSize: 8, wantKnown: true, wantDrift: -3},
+	}
+	for _, step := range steps {
+		if drift := step.apply(); drift != step.wantDrift {
+			t.Errorf("%s: drift = %d, want %d", step.name, drift, step.wantDrift)
+		}
+		if got, found := harness.MetricValue(CatalogSizeMetric); found != step.wantKnown || got != float64(step.wantSize) {
+			t.Errorf("%s: %s = %v (found %v), want %d (found %v)", step.name, CatalogSizeMetric, got, found, step.wantSize, step.wantKnown)
+		}
+	}
+}
+
+// soldAt backdates the last sale of a product in the stock snapshot.
+func soldAt(t *testing.T, name string, at time.Time) {
+	t.Helper()
+	latestProductStockMutex.Lock()
+	defer latestProductStockMutex.Unlock()
+	detail, ok := latestProductStock[name]
+	if !ok {
+		t.Fatalf("%s is not in the stock snapshot", name)
+	}
+	detail.LastSale = at
+	latestProductStock[name] = detail
+}
+
+func TestSinceLastSaleGauge(t *testing.T) {
+	prevStart := processStart
+	t.Cleanup(func() { processStart = prevStart })
+	processStart = time.Now().Add(-10 * time.Minute)
+	stockSnapshot(t, map[string]string{"Coffee Mug": "Kitchen", "Teapot": "Kitchen", "Desk Lamp": "Office"})
+
+	soldAt(t, "Teapot", time.Now().Add(-90*time.Second))
+	soldAt(t, "Desk Lamp", time.Now().Add(-2*time.Hour))
+	// Later stock updates keep the last sale
+	UpdateProductStockLevels(context.Background(), "Desk Lamp", "Office", 4)
+	// Desk Lamp is sold again, Coffee Mug ages by a minute
+	RecordProductSale("Desk Lamp")
+	processStart = processStart.Add(-time.Minute)
+	// Sales of products outside the snapshot are not reported
+	RecordProductSale("Unknown Gadget")
+
+	tests := []struct {
+		product string
+		want    float64
+		found   bool
+	}{
+		{product: "Coffee Mug", want: 11 * 60, found: true},
+		{product: "Teapot", want: 90, found: true},
+		{product: "Desk Lamp", want: 0, found: true},
+		{product: "Unknown Gadget", found: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.product, func(t *testing.T) {
+			got, found := harness.MetricValueWith(SinceLastSaleMetric, attribute.String(AttrProductName, tt.product))
+			if found != tt.found {
+				t.Fatalf("%s{product=%q} reported = %v, want %v", SinceLastSaleMetric, tt.product, found, tt.found)
+			}
+			// Allow for the seconds elapsed while the test runs
+			if got < tt.want || got > tt.want+5 {
+				t.Errorf("%s{product=%q} = %v, want %v", SinceLastSaleMetric, tt.product, got, tt.want)
+			}
+		})
+	}
+}
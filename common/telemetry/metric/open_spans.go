@@ -0,0 +1,35 @@
+package metric
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// openSpans counts spans that have started but not yet ended.
+var openSpans atomic.Int64
+
+// AddOpenSpans adjusts the open span count by delta.
+func AddOpenSpans(delta int64) {
+	openSpans.Add(delta)
+}
+
+// OpenSpans returns the number of spans started but not yet ended.
+func OpenSpans() int64 {
+	return openSpans.Load()
+}
+
+func observeOpenSpans(ctx context.Context, observer metric.Observer) error {
+	gauge, ok := gauges[OpenSpansMetric]
+	if !ok {
+		slog.ErrorContext(ctx, "Failed to find gauge instrument in callback", slog.String("metric", OpenSpansMetric))
+		return nil
+	}
+
+	attrs := attribute.NewSet(attribute.String(AttrCustomMetric, "true"))
+	observer.ObserveInt64(gauge, OpenSpans(), metric.WithAttributeSet(attrs))
+	return nil
+}
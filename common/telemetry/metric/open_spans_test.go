@@ -0,0 +1,22 @@
+package metric
+
+import "testing"
+
+func TestOpenSpansGauge(t *testing.T) {
+	base := OpenSpans()
+
+	for _, tt := range []struct {
+		delta int64
+		want  int64
+	}{
+		{delta: 2, want: 2},
+		{delta: 1, want: 3},
+		{delta: -3, want: 0},
+	} {
+		AddOpenSpans(tt.delta)
+		got, found := harness.MetricValue(OpenSpansMetric)
+		if !found || got != float64(base+tt.want) {
+			t.Errorf("after AddOpenSpans(%d): %s = %v (found %v), want %d", tt.delta, OpenSpansMetric, got, found, base+tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package metric
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// defaultPriceBandBounds yields the bands 0-10, 10-50 and 50+.
+var defaultPriceBandBounds = []float64{10, 50}
+
+var (
+	priceBandMu     sync.RWMutex
+	priceBandBounds = defaultPriceBandBounds
+	// Product counts per band label from the last full catalog read; nil until known
+	priceBandCounts map[string]int64
+)
+
+// ParsePriceBandBounds parses a comma-separated list of upper band bounds such as
+// "10,50". Bounds must be positive and strictly increasing.
+func ParsePriceBandBounds(s string) ([]float64, error) {
+	var bounds []float64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bound, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price band bound %q: %w", part, err)
+		}
+		if bound <= 0 {
+			return nil, fmt.Errorf("price band bound %q must be positive", part)
+		}
+		if len(bounds) > 0 && bound <= bounds[len(bounds)-1] {
+			return nil, fmt.Errorf("price band bounds must be strictly increasing, got %q after %v", part, bounds[len(bounds)-1])
+		}
+		bounds = append(bounds, bound)
+	}
+	if len(bounds) == 0 {
+		return nil, fmt.Errorf("no price band bounds in %q", s)
+	}
+	return bounds, nil
+}
+
+// SetPriceBandBounds replaces the band bounds. Counts from earlier catalog reads are
+// dropped because their labels no longer apply.
+func SetPriceBandBounds(bounds []float64) {
+	priceBandMu.Lock()
+	defer priceBandMu.Unlock()
+	priceBandBounds = bounds
+	priceBandCounts = nil
+}
+
+// RecordCatalogPrices buckets the prices from a full catalog read into the configured bands.
+func RecordCatalogPrices(prices []float64) {
+	priceBandMu.Lock()
+	defer priceBandMu.Unlock()
+
+	counts := make(map[string]int64, len(priceBandBounds)+1)
+	for _, label := range priceBandLabels(priceBandBounds) {
+		counts[label] = 0
+	}
+	for _, price := range prices {
+		counts[priceBandLabel(priceBandBounds, price)]++
+	}
+	priceBandCounts = counts
+}
+
+// priceBandLabels returns the labels for bounds in ascending order, e.g. "0-10", "10-50", "50+".
+func priceBandLabels(bounds []float64) []string {
+	labels := make([]string, 0, len(bounds)+1)
+	lower := 0.0
+	for _, upper := range bounds {
+		labels = append(labels, formatBound(lower)+"-"+formatBound(upper))
+		lower = upper
+	}
+	return append(labels, formatBound(lower)+"+")
+}
+
+// priceBandLabel returns the label of the band containing price. Bands include their
+// lower bound, so a price equal to a bound falls into the higher band.
+func priceBandLabel(bounds []float64, price float64) string {
+	i := sort.Search(len(bounds), func(i int) bool { return price < bounds[i] })
+	lower := 0.0
+	if i > 0 {
+		lower = bounds[i-1]
+	}
+	if i == len(bounds) {
+		return formatBound(lower) + "+"
+	}
+	return formatBound(lower) + "-" + formatBound(bounds[i])
+}
+
+func formatBound(bound float64) string {
+	return strconv.FormatFloat(bound, 'f', -1, 64)
+}
+
+// observeProductsByPriceBand is the callback function for the price band gauge.
+func observeProductsByPriceBand(ctx context.Context, observer metric.Observer) error {
+	priceBandMu.RLock()
+	defer priceBandMu.RUnlock()
+	if priceBandCounts == nil {
+		return nil
+	}
+
+	gauge, ok := gauges[ProductsByPriceBandMetric]
+	if !ok {
+		slog.ErrorContext(ctx, "Failed to find gauge instrument in callback", slog.String("metric", ProductsByPriceBandMetric))
+		return nil
+	}
+
+	for band, count := range priceBandCounts {
+		attrs := attribute.NewSet(
+			attribute.String(AttrPriceBand, band),
+			attribute.String(AttrCustomMetric, "true"),
+		)
+		observer.ObserveInt64(gauge, count, metric.WithAttributeSet(attrs))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package metric
+
+import (
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// useBounds sets the price band bounds for the rest of the test.
+func useBounds(t *testing.T, bounds []float64) {
+	t.Helper()
+	SetPriceBandBounds(bounds)
+	t.Cleanup(func() { SetPriceBandBounds(defaultPriceBandBounds) })
+}
+
+func TestProductsByPriceBandGauge(t *testing.T) {
+	prices := []float64{0.5, 4.99, 9.99, 10, 24.5, 49.99, 50, 74.99, 1200}
+
+	tests := []struct {
+		name   string
+		bounds []float64
+		want   map[string]float64
+	}{
+		{name: "default bands", bounds: defaultPriceBandBounds,
+			want: map[string]float64{"0-10": 3, "10-50": 3, "50+": 3}},
+		{name: "configured bands", bounds: []float64{5, 25, 100},
+			want: map[string]float64{"0-5": 2, "5-25": 3, "25-100": 3, "100+": 1}},
+		{name: "single bound", bounds: []float64{1000},
+			want: map[string]float64{"0-1000": 8, "1000+": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useBounds(t, tt.bounds)
+			if _, found := harness.MetricValue(ProductsByPriceBandMetric); found {
+				t.Fatalf("%s reported before any catalog read", ProductsByPriceBandMetric)
+			}
+
+			RecordCatalogPrices(prices)
+
+			for band, want := range tt.want {
+				if got, found := harness.MetricValueWith(ProductsByPriceBandMetric, attribute.String(AttrPriceBand, band)); !found || got != want {
+					t.Errorf("%s{%s} = %v (found %v), want %v", ProductsByPriceBandMetric, band, got, found, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEmptyBandsReportZero(t *testing.T) {
+	useBounds(t, defaultPriceBandBounds)
+	RecordCatalogPrices([]float64{3})
+
+	for band, want := range map[string]float64{"0-10": 1, "10-50": 0, "50+": 0} {
+		if got, found := harness.MetricValueWith(ProductsByPriceBandMetric, attribute.String(AttrPriceBand, band)); !found || got != want {
+			t.Errorf("%s{%s} = %v (found %v), want %v", ProductsByPriceBandMetric, band, got, found, want)
+		}
+	}
+}
+
+func TestParsePriceBandBounds(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []float64
+		wantErr bool
+	}{
+		{in: "10,50", want: []float64{10, 50}},
+		{in: " 2.5 , 20 ,", want: []float64{2.5, 20}},
+		{in: "", wantErr: true},
+		{in: "10,abc", wantErr: true},
+		{in: "0,10", wantErr: true},
+		{in: "50,10", wantErr: true},
+		{in: "10,10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePriceBandBounds(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePriceBandBounds(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePriceBandBounds(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
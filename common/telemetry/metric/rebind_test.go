@@ -0,0 +1,99 @@
+package metric
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// dataPoints collects reader and returns the int64 data points recorded for name.
+func dataPoints(t *testing.T, reader sdkmetric.Reader, name string) []metricdata.DataPoint[int64] {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				return data.DataPoints
+			case metricdata.Gauge[int64]:
+				return data.DataPoints
+			}
+		}
+	}
+	return nil
+}
+
+// installProvider makes a fresh SDK meter provider global, as SetupOtlpMetricExporter
+// does, and restores the previous provider and bindings when the test ends.
+func installProvider(t *testing.T) *sdkmetric.ManualReader {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(provider)
+	t.Cleanup(func() {
+		otel.SetMeterProvider(previous)
+		Rebind()
+		provider.Shutdown(context.Background())
+	})
+	return reader
+}
+
+func TestInstrumentsBoundBeforeSetupExportAfterRebind(t *testing.T) {
+	ctx := context.Background()
+	// Instruments created on a provider that never delegates, as when the package is
+	// initialised before any SDK exists, record nothing
+	bindInstruments(noop.NewMeterProvider())
+	IncrementItemsSoldCount(ctx, 5, "Rebind Mug", "Kitchen")
+
+	reader := installProvider(t)
+	if points := dataPoints(t, reader, AppItemsSoldCountMetric); len(points) != 0 {
+		t.Fatalf("%s exported %d data points recorded before setup", AppItemsSoldCountMetric, len(points))
+	}
+
+	Rebind()
+	IncrementItemsSoldCount(ctx, 2, "Rebind Mug", "Kitchen")
+
+	points := dataPoints(t, reader, AppItemsSoldCountMetric)
+	if len(points) != 1 || points[0].Value != 2 {
+		t.Errorf("%s after Rebind = %+v, want one data point of 2", AppItemsSoldCountMetric, points)
+	}
+}
+
+func TestRebindIsIdempotent(t *testing.T) {
+	reader := installProvider(t)
+	Rebind()
+	Rebind()
+	SetCatalogSize(7)
+
+	// A second set of gauge callbacks would report the catalog size twice
+	points := dataPoints(t, reader, CatalogSizeMetric)
+	if len(points) != 1 || points[0].Value != 7 {
+		t.Errorf("%s = %+v, want a single data point of 7", CatalogSizeMetric, points)
+	}
+}
+
+func TestRebindMovesGaugeCallbacksToTheNewProvider(t *testing.T) {
+	first := installProvider(t)
+	Rebind()
+	second := installProvider(t)
+	Rebind()
+	SetCatalogSize(9)
+
+	if points := dataPoints(t, first, CatalogSizeMetric); len(points) != 0 {
+		t.Errorf("replaced provider still reports %s: %+v", CatalogSizeMetric, points)
+	}
+	if points := dataPoints(t, second, CatalogSizeMetric); len(points) != 1 || points[0].Value != 9 {
+		t.Errorf("%s on the new provider = %+v, want a single data point of 9", CatalogSizeMetric, points)
+	}
+}
@@ -0,0 +1,95 @@
+package metric
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// defaultLowStockThreshold is used until SetStockThresholds is called.
+const defaultLowStockThreshold = 10
+
+var (
+	stockThresholdMu        sync.RWMutex
+	lowStockThreshold       = defaultLowStockThreshold
+	categoryStockThresholds map[string]int
+)
+
+// ParseCategoryThresholds parses "category=threshold" pairs such as
+// "Perishables=20,Electronics=5". Category names are matched case-insensitively.
+func ParseCategoryThresholds(s string) (map[string]int, error) {
+	thresholds := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		category, value, ok := strings.Cut(pair, "=")
+		category = strings.TrimSpace(category)
+		if !ok || category == "" {
+			return nil, fmt.Errorf("invalid category threshold %q, want category=threshold", pair)
+		}
+		threshold, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("invalid threshold %q for category %q", value, category)
+		}
+		thresholds[strings.ToLower(category)] = threshold
+	}
+	return thresholds, nil
+}
+
+// SetStockThresholds sets the global low-stock threshold and the per-category
+// overrides of it.
+func SetStockThresholds(defaultThreshold int, byCategory map[string]int) {
+	stockThresholdMu.Lock()
+	defer stockThresholdMu.Unlock()
+	lowStockThreshold = defaultThreshold
+	categoryStockThresholds = byCategory
+}
+
+// StockThresholdFor returns the low-stock threshold for category: its override
+// if one is configured, otherwise the global threshold.
+func StockThresholdFor(category string) int {
+	stockThresholdMu.RLock()
+	defer stockThresholdMu.RUnlock()
+	if threshold, ok := categoryStockThresholds[strings.ToLower(strings.TrimSpace(category))]; ok {
+		return threshold
+	}
+	return lowStockThreshold
+}
+
+// observeLowStockProducts counts, per category, the products whose latest
+// stock is below their category's threshold.
+func observeLowStockProducts(ctx context.Context, observer metric.Observer) error {
+	gauge, ok := gauges[LowStockProductsMetric]
+	if !ok {
+		slog.ErrorContext(ctx, "Failed to find gauge instrument in callback", slog.String("metric", LowStockProductsMetric))
+		return nil
+	}
+
+	latestProductStockMutex.RLock()
+	counts := make(map[string]int64)
+	for _, detail := range latestProductStock {
+		if _, seen := counts[detail.ProductCategory]; !seen {
+			counts[detail.ProductCategory] = 0
+		}
+		if detail.StockLevel < int64(StockThresholdFor(detail.ProductCategory)) {
+			counts[detail.ProductCategory]++
+		}
+	}
+	latestProductStockMutex.RUnlock()
+
+	for category, count := range counts {
+		attrs := attribute.NewSet(
+			attribute.String(AttrProductCategory, category),
+			attribute.String(AttrCustomMetric, "true"),
+		)
+		observer.ObserveInt64(gauge, count, metric.WithAttributeSet(attrs))
+	}
+	return nil
+}
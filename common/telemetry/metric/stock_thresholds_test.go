@@ -0,0 +1,95 @@
+package metric
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// useStockThresholds sets the low-stock thresholds for the rest of the test.
+func useStockThresholds(t *testing.T, defaultThreshold int, byCategory map[string]int) {
+	t.Helper()
+	SetStockThresholds(defaultThreshold, byCategory)
+	t.Cleanup(func() { SetStockThresholds(defaultLowStockThreshold, nil) })
+}
+
+func TestStockThresholdFor(t *testing.T) {
+	useStockThresholds(t, 10, map[string]int{"perishables": 20, "electronics": 5})
+
+	tests := []struct {
+		category string
+		want     int
+	}{
+		{category: "Perishables", want: 20},
+		{category: "ELECTRONICS", want: 5},
+		{category: " electronics ", want: 5},
+		{category: "Furniture", want: 10},
+		{category: "", want: 10},
+	}
+	for _, tt := range tests {
+		if got := StockThresholdFor(tt.category); got != tt.want {
+			t.Errorf("StockThresholdFor(%q) = %d, want %d", tt.category, got, tt.want)
+		}
+	}
+}
+
+func TestLowStockProductsGauge(t *testing.T) {
+	useStockThresholds(t, 10, map[string]int{"threshold test perishables": 20, "threshold test electronics": 5})
+
+	stock := []struct {
+		name     string
+		category string
+		level    int64
+	}{
+		// 15 is above the global threshold but below the perishables one
+		{name: "Threshold Test Milk", category: "Threshold Test Perishables", level: 15},
+		{name: "Threshold Test Bread", category: "Threshold Test Perishables", level: 25},
+		// 8 is below the global threshold but not the electronics one
+		{name: "Threshold Test Cable", category: "Threshold Test Electronics", level: 8},
+		{name: "Threshold Test Charger", category: "Threshold Test Electronics", level: 2},
+		{name: "Threshold Test Chair", category: "Threshold Test Furniture", level: 9},
+		{name: "Threshold Test Desk", category: "Threshold Test Furniture", level: 10},
+	}
+	for _, s := range stock {
+		UpdateProductStockLevels(context.Background(), s.name, s.category, s.level)
+		t.Cleanup(func() { RemoveProductStockLevel(s.name) })
+	}
+
+	for category, want := range map[string]float64{
+		"Threshold Test Perishables": 1,
+		"Threshold Test Electronics": 1,
+		"Threshold Test Furniture":   1,
+	} {
+		if got, found := harness.MetricValueWith(LowStockProductsMetric, attribute.String(AttrProductCategory, category)); !found || got != want {
+			t.Errorf("%s{%s} = %v (found %v), want %v", LowStockProductsMetric, category, got, found, want)
+		}
+	}
+}
+
+func TestParseCategoryThresholds(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    map[string]int
+		wantErr bool
+	}{
+		{spec: "", want: map[string]int{}},
+		{spec: "Perishables=20,Electronics=5", want: map[string]int{"perishables": 20, "electronics": 5}},
+		{spec: " Perishables = 20 , ,Toys=0", want: map[string]int{"perishables": 20, "toys": 0}},
+		{spec: "Perishables", wantErr: true},
+		{spec: "=20", wantErr: true},
+		{spec: "Perishables=many", wantErr: true},
+		{spec: "Perishables=-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCategoryThresholds(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCategoryThresholds(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseCategoryThresholds(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+
+	"github.com/narender/common/telemetry/metric"
+)
+
+type operationIDKey struct{}
+
+// WithOperationID generates an operation ID for a logical operation and stores it
+// on ctx and in its log fields. Unlike the trace ID it exists whether or not the
+// request is sampled, so logs can always be grouped by it. It must never be used
+// as a metric attribute; the meter provider drops it if it is.
+func WithOperationID(ctx context.Context) (context.Context, string) {
+	id := newOperationID()
+	ctx = context.WithValue(ctx, operationIDKey{}, id)
+	return WithLogFields(ctx, slog.String(metric.AttrOperationID, id)), id
+}
+
+// OperationID returns the operation ID stored on ctx, or "" if there is none.
+func OperationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(operationIDKey{}).(string)
+	return id
+}
+
+func newOperationID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,194 @@
+// Package pipeline tracks how many telemetry items enter and leave the export
+// pipeline so operators can spot queues backing up before data is dropped.
+package pipeline
+
+import (
+	"context"
+	"sync/atomic"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SignalStats counts items accepted into and exported out of one signal's pipeline,
+// and items discarded before they reached it.
+type SignalStats struct {
+	accepted  atomic.Int64
+	exported  atomic.Int64
+	failed    atomic.Int64
+	discarded atomic.Int64
+}
+
+// SignalSnapshot is a point-in-time view of a SignalStats.
+// QueueDepth is approximate: items accepted but not yet exported or failed.
+// Discarded items (e.g. unsampled spans) never entered the queue and do not count
+// towards it.
+type SignalSnapshot struct {
+	Accepted   int64 `json:"accepted"`
+	Exported   int64 `json:"exported"`
+	Dropped    int64 `json:"dropped"`
+	Discarded  int64 `json:"discarded"`
+	QueueDepth int64 `json:"queueDepth"`
+}
+
+// Snapshot returns the current counts.
+func (s *SignalStats) Snapshot() SignalSnapshot {
+	accepted, exported, failed := s.accepted.Load(), s.exported.Load(), s.failed.Load()
+	return SignalSnapshot{
+		Accepted:   accepted,
+		Exported:   exported,
+		Dropped:    failed,
+		Discarded:  s.discarded.Load(),
+		QueueDepth: max(0, accepted-exported-failed),
+	}
+}
+
+// Record adds accepted, exported and failed item counts. It is exported so
+// callers outside the SDK wrappers (and simulations) can feed the stats.
+func (s *SignalStats) Record(accepted, exported, failed int64) {
+	s.accepted.Add(accepted)
+	s.exported.Add(exported)
+	s.failed.Add(failed)
+}
+
+// RecordDiscarded adds items that were filtered out before entering the pipeline.
+func (s *SignalStats) RecordDiscarded(n int64) {
+	s.discarded.Add(n)
+}
+
+// Reset zeroes the counts.
+func (s *SignalStats) Reset() {
+	s.accepted.Store(0)
+	s.exported.Store(0)
+	s.failed.Store(0)
+	s.discarded.Store(0)
+}
+
+var (
+	Traces  = &SignalStats{}
+	Metrics = &SignalStats{}
+	Logs    = &SignalStats{}
+)
+
+// Health is the combined pipeline view for all signals.
+type Health struct {
+	Traces  SignalSnapshot `json:"traces"`
+	Metrics SignalSnapshot `json:"metrics"`
+	Logs    SignalSnapshot `json:"logs"`
+}
+
+// ResetAll zeroes the counts of every signal's pipeline. Items still queued at the
+// time of the reset can briefly push a queue depth below its true value.
+func ResetAll() {
+	Traces.Reset()
+	Metrics.Reset()
+	Logs.Reset()
+}
+
+// CurrentHealth returns a snapshot of every signal's pipeline.
+func CurrentHealth() Health {
+	return Health{
+		Traces:  Traces.Snapshot(),
+		Metrics: Metrics.Snapshot(),
+		Logs:    Logs.Snapshot(),
+	}
+}
+
+// --- Traces ---
+
+type spanProcessor struct {
+	sdktrace.SpanProcessor
+}
+
+// WrapSpanProcessor counts the spans handed to next, which should be the batch
+// processor, as accepted. The batch processor ignores unsampled spans, so those
+// are counted as discarded instead and never add to the queue depth.
+func WrapSpanProcessor(next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+	return spanProcessor{next}
+}
+
+func (p spanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		Traces.RecordDiscarded(1)
+		return
+	}
+	Traces.Record(1, 0, 0)
+	p.SpanProcessor.OnEnd(s)
+}
+
+type spanExporter struct {
+	sdktrace.SpanExporter
+}
+
+// WrapSpanExporter counts exported and failed spans.
+func WrapSpanExporter(next sdktrace.SpanExporter) sdktrace.SpanExporter {
+	return spanExporter{next}
+}
+
+func (e spanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if err != nil {
+		Traces.Record(0, 0, int64(len(spans)))
+	} else {
+		Traces.Record(0, int64(len(spans)), 0)
+	}
+	return err
+}
+
+// --- Metrics ---
+
+type metricExporter struct {
+	sdkmetric.Exporter
+}
+
+// WrapMetricExporter counts metric export cycles; each collection is accepted
+// and then either exported or dropped, so the metric queue depth stays near zero.
+func WrapMetricExporter(next sdkmetric.Exporter) sdkmetric.Exporter {
+	return metricExporter{next}
+}
+
+func (e metricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	Metrics.Record(1, 0, 0)
+	err := e.Exporter.Export(ctx, rm)
+	if err != nil {
+		Metrics.Record(0, 0, 1)
+	} else {
+		Metrics.Record(0, 1, 0)
+	}
+	return err
+}
+
+// --- Logs ---
+
+// LogCounter is a log processor that counts emitted records as accepted.
+// Register it alongside the batch processor.
+type LogCounter struct{}
+
+func (LogCounter) OnEmit(context.Context, *sdklog.Record) error {
+	Logs.Record(1, 0, 0)
+	return nil
+}
+
+func (LogCounter) Shutdown(context.Context) error   { return nil }
+func (LogCounter) ForceFlush(context.Context) error { return nil }
+
+type logExporter struct {
+	sdklog.Exporter
+}
+
+// WrapLogExporter counts exported and failed log records.
+func WrapLogExporter(next sdklog.Exporter) sdklog.Exporter {
+	return logExporter{next}
+}
+
+func (e logExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	if err != nil {
+		Logs.Record(0, 0, int64(len(records)))
+	} else {
+		Logs.Record(0, int64(len(records)), 0)
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// decisionSampler makes the same sampling decision for every span.
+type decisionSampler struct {
+	decision sdktrace.SamplingDecision
+}
+
+func (s decisionSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.SamplingResult{Decision: s.decision}
+}
+
+func (s decisionSampler) Description() string {
+	return "decisionSampler"
+}
+
+// failingExporter rejects every export.
+type failingExporter struct{}
+
+func (failingExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	return errors.New("collector unavailable")
+}
+
+func (failingExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestWrapSpanProcessorCountsOnlySpansEnteringThePipeline(t *testing.T) {
+	tests := []struct {
+		name     string
+		decision sdktrace.SamplingDecision
+		exporter sdktrace.SpanExporter
+		want     SignalSnapshot
+	}{
+		{
+			name:     "sampled spans exported",
+			decision: sdktrace.RecordAndSample,
+			exporter: tracetest.NewInMemoryExporter(),
+			want:     SignalSnapshot{Accepted: 5, Exported: 5},
+		},
+		{
+			name:     "recorded but unsampled spans discarded",
+			decision: sdktrace.RecordOnly,
+			exporter: tracetest.NewInMemoryExporter(),
+			want:     SignalSnapshot{Discarded: 5},
+		},
+		{
+			name:     "dropped spans not recorded at all",
+			decision: sdktrace.Drop,
+			exporter: tracetest.NewInMemoryExporter(),
+			want:     SignalSnapshot{},
+		},
+		{
+			name:     "failed exports dropped",
+			decision: sdktrace.RecordAndSample,
+			exporter: failingExporter{},
+			want:     SignalSnapshot{Accepted: 5, Dropped: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ResetAll()
+			t.Cleanup(ResetAll)
+
+			tp := sdktrace.NewTracerProvider(
+				sdktrace.WithSampler(decisionSampler{tt.decision}),
+				sdktrace.WithSpanProcessor(WrapSpanProcessor(sdktrace.NewSimpleSpanProcessor(WrapSpanExporter(tt.exporter)))),
+			)
+			tracer := tp.Tracer("pipeline_test")
+			for i := 0; i < 5; i++ {
+				_, span := tracer.Start(context.Background(), "work")
+				span.End()
+			}
+			if err := tp.Shutdown(context.Background()); err != nil {
+				t.Fatalf("shutdown tracer provider: %v", err)
+			}
+
+			if got := Traces.Snapshot(); got != tt.want {
+				t.Errorf("snapshot = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignalSnapshotQueueDepth(t *testing.T) {
+	tests := []struct {
+		name                                string
+		accepted, exported, failed, discard int64
+		wantDepth                           int64
+	}{
+		{name: "empty", wantDepth: 0},
+		{name: "items waiting", accepted: 10, exported: 4, failed: 1, wantDepth: 5},
+		{name: "discarded items do not queue", accepted: 3, exported: 3, discard: 20, wantDepth: 0},
+		{name: "never negative after reset race", exported: 2, wantDepth: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stats SignalStats
+			stats.Record(tt.accepted, tt.exported, tt.failed)
+			stats.RecordDiscarded(tt.discard)
+
+			got := stats.Snapshot()
+			if got.QueueDepth != tt.wantDepth {
+				t.Errorf("QueueDepth = %d, want %d", got.QueueDepth, tt.wantDepth)
+			}
+			if got.Discarded != tt.discard {
+				t.Errorf("Discarded = %d, want %d", got.Discarded, tt.discard)
+			}
+		})
+	}
+}
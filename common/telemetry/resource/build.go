@@ -0,0 +1,32 @@
+package resource
+
+import (
+	"runtime"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Build information, set at link time, e.g.
+//
+//	go build -ldflags "-X github.com/narender/common/telemetry/resource.BuildCommit=$(git rev-parse HEAD)"
+var (
+	BuildCommit = "unknown"
+	BuildDate   = "unknown"
+)
+
+// Build attribute keys recorded on the resource of every signal.
+const (
+	AttrBuildCommit    = attribute.Key("service.build.commit")
+	AttrBuildDate      = attribute.Key("service.build.date")
+	AttrBuildGoVersion = attribute.Key("service.build.go_version")
+)
+
+// BuildAttributes returns the build commit and date from the link-time variables
+// and the Go version the binary was built with.
+func BuildAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		AttrBuildCommit.String(BuildCommit),
+		AttrBuildDate.String(BuildDate),
+		AttrBuildGoVersion.String(runtime.Version()),
+	}
+}
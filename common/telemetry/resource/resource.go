@@ -10,8 +10,9 @@ import (
 
 // NewResource creates a new OpenTelemetry resource with standard attributes.
 // These attributes describe the entity producing telemetry (e.g., process, SDK).
-// It now accepts serviceName and serviceVersion.
-func NewResource(ctx context.Context, serviceName string, serviceVersion string) (*resource.Resource, error) {
+// It now accepts serviceName, serviceVersion and deploymentEnv, and adds the build
+// commit, date and Go version from BuildAttributes.
+func NewResource(ctx context.Context, serviceName string, serviceVersion string, deploymentEnv string) (*resource.Resource, error) {
 
 	res, err := resource.New(ctx,
 		resource.WithProcess(),
@@ -19,7 +20,9 @@ func NewResource(ctx context.Context, serviceName string, serviceVersion string)
 		resource.WithAttributes(
 			semconv.ServiceNameKey.String(serviceName),
 			semconv.ServiceVersionKey.String(serviceVersion),
+			semconv.DeploymentEnvironmentKey.String(deploymentEnv),
 		),
+		resource.WithAttributes(BuildAttributes()...),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OTel resource: %w", err)
@@ -0,0 +1,87 @@
+package resource
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/caarlos0/env/v10"
+	"github.com/narender/common/config"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+func TestNewResourceCarriesDeploymentEnvironmentFromConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{name: "default", want: "development"},
+		{name: "staging", env: "staging", want: "staging"},
+		{name: "production", env: "production", want: "production"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("DEPLOYMENT_ENV", tt.env)
+			}
+			cfg := &config.Config{}
+			if err := env.Parse(cfg); err != nil {
+				t.Fatalf("parse configuration: %v", err)
+			}
+
+			res, err := NewResource(context.Background(), "product-service", "v1.0.0", cfg.DEPLOYMENT_ENV)
+			if err != nil {
+				t.Fatalf("NewResource: %v", err)
+			}
+			got, ok := res.Set().Value(semconv.DeploymentEnvironmentKey)
+			if !ok {
+				t.Fatalf("resource has no %s attribute", semconv.DeploymentEnvironmentKey)
+			}
+			if got.AsString() != tt.want {
+				t.Errorf("%s = %q, want %q", semconv.DeploymentEnvironmentKey, got.AsString(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResourceCarriesBuildAttributes(t *testing.T) {
+	tests := []struct {
+		name                 string
+		commit, date         string // ldflags values; empty leaves the defaults
+		wantCommit, wantDate string
+	}{
+		{name: "set by ldflags", commit: "4f2c9e1", date: "2026-10-16T08:00:00Z", wantCommit: "4f2c9e1", wantDate: "2026-10-16T08:00:00Z"},
+		{name: "not set", wantCommit: "unknown", wantDate: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prevCommit, prevDate := BuildCommit, BuildDate
+			t.Cleanup(func() { BuildCommit, BuildDate = prevCommit, prevDate })
+			if tt.commit != "" {
+				BuildCommit, BuildDate = tt.commit, tt.date
+			}
+
+			res, err := NewResource(context.Background(), "product-service", "v1.0.0", "test")
+			if err != nil {
+				t.Fatalf("NewResource: %v", err)
+			}
+
+			want := map[attribute.Key]string{
+				AttrBuildCommit:        tt.wantCommit,
+				AttrBuildDate:          tt.wantDate,
+				AttrBuildGoVersion:     runtime.Version(),
+				semconv.ServiceNameKey: "product-service",
+			}
+			for key, value := range want {
+				got, ok := res.Set().Value(key)
+				if !ok || got.AsString() != value {
+					t.Errorf("%s = %q (present %v), want %q", key, got.AsString(), ok, value)
+				}
+			}
+		})
+	}
+}
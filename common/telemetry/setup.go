@@ -2,6 +2,7 @@ package telemetry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
@@ -11,49 +12,135 @@ import (
 	otelemetryResource "github.com/narender/common/telemetry/resource"
 	traceExporter "github.com/narender/common/telemetry/trace"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log/global"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// collectorDialOptions returns the options for connections to the collector. Only
+// OTEL_DIAL_TIMEOUT bounds connecting; exports have their own timeout.
+func collectorDialOptions(cfg *config.Config, creds credentials.TransportCredentials) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoff.DefaultConfig,
+			MinConnectTimeout: cfg.OTEL_DIAL_TIMEOUT,
+		}),
+	}
+}
+
 func InitTelemetry(cfg *config.Config) error {
+	// Reject a bad TLS minimum at startup even while TLS is off, so enabling it later cannot fail
+	if _, err := cfg.ExporterTLSMinVersion(); err != nil {
+		return fmt.Errorf("invalid exporter TLS configuration: %w", err)
+	}
 
-	res, err := otelemetryResource.NewResource(context.Background(), cfg.SERVICE_NAME, cfg.SERVICE_VERSION)
+	res, err := otelemetryResource.NewResource(context.Background(), cfg.SERVICE_NAME, cfg.SERVICE_VERSION, cfg.DEPLOYMENT_ENV)
 	if err != nil {
 
 		log.Printf("ERROR: Failed to create OTel resource: %v\n", err)
 		return fmt.Errorf("failed to create resource: %w", err)
 	}
-	log.Println("OTel Resource created.")
+	log.Printf("OTel Resource created (deployment.environment=%s).\n", cfg.DEPLOYMENT_ENV)
 
 	if cfg.ENVIRONMENT == "production" {
 		log.Println("Production environment detected. Initializing OTLP Trace, Metric, and Log providers.")
 
 		ctx := context.Background()
-		connOpts := []grpc.DialOption{
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		// Only header keys are logged; values may carry credentials.
+		log.Printf("OTLP exporter headers configured: %v\n", config.HeaderKeys(cfg.OtlpHeaders()))
+		transportCreds := insecure.NewCredentials()
+		if cfg.OTEL_EXPORTER_TLS_ENABLED {
+			tlsConfig, err := exporterTLSConfig(cfg)
+			if err != nil {
+				return err
+			}
+			transportCreds = credentials.NewTLS(tlsConfig)
+			log.Printf("OTLP exporters use TLS (minimum version %s).\n", cfg.OTEL_EXPORTER_TLS_MIN_VERSION)
 		}
+		otlpConns.opts = collectorDialOptions(cfg, transportCreds)
 
-		if err := traceExporter.SetupOtlpTraceExporter(ctx, cfg, connOpts, res); err != nil {
+		traceConn, err := otlpConns.get(cfg.TracesEndpoint())
+		if err != nil {
+			return err
+		}
+		if err := traceExporter.SetupOtlpTraceExporter(ctx, cfg, traceConn, res); err != nil {
 			log.Printf("ERROR: OTLP Trace exporter setup failed: %v\n", err)
 			return fmt.Errorf("trace exporter setup failed: %w", err)
 		}
 
-		if err := metricExporter.SetupOtlpMetricExporter(ctx, cfg, connOpts, res); err != nil {
+		metricConn, err := otlpConns.get(cfg.MetricsEndpoint())
+		if err != nil {
+			return err
+		}
+		if err := metricExporter.SetupOtlpMetricExporter(ctx, cfg, metricConn, res); err != nil {
 			log.Printf("ERROR: OTLP Metric exporter setup failed: %v\n", err)
 			return fmt.Errorf("metric exporter setup failed: %w", err)
 		}
 
-		if err := logExporter.SetupOtlpLogExporter(ctx, cfg, connOpts, res); err != nil {
+		logConn, err := otlpConns.get(cfg.LogsEndpoint())
+		if err != nil {
+			return err
+		}
+		if err := logExporter.SetupOtlpLogExporter(ctx, cfg, logConn, res); err != nil {
 			log.Printf("ERROR: OTLP Log exporter setup failed: %v\n", err)
 			return fmt.Errorf("log exporter setup failed: %w", err)
 		}
 
+		go watchConnection(traceConn, "traces", cfg.OTEL_CONNECTION_WARMUP)
+		go watchConnection(metricConn, "metrics", cfg.OTEL_CONNECTION_WARMUP)
+		go watchConnection(logConn, "logs", cfg.OTEL_CONNECTION_WARMUP)
+
 	} else {
 
 		log.Printf("Non-production environment (%s) detected. Skipping OTLP exporter setup. Using No-Op providers.", cfg.ENVIRONMENT)
+		// Still build the sampler so the effective sampling configuration can be inspected.
+		traceExporter.NewSampler(cfg)
 
 	}
 
+	metricExporter.SetCardinalityLimit(cfg.OTEL_METRIC_CARDINALITY_LIMIT)
+	if bounds, err := metricExporter.ParsePriceBandBounds(cfg.PRICE_BAND_BOUNDS); err != nil {
+		log.Printf("WARN: Ignoring PRICE_BAND_BOUNDS: %v\n", err)
+	} else {
+		metricExporter.SetPriceBandBounds(bounds)
+	}
+	if thresholds, err := metricExporter.ParseCategoryThresholds(cfg.CATEGORY_STOCK_THRESHOLDS); err != nil {
+		log.Printf("WARN: Ignoring CATEGORY_STOCK_THRESHOLDS: %v\n", err)
+		metricExporter.SetStockThresholds(cfg.LOW_STOCK_THRESHOLD, nil)
+	} else {
+		metricExporter.SetStockThresholds(cfg.LOW_STOCK_THRESHOLD, thresholds)
+	}
+
+	// Propagation applies in every environment so incoming trace context is honoured
+	// even when exporters are disabled.
+	otel.SetTextMapPropagator(traceExporter.NewPropagator(cfg.OTEL_PROPAGATORS))
+	log.Printf("OTel TextMapPropagator set (%s).\n", cfg.OTEL_PROPAGATORS)
+
 	log.Println("OpenTelemetry SDK initialization sequence complete.")
 	return nil
 }
+
+// Shutdown flushes and stops the global SDK providers installed by InitTelemetry.
+// It is a no-op for providers that were never replaced (non-production).
+func Shutdown(ctx context.Context) error {
+	var errs []error
+	if tp, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider); ok {
+		errs = append(errs, tp.Shutdown(ctx))
+	}
+	if mp, ok := otel.GetMeterProvider().(*sdkmetric.MeterProvider); ok {
+		errs = append(errs, mp.Shutdown(ctx))
+	}
+	if lp, ok := global.GetLoggerProvider().(*sdklog.LoggerProvider); ok {
+		errs = append(errs, lp.Shutdown(ctx))
+	}
+	// Providers flush through these connections, so close them last
+	errs = append(errs, otlpConns.closeAll())
+	return errors.Join(errs...)
+}
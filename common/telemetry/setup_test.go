@@ -0,0 +1,38 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/narender/common/config"
+	"github.com/narender/common/telemetry/telemetrytest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestCollectorDialOptionsUseTheDialTimeout(t *testing.T) {
+	// gRPC never gives up on a connection attempt before its 1s base backoff, and
+	// waits 20s by default
+	cfg := &config.Config{OTEL_DIAL_TIMEOUT: 2 * time.Second, OTEL_EXPORT_TIMEOUT: time.Minute}
+	conn, err := grpc.NewClient(telemetrytest.SilentCollector(t), collectorDialOptions(cfg, insecure.NewCredentials())...)
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	defer conn.Close()
+
+	// The handshake never completes, so only the dial timeout ends the attempt
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	start := time.Now()
+	conn.Connect()
+	for state := conn.GetState(); state != connectivity.TransientFailure; state = conn.GetState() {
+		if !conn.WaitForStateChange(ctx, state) {
+			t.Fatalf("connection still %s after %s, want it to fail after the 2s dial timeout", state, time.Since(start))
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 1900*time.Millisecond {
+		t.Errorf("connection failed after %s, before the 2s dial timeout", elapsed)
+	}
+}
@@ -0,0 +1,44 @@
+package telemetrytest
+
+import (
+	"io"
+	"net"
+	"sync"
+	"testing"
+)
+
+// SilentCollector listens on a local port and returns its address. It accepts
+// connections but never answers, so gRPC calls to it end only when a timeout
+// fires. The listener and its connections are closed when the test ends.
+func SilentCollector(tb testing.TB) string {
+	tb.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("listen: %v", err)
+	}
+
+	var mu sync.Mutex
+	var conns []net.Conn
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+			go io.Copy(io.Discard, conn)
+		}
+	}()
+
+	tb.Cleanup(func() {
+		listener.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+	})
+	return listener.Addr().String()
+}
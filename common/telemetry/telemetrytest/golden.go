@@ -0,0 +1,164 @@
+package telemetrytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// UpdateGoldenEnv set to "1" makes AssertGoldenSpans rewrite golden files instead
+// of comparing against them.
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// volatileAttributePrefixes are attributes whose values change between runs and
+// are left out of span shapes.
+var volatileAttributePrefixes = []string{
+	"latency.",
+	"code.",
+	"thread.",
+	"net.",
+	"network.",
+	"server.port",
+	"client.",
+	"user_agent.",
+	"http.request.body.size",
+	"http.response.body.size",
+	"request.content_length",
+	"request.deadline_ms",
+	"operation.id",
+	"db.file.path",
+}
+
+// SpanShape is the run-independent part of a span: its name, kind, status,
+// attributes and event names, with its children in a stable order. Timestamps and
+// trace and span IDs are left out.
+type SpanShape struct {
+	Name       string            `json:"name"`
+	Kind       string            `json:"kind"`
+	Status     string            `json:"status"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Events     []string          `json:"events,omitempty"`
+	Children   []SpanShape       `json:"children,omitempty"`
+}
+
+// SpanTree turns recorded spans into shapes, one per root span. A span whose
+// parent was not recorded is treated as a root.
+func SpanTree(spans tracetest.SpanStubs) []SpanShape {
+	byID := make(map[string]bool, len(spans))
+	for _, s := range spans {
+		byID[s.SpanContext.SpanID().String()] = true
+	}
+
+	children := make(map[string][]tracetest.SpanStub)
+	var roots []tracetest.SpanStub
+	for _, s := range spans {
+		parentID := s.Parent.SpanID().String()
+		if s.Parent.HasSpanID() && byID[parentID] {
+			children[parentID] = append(children[parentID], s)
+			continue
+		}
+		roots = append(roots, s)
+	}
+
+	var build func(stubs []tracetest.SpanStub) []SpanShape
+	build = func(stubs []tracetest.SpanStub) []SpanShape {
+		// Siblings are ordered by start time so concurrent work does not reorder the tree
+		sort.SliceStable(stubs, func(i, j int) bool { return stubs[i].StartTime.Before(stubs[j].StartTime) })
+		shapes := make([]SpanShape, 0, len(stubs))
+		for _, s := range stubs {
+			shape := shapeOf(s)
+			shape.Children = build(children[s.SpanContext.SpanID().String()])
+			shapes = append(shapes, shape)
+		}
+		return shapes
+	}
+	return build(roots)
+}
+
+func shapeOf(s tracetest.SpanStub) SpanShape {
+	shape := SpanShape{
+		Name:   s.Name,
+		Kind:   s.SpanKind.String(),
+		Status: s.Status.Code.String(),
+	}
+	for _, attr := range s.Attributes {
+		key := string(attr.Key)
+		if volatileAttribute(key) {
+			continue
+		}
+		if shape.Attributes == nil {
+			shape.Attributes = make(map[string]string)
+		}
+		shape.Attributes[key] = attr.Value.Emit()
+	}
+	for _, event := range s.Events {
+		shape.Events = append(shape.Events, event.Name)
+	}
+	return shape
+}
+
+func volatileAttribute(key string) bool {
+	for _, prefix := range volatileAttributePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// AssertGoldenSpans compares the shape of spans with the golden file at path. With
+// UPDATE_GOLDEN=1, or when the file does not exist yet, the file is written instead.
+func AssertGoldenSpans(t testing.TB, path string, spans tracetest.SpanStubs) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(SpanTree(spans), "", "  ")
+	if err != nil {
+		t.Fatalf("marshal span tree: %v", err)
+	}
+	got = append(got, '\n')
+
+	want, err := os.ReadFile(path)
+	if os.Getenv(UpdateGoldenEnv) == "1" || os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("write golden file: %v", err)
+		}
+		t.Logf("wrote golden span tree %s", path)
+		return
+	}
+	if err != nil {
+		t.Fatalf("read golden file: %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("span tree does not match %s (rerun with %s=1 to accept):\n%s", path, UpdateGoldenEnv, firstDifference(want, got))
+	}
+}
+
+// firstDifference describes the first line where want and got differ.
+func firstDifference(want, got []byte) string {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n  want: %s\n  got:  %s", i+1, w, g)
+		}
+	}
+	return "files differ"
+}
@@ -0,0 +1,151 @@
+package telemetrytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordBuyShape records a small request-shaped trace: a handler span with a
+// service child, which in turn has two repository children.
+func recordBuyShape(t *testing.T, failRepository bool) tracetest.SpanStubs {
+	t.Helper()
+	harness.Reset()
+	tracer := otel.Tracer("telemetrytest_test")
+
+	ctx, handler := tracer.Start(context.Background(), "handler")
+	handler.SetAttributes(
+		attribute.String("product.name", "Coffee Mug"),
+		attribute.Int64("latency.ms", 12),
+		attribute.String("client.address", "10.0.0.7"))
+	serviceCtx, service := tracer.Start(ctx, "service")
+	_, read := tracer.Start(serviceCtx, "repository.read")
+	read.End()
+	_, write := tracer.Start(serviceCtx, "repository.write")
+	if failRepository {
+		write.RecordError(errors.New("disk full"))
+		write.SetStatus(codes.Error, "disk full")
+	}
+	write.End()
+	service.End()
+	handler.End()
+
+	return harness.Spans()
+}
+
+func TestSpanTree(t *testing.T) {
+	tree := SpanTree(recordBuyShape(t, false))
+
+	if len(tree) != 1 || tree[0].Name != "handler" {
+		t.Fatalf("roots = %+v, want the handler span only", tree)
+	}
+	handler := tree[0]
+	if got := handler.Attributes["product.name"]; got != "Coffee Mug" {
+		t.Errorf("product.name = %q, want Coffee Mug", got)
+	}
+	for _, volatile := range []string{"latency.ms", "client.address"} {
+		if _, ok := handler.Attributes[volatile]; ok {
+			t.Errorf("volatile attribute %s kept in the shape", volatile)
+		}
+	}
+
+	if len(handler.Children) != 1 || handler.Children[0].Name != "service" {
+		t.Fatalf("handler children = %+v, want service", handler.Children)
+	}
+	var names []string
+	for _, child := range handler.Children[0].Children {
+		names = append(names, child.Name)
+	}
+	if got := strings.Join(names, ","); got != "repository.read,repository.write" {
+		t.Errorf("service children = %s, want repository.read,repository.write in start order", got)
+	}
+}
+
+func TestSpanTreeTreatsUnrecordedParentAsRoot(t *testing.T) {
+	spans := recordBuyShape(t, false)
+
+	var withoutHandler tracetest.SpanStubs
+	for _, span := range spans {
+		if span.Name != "handler" {
+			withoutHandler = append(withoutHandler, span)
+		}
+	}
+
+	tree := SpanTree(withoutHandler)
+	if len(tree) != 1 || tree[0].Name != "service" {
+		t.Fatalf("roots = %+v, want service as the root", tree)
+	}
+}
+
+// recordingTB captures failures so AssertGoldenSpans can be checked for mismatches
+// without failing the calling test.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Logf(format string, args ...any) {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertGoldenSpans(t *testing.T) {
+	t.Setenv(UpdateGoldenEnv, "")
+	path := filepath.Join(t.TempDir(), "testdata", "buy.golden.json")
+
+	// A missing golden file is written from the first run
+	AssertGoldenSpans(t, path, recordBuyShape(t, false))
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("golden file not written: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		failRepository bool
+		wantMismatch   bool
+	}{
+		{name: "same shape from a new run matches", failRepository: false, wantMismatch: false},
+		{name: "changed span status is reported", failRepository: true, wantMismatch: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &recordingTB{TB: t}
+			AssertGoldenSpans(recorder, path, recordBuyShape(t, tt.failRepository))
+			if mismatch := len(recorder.failures) > 0; mismatch != tt.wantMismatch {
+				t.Errorf("mismatch = %v, want %v (failures: %v)", mismatch, tt.wantMismatch, recorder.failures)
+			}
+		})
+	}
+}
+
+func TestAssertGoldenSpansUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "buy.golden.json")
+	t.Setenv(UpdateGoldenEnv, "")
+	AssertGoldenSpans(t, path, recordBuyShape(t, false))
+
+	t.Setenv(UpdateGoldenEnv, "1")
+	AssertGoldenSpans(t, path, recordBuyShape(t, true))
+
+	t.Setenv(UpdateGoldenEnv, "")
+	recorder := &recordingTB{TB: t}
+	AssertGoldenSpans(recorder, path, recordBuyShape(t, true))
+	if len(recorder.failures) != 0 {
+		t.Errorf("shape differs from the rewritten golden file: %v", recorder.failures)
+	}
+}
@@ -0,0 +1,210 @@
+// Package telemetrytest installs in-memory OpenTelemetry providers so code that
+// emits spans, metrics and logs can be asserted on in tests.
+package telemetrytest
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Harness holds the in-memory providers installed as the OTel globals.
+//
+// Instruments created before the first provider is installed (such as those in
+// common/telemetry/metric) are only delegated to that first provider, so create
+// a single Harness per test binary when asserting on them.
+type Harness struct {
+	spanExporter *tracetest.InMemoryExporter
+	metricReader *sdkmetric.ManualReader
+	logExporter  *memoryLogExporter
+
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+	loggerProvider *sdklog.LoggerProvider
+
+	prevTracerProvider trace.TracerProvider
+	prevMeterProvider  metric.MeterProvider
+	prevLoggerProvider otellog.LoggerProvider
+	prevPropagator     propagation.TextMapPropagator
+}
+
+// NewTestHarness installs in-memory trace, metric and log providers as the globals.
+// Call Teardown to flush them and restore the previous globals.
+func NewTestHarness() *Harness {
+	h := &Harness{
+		spanExporter:       tracetest.NewInMemoryExporter(),
+		metricReader:       sdkmetric.NewManualReader(),
+		logExporter:        &memoryLogExporter{},
+		prevTracerProvider: otel.GetTracerProvider(),
+		prevMeterProvider:  otel.GetMeterProvider(),
+		prevLoggerProvider: global.GetLoggerProvider(),
+		prevPropagator:     otel.GetTextMapPropagator(),
+	}
+
+	h.tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSyncer(h.spanExporter),
+	)
+	h.meterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(h.metricReader))
+	h.loggerProvider = sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(h.logExporter)))
+
+	otel.SetTracerProvider(h.tracerProvider)
+	otel.SetMeterProvider(h.meterProvider)
+	global.SetLoggerProvider(h.loggerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return h
+}
+
+// Spans returns all ended spans recorded so far.
+func (h *Harness) Spans() tracetest.SpanStubs {
+	return h.spanExporter.GetSpans()
+}
+
+// SpansNamed returns the ended spans with the given name.
+func (h *Harness) SpansNamed(name string) tracetest.SpanStubs {
+	var matched tracetest.SpanStubs
+	for _, span := range h.spanExporter.GetSpans() {
+		if span.Name == name {
+			matched = append(matched, span)
+		}
+	}
+	return matched
+}
+
+// Metrics collects and returns the current metric data.
+func (h *Harness) Metrics() metricdata.ResourceMetrics {
+	var rm metricdata.ResourceMetrics
+	_ = h.metricReader.Collect(context.Background(), &rm)
+	return rm
+}
+
+// MetricValue returns the value of the named metric summed across all data points:
+// the total for sums and gauges, and the sum of recorded values for histograms.
+// The second result is false if the metric has not been recorded.
+func (h *Harness) MetricValue(name string) (float64, bool) {
+	return h.MetricValueWith(name)
+}
+
+// MetricValueWith is MetricValue restricted to the data points carrying all of
+// attrs. The second result is false if no such data point has been recorded.
+func (h *Harness) MetricValueWith(name string, attrs ...attribute.KeyValue) (float64, bool) {
+	rm := h.Metrics()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			var total float64
+			found := false
+			add := func(set attribute.Set, value float64) {
+				for _, attr := range attrs {
+					if v, ok := set.Value(attr.Key); !ok || v != attr.Value {
+						return
+					}
+				}
+				total += value
+				found = true
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					add(dp.Attributes, float64(dp.Value))
+				}
+			case metricdata.Sum[float64]:
+				for _, dp := range data.DataPoints {
+					add(dp.Attributes, dp.Value)
+				}
+			case metricdata.Gauge[int64]:
+				for _, dp := range data.DataPoints {
+					add(dp.Attributes, float64(dp.Value))
+				}
+			case metricdata.Gauge[float64]:
+				for _, dp := range data.DataPoints {
+					add(dp.Attributes, dp.Value)
+				}
+			case metricdata.Histogram[int64]:
+				for _, dp := range data.DataPoints {
+					add(dp.Attributes, float64(dp.Sum))
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					add(dp.Attributes, dp.Sum)
+				}
+			}
+			return total, found
+		}
+	}
+	return 0, false
+}
+
+// LogRecords returns all log records emitted through the OTel log bridge.
+func (h *Harness) LogRecords() []sdklog.Record {
+	return h.logExporter.Records()
+}
+
+// Reset discards all recorded spans and log records.
+func (h *Harness) Reset() {
+	h.spanExporter.Reset()
+	h.logExporter.Reset()
+}
+
+// Teardown shuts down the in-memory providers and restores the previous globals.
+func (h *Harness) Teardown() {
+	ctx := context.Background()
+	_ = h.tracerProvider.Shutdown(ctx)
+	_ = h.meterProvider.Shutdown(ctx)
+	_ = h.loggerProvider.Shutdown(ctx)
+
+	otel.SetTracerProvider(h.prevTracerProvider)
+	otel.SetMeterProvider(h.prevMeterProvider)
+	global.SetLoggerProvider(h.prevLoggerProvider)
+	otel.SetTextMapPropagator(h.prevPropagator)
+}
+
+// memoryLogExporter is an sdklog.Exporter that keeps records in memory.
+type memoryLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memoryLogExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (e *memoryLogExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+func (e *memoryLogExporter) Records() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdklog.Record(nil), e.records...)
+}
+
+func (e *memoryLogExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.records = nil
+}
@@ -0,0 +1,174 @@
+package telemetrytest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// harness is shared by the tests in this package, as the harness docs recommend.
+var harness *Harness
+
+func TestMain(m *testing.M) {
+	harness = NewTestHarness()
+	code := m.Run()
+	harness.Teardown()
+	os.Exit(code)
+}
+
+func TestHarnessCapturesSpans(t *testing.T) {
+	harness.Reset()
+	tracer := otel.Tracer("telemetrytest_test")
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child")
+	child.SetAttributes(attribute.String("product.name", "Coffee Mug"))
+	child.End()
+	parent.End()
+
+	if got := len(harness.Spans()); got != 2 {
+		t.Fatalf("recorded %d spans, want 2", got)
+	}
+	children := harness.SpansNamed("child")
+	if len(children) != 1 {
+		t.Fatalf("recorded %d child spans, want 1", len(children))
+	}
+	if children[0].Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("child parent = %s, want %s", children[0].Parent.SpanID(), parent.SpanContext().SpanID())
+	}
+	if len(children[0].Attributes) != 1 || children[0].Attributes[0].Value.AsString() != "Coffee Mug" {
+		t.Errorf("child attributes = %v, want product.name=Coffee Mug", children[0].Attributes)
+	}
+	if got := harness.SpansNamed("missing"); len(got) != 0 {
+		t.Errorf("SpansNamed(missing) = %d spans, want none", len(got))
+	}
+}
+
+func TestHarnessMetricValue(t *testing.T) {
+	meter := otel.Meter("telemetrytest_test")
+	counter, err := meter.Int64Counter("test.requests")
+	if err != nil {
+		t.Fatalf("create counter: %v", err)
+	}
+	gauge, err := meter.Float64Gauge("test.temperature")
+	if err != nil {
+		t.Fatalf("create gauge: %v", err)
+	}
+	histogram, err := meter.Float64Histogram("test.latency")
+	if err != nil {
+		t.Fatalf("create histogram: %v", err)
+	}
+
+	ctx := context.Background()
+	counter.Add(ctx, 2, metric.WithAttributes(attribute.String("route", "/products")))
+	counter.Add(ctx, 3, metric.WithAttributes(attribute.String("route", "/health")))
+	gauge.Record(ctx, 21.5)
+	histogram.Record(ctx, 0.25)
+	histogram.Record(ctx, 0.75)
+
+	tests := []struct {
+		name   string
+		metric string
+		want   float64
+		found  bool
+	}{
+		{name: "counter summed across attributes", metric: "test.requests", want: 5, found: true},
+		{name: "gauge", metric: "test.temperature", want: 21.5, found: true},
+		{name: "histogram sum", metric: "test.latency", want: 1, found: true},
+		{name: "unrecorded metric", metric: "test.missing", want: 0, found: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := harness.MetricValue(tt.metric)
+			if got != tt.want || found != tt.found {
+				t.Errorf("MetricValue(%q) = %v, %v, want %v, %v", tt.metric, got, found, tt.want, tt.found)
+			}
+		})
+	}
+
+	labelled := []struct {
+		name  string
+		attrs []attribute.KeyValue
+		want  float64
+		found bool
+	}{
+		{name: "no attributes matches every point", want: 5, found: true},
+		{name: "one route", attrs: []attribute.KeyValue{attribute.String("route", "/health")}, want: 3, found: true},
+		{name: "unknown route", attrs: []attribute.KeyValue{attribute.String("route", "/missing")}, want: 0, found: false},
+		{name: "same value of another type", attrs: []attribute.KeyValue{attribute.Int("route", 3)}, want: 0, found: false},
+	}
+	for _, tt := range labelled {
+		t.Run("with "+tt.name, func(t *testing.T) {
+			got, found := harness.MetricValueWith("test.requests", tt.attrs...)
+			if got != tt.want || found != tt.found {
+				t.Errorf("MetricValueWith(test.requests, %v) = %v, %v, want %v, %v", tt.attrs, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestHarnessCapturesLogRecords(t *testing.T) {
+	harness.Reset()
+	logger := global.GetLoggerProvider().Logger("telemetrytest_test")
+
+	var record otellog.Record
+	record.SetBody(otellog.StringValue("stock updated"))
+	record.SetSeverity(otellog.SeverityInfo)
+	logger.Emit(context.Background(), record)
+
+	records := harness.LogRecords()
+	if len(records) != 1 {
+		t.Fatalf("recorded %d log records, want 1", len(records))
+	}
+	if got := records[0].Body().AsString(); got != "stock updated" {
+		t.Errorf("log body = %q, want %q", got, "stock updated")
+	}
+	if got := records[0].Severity(); got != otellog.SeverityInfo {
+		t.Errorf("log severity = %v, want %v", got, otellog.SeverityInfo)
+	}
+}
+
+func TestHarnessReset(t *testing.T) {
+	_, span := otel.Tracer("telemetrytest_test").Start(context.Background(), "before_reset")
+	span.End()
+	var record otellog.Record
+	record.SetBody(otellog.StringValue("before reset"))
+	global.GetLoggerProvider().Logger("telemetrytest_test").Emit(context.Background(), record)
+
+	harness.Reset()
+
+	if got := len(harness.Spans()); got != 0 {
+		t.Errorf("recorded %d spans after Reset, want 0", got)
+	}
+	if got := len(harness.LogRecords()); got != 0 {
+		t.Errorf("recorded %d log records after Reset, want 0", got)
+	}
+}
+
+func TestHarnessTeardownRestoresGlobals(t *testing.T) {
+	tracerProvider := otel.GetTracerProvider()
+	meterProvider := otel.GetMeterProvider()
+	loggerProvider := global.GetLoggerProvider()
+
+	nested := NewTestHarness()
+	if otel.GetTracerProvider() == tracerProvider {
+		t.Fatalf("NewTestHarness did not install its tracer provider")
+	}
+	nested.Teardown()
+
+	if otel.GetTracerProvider() != tracerProvider {
+		t.Errorf("tracer provider not restored by Teardown")
+	}
+	if otel.GetMeterProvider() != meterProvider {
+		t.Errorf("meter provider not restored by Teardown")
+	}
+	if global.GetLoggerProvider() != loggerProvider {
+		t.Errorf("logger provider not restored by Teardown")
+	}
+}
@@ -0,0 +1,18 @@
+package telemetry
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/narender/common/config"
+)
+
+// exporterTLSConfig builds the TLS configuration for the collector connection.
+// Server certificates are verified against the system roots.
+func exporterTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	minVersion, err := cfg.ExporterTLSMinVersion()
+	if err != nil {
+		return nil, fmt.Errorf("invalid exporter TLS configuration: %w", err)
+	}
+	return &tls.Config{MinVersion: minVersion}, nil
+}
@@ -0,0 +1,38 @@
+package telemetry
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/narender/common/config"
+)
+
+func TestExporterTLSConfigUsesTheMinimumVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    uint16
+		wantErr bool
+	}{
+		{version: "1.2", want: tls.VersionTLS12},
+		{version: "1.3", want: tls.VersionTLS13},
+		{version: "1.1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := exporterTLSConfig(&config.Config{OTEL_EXPORTER_TLS_MIN_VERSION: tt.version})
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("exporterTLSConfig(%q) built a config, want an error", tt.version)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("exporterTLSConfig(%q): %v", tt.version, err)
+		}
+		if got.MinVersion != tt.want {
+			t.Errorf("exporterTLSConfig(%q).MinVersion = %#x, want %#x", tt.version, got.MinVersion, tt.want)
+		}
+		if got.InsecureSkipVerify {
+			t.Errorf("exporterTLSConfig(%q) skips certificate verification", tt.version)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type causeKey struct{}
+
+// AttrCausedBy names the operation that triggered a downstream span.
+const AttrCausedBy = "caused_by"
+
+// WithCause records on ctx that work started from it is caused by the given
+// operation. The attributes are attached to any span that calls CauseAttributes.
+func WithCause(ctx context.Context, operation string, attrs ...attribute.KeyValue) context.Context {
+	causeAttrs := append([]attribute.KeyValue{attribute.String(AttrCausedBy, operation)}, attrs...)
+	return context.WithValue(ctx, causeKey{}, causeAttrs)
+}
+
+// CauseAttributes returns the causal attributes stored by WithCause, or nil.
+func CauseAttributes(ctx context.Context) []attribute.KeyValue {
+	attrs, _ := ctx.Value(causeKey{}).([]attribute.KeyValue)
+	return attrs
+}
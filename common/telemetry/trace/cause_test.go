@@ -0,0 +1,47 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestCauseAttributes(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []attribute.KeyValue
+	}{
+		{name: "no cause", ctx: context.Background()},
+		{
+			name: "operation only",
+			ctx:  WithCause(context.Background(), "buy_product"),
+			want: []attribute.KeyValue{attribute.String(AttrCausedBy, "buy_product")},
+		},
+		{
+			name: "operation with attributes",
+			ctx:  WithCause(context.Background(), "buy_product", attribute.Int("purchase.quantity", 2)),
+			want: []attribute.KeyValue{attribute.String(AttrCausedBy, "buy_product"), attribute.Int("purchase.quantity", 2)},
+		},
+		{
+			name: "innermost cause wins",
+			ctx:  WithCause(WithCause(context.Background(), "restock"), "buy_product"),
+			want: []attribute.KeyValue{attribute.String(AttrCausedBy, "buy_product")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CauseAttributes(tt.ctx)
+			if len(got) != len(tt.want) {
+				t.Fatalf("CauseAttributes = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("CauseAttributes[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/narender/common/telemetry/metric"
+)
+
+// spanOverheadBytes approximates the fixed protobuf cost of a span
+// (ids, timestamps, kind, status) before names and attributes.
+const spanOverheadBytes = 64
+
+// chunkRetryBaseDelay is the wait before the first retry of a failed chunk; it
+// doubles with each further attempt.
+const chunkRetryBaseDelay = 100 * time.Millisecond
+
+// chunkingExporter splits batches whose estimated encoded size exceeds maxBytes
+// so a single burst of spans does not exceed the collector's max message size.
+type chunkingExporter struct {
+	next       sdktrace.SpanExporter
+	maxBytes   int
+	retries    int
+	retryDelay time.Duration
+}
+
+// NewChunkingExporter wraps next so each export call carries at most maxBytes of
+// estimated span payload. When a batch is split, a chunk that fails to export is
+// retried up to retries more times. A non-positive maxBytes disables chunking.
+func NewChunkingExporter(next sdktrace.SpanExporter, maxBytes, retries int) sdktrace.SpanExporter {
+	if maxBytes <= 0 {
+		return next
+	}
+	return &chunkingExporter{next: next, maxBytes: maxBytes, retries: max(0, retries), retryDelay: chunkRetryBaseDelay}
+}
+
+func (e *chunkingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	chunks := chunkSpans(spans, e.maxBytes)
+	if len(chunks) <= 1 {
+		return e.next.ExportSpans(ctx, spans)
+	}
+
+	metric.IncrementExportChunked(ctx, "traces")
+
+	// Export each chunk independently so one rejected chunk does not drop the rest.
+	var errs []error
+	for _, chunk := range chunks {
+		if err := e.exportChunk(ctx, chunk); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// exportChunk exports one chunk, retrying with exponential backoff until it
+// succeeds, the retries are used up or ctx is done. Chunks that already went
+// through are not resent.
+func (e *chunkingExporter) exportChunk(ctx context.Context, chunk []sdktrace.ReadOnlySpan) error {
+	err := e.next.ExportSpans(ctx, chunk)
+	delay := e.retryDelay
+	for attempt := 0; err != nil && attempt < e.retries; attempt++ {
+		if ctx.Err() != nil {
+			return errors.Join(err, ctx.Err())
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+		delay *= 2
+		err = e.next.ExportSpans(ctx, chunk)
+	}
+	return err
+}
+
+func (e *chunkingExporter) Shutdown(ctx context.Context) error {
+	return e.next.Shutdown(ctx)
+}
+
+// chunkSpans groups spans into consecutive chunks whose estimated size stays
+// under maxBytes. A single span larger than maxBytes gets a chunk of its own.
+func chunkSpans(spans []sdktrace.ReadOnlySpan, maxBytes int) [][]sdktrace.ReadOnlySpan {
+	var chunks [][]sdktrace.ReadOnlySpan
+	start, size := 0, 0
+	for i, span := range spans {
+		spanSize := estimateSpanSize(span)
+		if i > start && size+spanSize > maxBytes {
+			chunks = append(chunks, spans[start:i])
+			start, size = i, 0
+		}
+		size += spanSize
+	}
+	if start < len(spans) {
+		chunks = append(chunks, spans[start:])
+	}
+	return chunks
+}
+
+// estimateSpanSize approximates the encoded size of a span from its name,
+// attributes and events. It is deliberately cheap rather than exact.
+func estimateSpanSize(span sdktrace.ReadOnlySpan) int {
+	size := spanOverheadBytes + len(span.Name()) + len(span.Status().Description)
+	for _, attr := range span.Attributes() {
+		size += len(attr.Key) + len(attr.Value.Emit())
+	}
+	for _, event := range span.Events() {
+		size += spanOverheadBytes + len(event.Name)
+		for _, attr := range event.Attributes {
+			size += len(attr.Key) + len(attr.Value.Emit())
+		}
+	}
+	return size
+}
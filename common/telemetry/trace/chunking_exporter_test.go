@@ -0,0 +1,167 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordingExporter records the span names of each export call and fails the
+// calls whose first span is listed in failures, once per remaining count.
+type recordingExporter struct {
+	calls    [][]string
+	failures map[string]int
+}
+
+func (e *recordingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name()
+	}
+	e.calls = append(e.calls, names)
+	if e.failures[names[0]] > 0 {
+		e.failures[names[0]]--
+		return fmt.Errorf("export of chunk starting at %s rejected", names[0])
+	}
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// testSpans builds n ended spans named span-0..span-(n-1), each carrying a payload
+// attribute of payloadBytes bytes.
+func testSpans(n, payloadBytes int) []sdktrace.ReadOnlySpan {
+	stubs := make(tracetest.SpanStubs, n)
+	for i := range stubs {
+		stubs[i] = tracetest.SpanStub{
+			Name:       fmt.Sprintf("span-%d", i),
+			Attributes: []attribute.KeyValue{attribute.String("payload", strings.Repeat("x", payloadBytes))},
+		}
+	}
+	return stubs.Snapshots()
+}
+
+func newTestChunkingExporter(next sdktrace.SpanExporter, maxBytes, retries int) *chunkingExporter {
+	e := NewChunkingExporter(next, maxBytes, retries).(*chunkingExporter)
+	e.retryDelay = 0
+	return e
+}
+
+func TestChunkingExporterSplitsOversizedBatch(t *testing.T) {
+	spans := testSpans(10, 1000)
+	spanSize := estimateSpanSize(spans[0])
+
+	tests := []struct {
+		name       string
+		maxBytes   int
+		wantChunks int
+	}{
+		{name: "fits in one export", maxBytes: 10 * spanSize, wantChunks: 1},
+		{name: "two spans per chunk", maxBytes: 2*spanSize + 1, wantChunks: 5},
+		{name: "three spans per chunk", maxBytes: 3 * spanSize, wantChunks: 4},
+		{name: "span larger than limit gets its own chunk", maxBytes: spanSize / 2, wantChunks: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &recordingExporter{}
+			if err := newTestChunkingExporter(next, tt.maxBytes, 0).ExportSpans(context.Background(), spans); err != nil {
+				t.Fatalf("ExportSpans returned error: %v", err)
+			}
+
+			if len(next.calls) != tt.wantChunks {
+				t.Fatalf("export calls = %d, want %d", len(next.calls), tt.wantChunks)
+			}
+			exported := 0
+			for _, call := range next.calls {
+				if len(call) > 1 && len(call)*spanSize > tt.maxBytes {
+					t.Errorf("chunk of %d spans exceeds %d bytes", len(call), tt.maxBytes)
+				}
+				for _, name := range call {
+					if want := fmt.Sprintf("span-%d", exported); name != want {
+						t.Errorf("exported %s, want %s in order", name, want)
+					}
+					exported++
+				}
+			}
+			if exported != len(spans) {
+				t.Errorf("exported %d spans, want %d", exported, len(spans))
+			}
+		})
+	}
+}
+
+func TestChunkingExporterRetriesFailedChunk(t *testing.T) {
+	spans := testSpans(6, 1000)
+	maxBytes := 2 * estimateSpanSize(spans[0])
+
+	tests := []struct {
+		name      string
+		retries   int
+		failures  map[string]int
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "no failures", retries: 2, failures: map[string]int{}, wantCalls: 3},
+		{name: "transient failure recovered", retries: 2, failures: map[string]int{"span-2": 1}, wantCalls: 4},
+		{name: "failure on last allowed attempt", retries: 2, failures: map[string]int{"span-4": 2}, wantCalls: 5},
+		{name: "retries exhausted", retries: 2, failures: map[string]int{"span-2": 3}, wantCalls: 5, wantErr: true},
+		{name: "retries disabled", retries: 0, failures: map[string]int{"span-0": 1}, wantCalls: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &recordingExporter{failures: tt.failures}
+			err := newTestChunkingExporter(next, maxBytes, tt.retries).ExportSpans(context.Background(), spans)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExportSpans error = %v, want error %v", err, tt.wantErr)
+			}
+			if len(next.calls) != tt.wantCalls {
+				t.Errorf("export calls = %d, want %d", len(next.calls), tt.wantCalls)
+			}
+			// Every chunk that succeeded was exported exactly once
+			sent := make(map[string]int)
+			for _, call := range next.calls {
+				sent[call[0]]++
+			}
+			for first, count := range sent {
+				if _, failed := tt.failures[first]; !failed && count != 1 {
+					t.Errorf("chunk starting at %s exported %d times, want 1", first, count)
+				}
+			}
+		})
+	}
+}
+
+func TestChunkingExporterStopsRetryingWhenContextDone(t *testing.T) {
+	spans := testSpans(4, 1000)
+	next := &recordingExporter{failures: map[string]int{"span-0": 10, "span-2": 10}}
+	e := newTestChunkingExporter(next, 2*estimateSpanSize(spans[0]), 5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := e.ExportSpans(ctx, spans)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ExportSpans error = %v, want context.Canceled", err)
+	}
+	if len(next.calls) != 2 {
+		t.Errorf("export calls = %d, want one attempt per chunk", len(next.calls))
+	}
+}
+
+func TestNewChunkingExporterDisabled(t *testing.T) {
+	next := &recordingExporter{}
+	if got := NewChunkingExporter(next, 0, 2); got != sdktrace.SpanExporter(next) {
+		t.Errorf("NewChunkingExporter with maxBytes 0 should return the wrapped exporter")
+	}
+}
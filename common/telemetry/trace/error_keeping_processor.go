@@ -0,0 +1,166 @@
+package trace
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"github.com/narender/common/telemetry/pipeline"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxPendingTraces bounds the traces whose spans are held back waiting for their
+// local root span. Beyond it, a held trace is decided early from its trace ID.
+const maxPendingTraces = 4096
+
+// maxDecidedTraces bounds the remembered decisions used for spans that end after
+// their local root; the memory is cleared once it is full.
+const maxDecidedTraces = 4096
+
+// errorKeepingProcessor keeps every trace that contains an Error span and only a
+// share of the rest, approximating tail-based sampling without a collector. Spans
+// are held back until the local root span of their trace (the first span in this
+// process) ends, and then the whole trace is forwarded or dropped together, so a
+// kept error never loses its parents. The keep decision for successful traces is
+// derived from the trace ID. It only sees spans the head sampler recorded.
+type errorKeepingProcessor struct {
+	next      sdktrace.SpanProcessor
+	threshold uint64
+
+	mu      sync.Mutex
+	pending map[trace.TraceID]*pendingTrace
+	decided map[trace.TraceID]bool
+}
+
+// pendingTrace holds the ended spans of a trace whose local root is still open.
+type pendingTrace struct {
+	spans  []sdktrace.ReadOnlySpan
+	failed bool
+}
+
+// NewErrorKeepingProcessor wraps next, keeping all traces with an error span and
+// roughly successRatio of successful traces. Ratios outside [0, 1] are clamped.
+func NewErrorKeepingProcessor(next sdktrace.SpanProcessor, successRatio float64) sdktrace.SpanProcessor {
+	if successRatio < 0 {
+		successRatio = 0
+	} else if successRatio > 1 {
+		successRatio = 1
+	}
+	return &errorKeepingProcessor{
+		next:      next,
+		threshold: uint64(successRatio * (1 << 63)),
+		pending:   make(map[trace.TraceID]*pendingTrace),
+		decided:   make(map[trace.TraceID]bool),
+	}
+}
+
+func (p *errorKeepingProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *errorKeepingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	traceID := s.SpanContext().TraceID()
+	failed := s.Status().Code == codes.Error
+
+	p.mu.Lock()
+	if isLocalRoot(s) {
+		pt := p.pending[traceID]
+		delete(p.pending, traceID)
+		if pt == nil {
+			pt = &pendingTrace{}
+		}
+		pt.spans = append(pt.spans, s)
+		pt.failed = pt.failed || failed
+		keep := p.keep(traceID, pt)
+		if len(p.decided) >= maxDecidedTraces {
+			clear(p.decided)
+		}
+		p.decided[traceID] = keep
+		p.mu.Unlock()
+		p.release(pt.spans, keep)
+		return
+	}
+
+	// A span ending after its local root follows the decision made for its trace
+	if keep, ok := p.decided[traceID]; ok {
+		p.mu.Unlock()
+		p.release([]sdktrace.ReadOnlySpan{s}, keep || failed)
+		return
+	}
+
+	var evictedID trace.TraceID
+	var evicted *pendingTrace
+	pt := p.pending[traceID]
+	if pt == nil {
+		if len(p.pending) >= maxPendingTraces {
+			for id, held := range p.pending {
+				evictedID, evicted = id, held
+				delete(p.pending, id)
+				break
+			}
+		}
+		pt = &pendingTrace{}
+		p.pending[traceID] = pt
+	}
+	pt.spans = append(pt.spans, s)
+	pt.failed = pt.failed || failed
+	p.mu.Unlock()
+
+	if evicted != nil {
+		p.release(evicted.spans, p.keep(evictedID, evicted))
+	}
+}
+
+func (p *errorKeepingProcessor) Shutdown(ctx context.Context) error {
+	p.flushPending()
+	return p.next.Shutdown(ctx)
+}
+
+func (p *errorKeepingProcessor) ForceFlush(ctx context.Context) error {
+	p.flushPending()
+	return p.next.ForceFlush(ctx)
+}
+
+// flushPending decides every held trace without waiting for its local root.
+func (p *errorKeepingProcessor) flushPending() {
+	p.mu.Lock()
+	pending := p.pending
+	p.pending = make(map[trace.TraceID]*pendingTrace)
+	p.mu.Unlock()
+
+	for traceID, pt := range pending {
+		p.release(pt.spans, p.keep(traceID, pt))
+	}
+}
+
+// keep reports whether a trace is forwarded: always when one of its spans
+// failed, otherwise according to its trace ID.
+func (p *errorKeepingProcessor) keep(traceID trace.TraceID, pt *pendingTrace) bool {
+	return pt.failed || p.keepTrace(traceID)
+}
+
+// release forwards spans to next when keep is set and counts them as discarded
+// otherwise.
+func (p *errorKeepingProcessor) release(spans []sdktrace.ReadOnlySpan, keep bool) {
+	if !keep {
+		pipeline.Traces.RecordDiscarded(int64(len(spans)))
+		return
+	}
+	for _, s := range spans {
+		p.next.OnEnd(s)
+	}
+}
+
+// keepTrace uses the same trace ID arithmetic as sdktrace.TraceIDRatioBased.
+func (p *errorKeepingProcessor) keepTrace(traceID trace.TraceID) bool {
+	return binary.BigEndian.Uint64(traceID[8:16])>>1 < p.threshold
+}
+
+// isLocalRoot reports whether s is the first span of its trace in this process:
+// it has no parent, or its parent was propagated from another service.
+func isLocalRoot(s sdktrace.ReadOnlySpan) bool {
+	parent := s.Parent()
+	return !parent.IsValid() || parent.IsRemote()
+}
@@ -0,0 +1,143 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newErrorKeepingProvider(successRatio float64) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(NewErrorKeepingProcessor(sdktrace.NewSimpleSpanProcessor(exporter), successRatio)),
+	)
+	return tp, exporter
+}
+
+func exportedNames(exporter *tracetest.InMemoryExporter) []string {
+	var names []string
+	for _, span := range exporter.GetSpans() {
+		names = append(names, span.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func failSpan(span trace.Span) {
+	span.RecordError(errors.New("boom"))
+	span.SetStatus(codes.Error, "boom")
+}
+
+func TestErrorKeepingProcessorDecidesPerTrace(t *testing.T) {
+	tests := []struct {
+		name         string
+		successRatio float64
+		failRoot     bool
+		failChild    bool
+		want         []string
+	}{
+		{name: "successful trace dropped as a whole", successRatio: 0},
+		{name: "failed child keeps its successful parents", successRatio: 0, failChild: true, want: []string{"child", "grandchild", "root"}},
+		{name: "failed root keeps its children", successRatio: 0, failRoot: true, want: []string{"child", "grandchild", "root"}},
+		{name: "successful trace kept at full ratio", successRatio: 1, want: []string{"child", "grandchild", "root"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, exporter := newErrorKeepingProvider(tt.successRatio)
+			tracer := tp.Tracer("error_keeping_test")
+
+			ctx, root := tracer.Start(context.Background(), "root")
+			childCtx, child := tracer.Start(ctx, "child")
+			_, grandchild := tracer.Start(childCtx, "grandchild")
+			grandchild.End()
+			if tt.failChild {
+				failSpan(child)
+			}
+			child.End()
+			if tt.failRoot {
+				failSpan(root)
+			}
+			root.End()
+
+			got := exportedNames(exporter)
+			if len(got) != len(tt.want) {
+				t.Fatalf("exported %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("exported %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorKeepingProcessorTreatsRemoteParentAsRoot(t *testing.T) {
+	tp, exporter := newErrorKeepingProvider(0)
+	tracer := tp.Tracer("error_keeping_test")
+
+	remote := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx, server := tracer.Start(trace.ContextWithRemoteSpanContext(context.Background(), remote), "server")
+	_, child := tracer.Start(ctx, "child")
+	failSpan(child)
+	child.End()
+
+	if got := exportedNames(exporter); len(got) != 0 {
+		t.Fatalf("exported %v before the local root ended, want nothing", got)
+	}
+	server.End()
+
+	if got := exportedNames(exporter); len(got) != 2 {
+		t.Errorf("exported %v, want child and server", got)
+	}
+}
+
+func TestErrorKeepingProcessorSpansEndingAfterRoot(t *testing.T) {
+	tp, exporter := newErrorKeepingProvider(0)
+	tracer := tp.Tracer("error_keeping_test")
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, lateOK := tracer.Start(ctx, "late_ok")
+	_, lateFailed := tracer.Start(ctx, "late_failed")
+	root.End()
+
+	lateOK.End()
+	failSpan(lateFailed)
+	lateFailed.End()
+
+	got := exportedNames(exporter)
+	if len(got) != 1 || got[0] != "late_failed" {
+		t.Errorf("exported %v, want only late_failed", got)
+	}
+}
+
+func TestErrorKeepingProcessorForceFlushReleasesHeldSpans(t *testing.T) {
+	tp, exporter := newErrorKeepingProvider(0)
+	tracer := tp.Tracer("error_keeping_test")
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, failed := tracer.Start(ctx, "failed")
+	failSpan(failed)
+	failed.End()
+
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := exportedNames(exporter); len(got) != 1 || got[0] != "failed" {
+		t.Errorf("exported %v after ForceFlush, want the held failed span", got)
+	}
+	root.End()
+}
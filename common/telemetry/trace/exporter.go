@@ -7,31 +7,39 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	"google.golang.org/grpc"
 
 	"github.com/narender/common/config"
+	"github.com/narender/common/telemetry/pipeline"
 )
 
-func SetupOtlpTraceExporter(ctx context.Context, cfg *config.Config, connOpts []grpc.DialOption, res *resource.Resource) error {
+func SetupOtlpTraceExporter(ctx context.Context, cfg *config.Config, conn *grpc.ClientConn, res *resource.Resource) error {
 	traceExporter, err := otlptracegrpc.New(ctx,
-		otlptracegrpc.WithEndpoint(cfg.OTEL_ENDPOINT),
-		otlptracegrpc.WithDialOption(connOpts...),
-		otlptracegrpc.WithInsecure(),
+		otlptracegrpc.WithGRPCConn(conn),
+		otlptracegrpc.WithHeaders(cfg.OtlpHeaders()),
+		otlptracegrpc.WithTimeout(cfg.OTEL_EXPORT_TIMEOUT),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create OTLP trace exporter: %w", err)
 	}
 
+	var exportProcessor trace.SpanProcessor = pipeline.WrapSpanProcessor(
+		trace.NewBatchSpanProcessor(pipeline.WrapSpanExporter(NewChunkingExporter(traceExporter, cfg.OTEL_MAX_EXPORT_BATCH_BYTES, cfg.OTEL_EXPORT_CHUNK_RETRIES))))
+	if cfg.OTEL_TAIL_ERROR_SAMPLING {
+		exportProcessor = NewErrorKeepingProcessor(exportProcessor, cfg.OTEL_TAIL_SUCCESS_RATIO)
+		log.Printf("Error-keeping span processor enabled (success ratio %.2f).\n", cfg.OTEL_TAIL_SUCCESS_RATIO)
+	}
+
 	tp := trace.NewTracerProvider(
 		trace.WithResource(res),
-		trace.WithSpanProcessor(trace.NewBatchSpanProcessor(traceExporter)),
+		trace.WithSampler(NewSampler(cfg)),
+		trace.WithSpanProcessor(OpenSpanCounter{}),
+		trace.WithSpanProcessor(exportProcessor),
 	)
-	// Set the global TracerProvider and Propagator for the application.
+	// Set the global TracerProvider for the application.
 	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
 	log.Println("OTel TracerProvider initialized and set globally.")
 	return nil
 }
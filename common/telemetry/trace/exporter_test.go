@@ -0,0 +1,62 @@
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caarlos0/env/v10"
+	"github.com/narender/common/config"
+	"github.com/narender/common/telemetry/telemetrytest"
+	"go.opentelemetry.io/otel"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestTraceExportUsesTheExportTimeout(t *testing.T) {
+	cfg := &config.Config{}
+	if err := env.Parse(cfg); err != nil {
+		t.Fatalf("parse configuration: %v", err)
+	}
+	cfg.OTEL_EXPORT_TIMEOUT = 300 * time.Millisecond
+	cfg.OTEL_READER_TIMEOUT = time.Minute
+	cfg.OTEL_EXPORT_CHUNK_RETRIES = 0
+
+	// Without connect parameters gRPC keeps trying to connect for 20s, so only the
+	// export timeout can end the export early
+	conn, err := grpc.NewClient(telemetrytest.SilentCollector(t), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	defer conn.Close()
+
+	previous := otel.GetTracerProvider()
+	if err := SetupOtlpTraceExporter(context.Background(), cfg, conn, sdkresource.Empty()); err != nil {
+		t.Fatalf("SetupOtlpTraceExporter: %v", err)
+	}
+	tp := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	defer func() {
+		otel.SetTracerProvider(previous)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		tp.Shutdown(ctx)
+	}()
+
+	_, span := tp.Tracer("exporter_test").Start(context.Background(), "export_me")
+	span.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	start := time.Now()
+	err = tp.ForceFlush(ctx)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Errorf("ForceFlush succeeded against a collector that never answers")
+	}
+	if elapsed > 3*time.Second {
+		t.Errorf("export gave up after %s, want about the 300ms export timeout", elapsed)
+	}
+}
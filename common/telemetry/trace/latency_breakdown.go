@@ -0,0 +1,119 @@
+package trace
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Layers reported by LatencyBreakdown, derived from the span component name.
+const (
+	LayerHandler    = "handler"
+	LayerService    = "service"
+	LayerRepository = "repo"
+	LayerDatabase   = "db"
+	LayerDownstream = "downstream"
+)
+
+// LayerFor maps a span component to its layer, or "" for components outside the
+// request path layers (e.g. request decoding, which counts towards its handler).
+func LayerFor(component string) string {
+	switch {
+	case strings.HasSuffix(component, "_handler"):
+		return LayerHandler
+	case strings.HasSuffix(component, "_service"):
+		return LayerService
+	case strings.HasSuffix(component, "_repository"):
+		return LayerRepository
+	case strings.HasSuffix(component, "_database"):
+		return LayerDatabase
+	case strings.HasSuffix(component, "_client"):
+		return LayerDownstream
+	default:
+		return ""
+	}
+}
+
+type breakdownKey struct{}
+type layerKey struct{}
+
+// LatencyBreakdown accumulates, for one request, the time spent in each layer's
+// spans. It is filled in by spans from StartSpan whatever the sampling decision.
+type LatencyBreakdown struct {
+	mu sync.Mutex
+	// Time inside the outermost spans of each layer
+	totals map[string]time.Duration
+	// Time inside spans of another layer started directly under each layer
+	nested map[string]time.Duration
+}
+
+// WithLatencyBreakdown attaches a new LatencyBreakdown to ctx.
+func WithLatencyBreakdown(ctx context.Context) (context.Context, *LatencyBreakdown) {
+	b := &LatencyBreakdown{
+		totals: make(map[string]time.Duration),
+		nested: make(map[string]time.Duration),
+	}
+	return context.WithValue(ctx, breakdownKey{}, b), b
+}
+
+// SelfTimes returns the time spent in each layer excluding the layers it called,
+// e.g. handler time without the service call it made.
+func (b *LatencyBreakdown) SelfTimes() map[string]time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	self := make(map[string]time.Duration, len(b.totals))
+	for layer, total := range b.totals {
+		self[layer] = max(0, total-b.nested[layer])
+	}
+	return self
+}
+
+func (b *LatencyBreakdown) record(layer, parentLayer string, d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.totals[layer] += d
+	if parentLayer != "" {
+		b.nested[parentLayer] += d
+	}
+}
+
+// trackLayer wraps span so its duration is added to the request's breakdown. Spans
+// nested in a span of the same layer are not counted again.
+func trackLayer(ctx, newCtx context.Context, component string, span trace.Span) (context.Context, trace.Span) {
+	b, ok := ctx.Value(breakdownKey{}).(*LatencyBreakdown)
+	layer := LayerFor(component)
+	if !ok || layer == "" {
+		return newCtx, span
+	}
+	parentLayer, _ := ctx.Value(layerKey{}).(string)
+	if parentLayer == layer {
+		return newCtx, span
+	}
+	return context.WithValue(newCtx, layerKey{}, layer), &layerSpan{
+		Span:        span,
+		breakdown:   b,
+		layer:       layer,
+		parentLayer: parentLayer,
+		start:       time.Now(),
+	}
+}
+
+// layerSpan reports its duration to a LatencyBreakdown when ended.
+type layerSpan struct {
+	trace.Span
+	breakdown   *LatencyBreakdown
+	layer       string
+	parentLayer string
+	start       time.Time
+	once        sync.Once
+}
+
+func (s *layerSpan) End(options ...trace.SpanEndOption) {
+	s.once.Do(func() {
+		s.breakdown.record(s.layer, s.parentLayer, time.Since(s.start))
+	})
+	s.Span.End(options...)
+}
@@ -0,0 +1,84 @@
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLayerFor(t *testing.T) {
+	tests := []struct {
+		component string
+		want      string
+	}{
+		{component: "product_handler", want: LayerHandler},
+		{component: "product_service", want: LayerService},
+		{component: "product_repository", want: LayerRepository},
+		{component: "file_database", want: LayerDatabase},
+		{component: "inventory_client", want: LayerDownstream},
+		{component: "request", want: ""},
+		{component: "handler", want: ""},
+	}
+	for _, tt := range tests {
+		if got := LayerFor(tt.component); got != tt.want {
+			t.Errorf("LayerFor(%q) = %q, want %q", tt.component, got, tt.want)
+		}
+	}
+}
+
+func TestLatencyBreakdownSelfTimes(t *testing.T) {
+	const step = 20 * time.Millisecond
+	ctx, breakdown := WithLatencyBreakdown(context.Background())
+
+	// handler → decode → service → repository → repository → database, each
+	// sleeping step before starting the next
+	handlerCtx, handler := StartSpan(ctx, "product_handler", "buy_product")
+	time.Sleep(step)
+	decodeCtx, decode := StartSpan(handlerCtx, "request", "decode")
+	time.Sleep(step)
+	decode.End()
+	serviceCtx, service := StartSpan(decodeCtx, "product_service", "buy")
+	time.Sleep(step)
+	repoCtx, repo := StartSpan(serviceCtx, "product_repository", "update_stock")
+	time.Sleep(step)
+	innerCtx, inner := StartSpan(repoCtx, "product_repository", "read")
+	time.Sleep(step)
+	_, db := StartSpan(innerCtx, "file_database", "read")
+	time.Sleep(step)
+	db.End()
+	inner.End()
+	repo.End()
+	service.End()
+	handler.End()
+
+	self := breakdown.SelfTimes()
+	tests := []struct {
+		layer string
+		steps int
+	}{
+		// The decode span has no layer, so its time counts towards the handler
+		{layer: LayerHandler, steps: 2},
+		{layer: LayerService, steps: 1},
+		// The nested repository span is not counted a second time
+		{layer: LayerRepository, steps: 2},
+		{layer: LayerDatabase, steps: 1},
+		{layer: LayerDownstream, steps: 0},
+	}
+	for _, tt := range tests {
+		want := time.Duration(tt.steps) * step
+		if got := self[tt.layer]; got < want || got > want+step {
+			t.Errorf("%s self time = %s, want about %s", tt.layer, got, want)
+		}
+	}
+}
+
+func TestLatencyBreakdownIgnoresSpansOutsideARequest(t *testing.T) {
+	ctx, span := StartSpan(context.Background(), "product_service", "buy")
+	span.End()
+	if _, ok := span.(*layerSpan); ok {
+		t.Errorf("span outside a request was wrapped for the latency breakdown")
+	}
+	if ctx.Value(layerKey{}) != nil {
+		t.Errorf("span outside a request recorded a layer on its context")
+	}
+}
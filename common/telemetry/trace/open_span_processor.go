@@ -0,0 +1,19 @@
+package trace
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/narender/common/telemetry/metric"
+)
+
+// OpenSpanCounter is a span processor that tracks spans started but not yet ended.
+// It sees every recording span, so a count that keeps growing points at a span
+// whose EndSpan was never called.
+type OpenSpanCounter struct{}
+
+func (OpenSpanCounter) OnStart(context.Context, sdktrace.ReadWriteSpan) { metric.AddOpenSpans(1) }
+func (OpenSpanCounter) OnEnd(sdktrace.ReadOnlySpan)                     { metric.AddOpenSpans(-1) }
+func (OpenSpanCounter) Shutdown(context.Context) error                  { return nil }
+func (OpenSpanCounter) ForceFlush(context.Context) error                { return nil }
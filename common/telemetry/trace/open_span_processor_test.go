@@ -0,0 +1,43 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/narender/common/telemetry/metric"
+)
+
+func TestOpenSpanCounterTracksUnendedSpans(t *testing.T) {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(OpenSpanCounter{}))
+	defer tp.Shutdown(context.Background())
+	tracer := tp.Tracer("open_span_test")
+	base := metric.OpenSpans()
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child")
+	_, leaked := tracer.Start(ctx, "leaked")
+
+	tests := []struct {
+		name string
+		end  func()
+		want int64
+	}{
+		{name: "all started", end: func() {}, want: 3},
+		{name: "child ended", end: func() { child.End() }, want: 2},
+		{name: "child ended twice", end: func() { child.End() }, want: 2},
+		{name: "parent ended, leaked span still open", end: func() { parent.End() }, want: 1},
+	}
+	for _, tt := range tests {
+		tt.end()
+		if got := metric.OpenSpans() - base; got != tt.want {
+			t.Errorf("%s: open spans = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	leaked.End()
+	if got := metric.OpenSpans() - base; got != 0 {
+		t.Errorf("after ending every span: open spans = %d, want 0", got)
+	}
+}
@@ -0,0 +1,37 @@
+package trace
+
+import (
+	"log"
+	"strings"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// NewPropagator builds a composite propagator from a comma-separated list of
+// propagator names (tracecontext, baggage, b3, b3multi). Unknown names are skipped,
+// and an empty result falls back to TraceContext+Baggage.
+func NewPropagator(names string) propagation.TextMapPropagator {
+	var propagators []propagation.TextMapPropagator
+	for _, name := range strings.Split(names, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "":
+			continue
+		case "tracecontext":
+			propagators = append(propagators, propagation.TraceContext{})
+		case "baggage":
+			propagators = append(propagators, propagation.Baggage{})
+		case "b3":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case "b3multi":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		default:
+			log.Printf("WARN: Unknown propagator %q in OTEL_PROPAGATORS, skipping.\n", name)
+		}
+	}
+
+	if len(propagators) == 0 {
+		propagators = []propagation.TextMapPropagator{propagation.TraceContext{}, propagation.Baggage{}}
+	}
+	return propagation.NewCompositeTextMapPropagator(propagators...)
+}
@@ -0,0 +1,89 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	testTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID  = "00f067aa0ba902b7"
+)
+
+func TestNewPropagatorExtractsB3(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   string
+		headers map[string]string
+	}{
+		{
+			name:    "b3 single header",
+			names:   "b3",
+			headers: map[string]string{"b3": testTraceID + "-" + testSpanID + "-1"},
+		},
+		{
+			name:  "b3 multiple headers",
+			names: "b3multi",
+			headers: map[string]string{
+				"x-b3-traceid": testTraceID,
+				"x-b3-spanid":  testSpanID,
+				"x-b3-sampled": "1",
+			},
+		},
+		{
+			name:    "b3 alongside tracecontext",
+			names:   "tracecontext, baggage, b3",
+			headers: map[string]string{"b3": testTraceID + "-" + testSpanID + "-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewPropagator(tt.names).Extract(context.Background(), propagation.MapCarrier(tt.headers))
+
+			sc := trace.SpanContextFromContext(ctx)
+			if !sc.IsValid() {
+				t.Fatalf("extracted span context is invalid")
+			}
+			if got := sc.TraceID().String(); got != testTraceID {
+				t.Errorf("trace ID = %s, want %s", got, testTraceID)
+			}
+			if got := sc.SpanID().String(); got != testSpanID {
+				t.Errorf("span ID = %s, want %s", got, testSpanID)
+			}
+			if !sc.IsSampled() {
+				t.Errorf("extracted span context is not sampled")
+			}
+			if !sc.IsRemote() {
+				t.Errorf("extracted span context is not remote")
+			}
+		})
+	}
+}
+
+func TestNewPropagatorIgnoresB3WhenNotConfigured(t *testing.T) {
+	headers := propagation.MapCarrier{"b3": testTraceID + "-" + testSpanID + "-1"}
+
+	ctx := NewPropagator("tracecontext,baggage").Extract(context.Background(), headers)
+
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		t.Errorf("b3 header was extracted although b3 is not configured")
+	}
+}
+
+func TestNewPropagatorFallsBackToTraceContext(t *testing.T) {
+	for _, names := range []string{"", " , ", "unknown"} {
+		t.Run(names, func(t *testing.T) {
+			headers := propagation.MapCarrier{"traceparent": "00-" + testTraceID + "-" + testSpanID + "-01"}
+
+			ctx := NewPropagator(names).Extract(context.Background(), headers)
+
+			if got := trace.SpanContextFromContext(ctx).TraceID().String(); got != testTraceID {
+				t.Errorf("trace ID = %q, want %q", got, testTraceID)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package trace
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/narender/common/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SamplingConfig describes the effective trace sampling setup.
+type SamplingConfig struct {
+	Ratio       float64 `json:"ratio"`
+	Warmup      string  `json:"warmup"`
+	ParentBased bool    `json:"parentBased"`
+	Description string  `json:"description"`
+}
+
+var (
+	samplingMu      sync.RWMutex
+	currentSampling SamplingConfig
+	// Root sampler behind the ParentBased sampler NewSampler last built
+	activeRoot *reloadableSampler
+)
+
+// NewSampler builds the sampler from configuration and records it as the
+// current sampling configuration. Ratios outside [0, 1] are clamped.
+func NewSampler(cfg *config.Config) sdktrace.Sampler {
+	ratio := cfg.OTEL_TRACE_SAMPLE_RATIO
+	if ratio < 0 {
+		ratio = 0
+	} else if ratio > 1 {
+		ratio = 1
+	}
+
+	root := sdktrace.TraceIDRatioBased(ratio)
+	if cfg.OTEL_TRACE_SAMPLE_WARMUP > 0 {
+		root = NewWarmupSampler(ratio, cfg.OTEL_TRACE_SAMPLE_WARMUP, time.Now)
+	}
+	reloadable := &reloadableSampler{root: root}
+	sampler := sdktrace.ParentBased(reloadable)
+
+	samplingMu.Lock()
+	activeRoot = reloadable
+	currentSampling = SamplingConfig{
+		Ratio:       ratio,
+		Warmup:      cfg.OTEL_TRACE_SAMPLE_WARMUP.String(),
+		ParentBased: true,
+		Description: sampler.Description(),
+	}
+	samplingMu.Unlock()
+
+	return sampler
+}
+
+// CurrentSamplingConfig returns the sampling configuration last built by NewSampler.
+func CurrentSamplingConfig() SamplingConfig {
+	samplingMu.RLock()
+	defer samplingMu.RUnlock()
+	return currentSampling
+}
+
+// SetSampleRatio replaces the root ratio of the sampler NewSampler last built, so
+// the ratio can change without rebuilding the tracer provider. Any warmup still in
+// progress is dropped. Ratios outside [0, 1] are clamped.
+func SetSampleRatio(ratio float64) error {
+	if ratio < 0 {
+		ratio = 0
+	} else if ratio > 1 {
+		ratio = 1
+	}
+
+	samplingMu.Lock()
+	defer samplingMu.Unlock()
+	if activeRoot == nil {
+		return fmt.Errorf("no sampler has been built yet")
+	}
+	activeRoot.set(sdktrace.TraceIDRatioBased(ratio))
+	currentSampling.Ratio = ratio
+	currentSampling.Warmup = time.Duration(0).String()
+	currentSampling.Description = sdktrace.ParentBased(activeRoot).Description()
+	return nil
+}
+
+// reloadableSampler delegates to a root sampler that can be swapped at runtime.
+type reloadableSampler struct {
+	mu   sync.RWMutex
+	root sdktrace.Sampler
+}
+
+func (s *reloadableSampler) set(root sdktrace.Sampler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.root = root
+}
+
+func (s *reloadableSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	s.mu.RLock()
+	root := s.root
+	s.mu.RUnlock()
+	return root.ShouldSample(p)
+}
+
+func (s *reloadableSampler) Description() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.root.Description()
+}
+
+// warmupSampler samples every trace right after startup and decays linearly to
+// the steady ratio over the warmup window, so fresh deploys are fully visible.
+type warmupSampler struct {
+	target float64
+	warmup time.Duration
+	start  time.Time
+	now    func() time.Time
+}
+
+// NewWarmupSampler returns a ratio sampler that ramps from 1.0 down to target over
+// warmup, measured from its creation using now.
+func NewWarmupSampler(target float64, warmup time.Duration, now func() time.Time) sdktrace.Sampler {
+	return &warmupSampler{target: target, warmup: warmup, start: now(), now: now}
+}
+
+// EffectiveRatio returns the ratio currently applied.
+func (s *warmupSampler) EffectiveRatio() float64 {
+	elapsed := s.now().Sub(s.start)
+	if elapsed >= s.warmup {
+		return s.target
+	}
+	progress := float64(elapsed) / float64(s.warmup)
+	return 1 - (1-s.target)*progress
+}
+
+func (s *warmupSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.TraceIDRatioBased(s.EffectiveRatio()).ShouldSample(p)
+}
+
+func (s *warmupSampler) Description() string {
+	return fmt.Sprintf("WarmupSampler{target=%g,warmup=%s}", s.target, s.warmup)
+}
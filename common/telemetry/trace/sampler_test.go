@@ -0,0 +1,110 @@
+package trace
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/narender/common/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewSamplerRecordsTheCurrentConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		ratio      float64
+		warmup     time.Duration
+		wantRatio  float64
+		wantWarmup string
+	}{
+		{name: "configured ratio", ratio: 0.25, wantRatio: 0.25, wantWarmup: "0s"},
+		{name: "negative ratio clamped", ratio: -1, wantRatio: 0, wantWarmup: "0s"},
+		{name: "ratio above one clamped", ratio: 3, wantRatio: 1, wantWarmup: "0s"},
+		{name: "with warmup", ratio: 0.1, warmup: 5 * time.Minute, wantRatio: 0.1, wantWarmup: "5m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sampler := NewSampler(&config.Config{OTEL_TRACE_SAMPLE_RATIO: tt.ratio, OTEL_TRACE_SAMPLE_WARMUP: tt.warmup})
+
+			got := CurrentSamplingConfig()
+			want := SamplingConfig{Ratio: tt.wantRatio, Warmup: tt.wantWarmup, ParentBased: true, Description: sampler.Description()}
+			if got != want {
+				t.Errorf("CurrentSamplingConfig() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetSampleRatio(t *testing.T) {
+	sampler := NewSampler(&config.Config{OTEL_TRACE_SAMPLE_RATIO: 1, OTEL_TRACE_SAMPLE_WARMUP: time.Minute})
+
+	if err := SetSampleRatio(0.5); err != nil {
+		t.Fatalf("SetSampleRatio: %v", err)
+	}
+
+	got := CurrentSamplingConfig()
+	if got.Ratio != 0.5 || got.Warmup != "0s" {
+		t.Errorf("after SetSampleRatio(0.5), ratio = %v and warmup = %q, want 0.5 and 0s", got.Ratio, got.Warmup)
+	}
+	if got.Description != sampler.Description() {
+		t.Errorf("description = %q, want the live sampler's %q", got.Description, sampler.Description())
+	}
+}
+
+func TestWarmupSamplerDecaysToTarget(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	now := start
+	sampler := NewWarmupSampler(0.2, 10*time.Minute, func() time.Time { return now }).(*warmupSampler)
+
+	tests := []struct {
+		elapsed time.Duration
+		want    float64
+	}{
+		{elapsed: 0, want: 1},
+		{elapsed: 5 * time.Minute, want: 0.6},
+		{elapsed: 10 * time.Minute, want: 0.2},
+		{elapsed: time.Hour, want: 0.2},
+	}
+	for _, tt := range tests {
+		now = start.Add(tt.elapsed)
+		if got := sampler.EffectiveRatio(); got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("EffectiveRatio after %s = %v, want %v", tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestWarmupSamplerDecisionsFollowTheRamp(t *testing.T) {
+	const traces = 2000
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	now := start
+	sampler := NewWarmupSampler(0, 10*time.Minute, func() time.Time { return now })
+
+	tests := []struct {
+		elapsed  time.Duration
+		min, max int
+	}{
+		{elapsed: 0, min: traces, max: traces},
+		{elapsed: 5 * time.Minute, min: traces * 4 / 10, max: traces * 6 / 10},
+		{elapsed: 9 * time.Minute, min: traces * 5 / 100, max: traces * 15 / 100},
+		{elapsed: 10 * time.Minute, min: 0, max: 0},
+	}
+	for _, tt := range tests {
+		now = start.Add(tt.elapsed)
+		random := rand.New(rand.NewSource(1))
+		sampled := 0
+		for i := 0; i < traces; i++ {
+			var traceID trace.TraceID
+			random.Read(traceID[:])
+			result := sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: context.Background(), TraceID: traceID, Name: "request"})
+			if result.Decision == sdktrace.RecordAndSample {
+				sampled++
+			}
+		}
+		if sampled < tt.min || sampled > tt.max {
+			t.Errorf("after %s sampled %d of %d traces, want between %d and %d", tt.elapsed, sampled, traces, tt.min, tt.max)
+		}
+	}
+}
@@ -2,6 +2,10 @@ package trace
 
 import (
 	"context"
+	"errors"
+	"strings"
+
+	apierrors "github.com/narender/common/apierrors"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -10,20 +14,60 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// AttrBusinessOutcome carries the code of a business rejection on spans that are
+// not marked as errored.
+const AttrBusinessOutcome = "business.outcome"
+
+// DefaultStatusMapper marks spans as errored for application failures only.
+// Business rule rejections (e.g. insufficient stock) are expected outcomes and
+// leave the status Unset so they do not skew error-rate dashboards.
 func DefaultStatusMapper(err error) codes.Code {
 	if err == nil {
 		return codes.Ok
 	}
 
+	if businessErr := asBusinessError(err); businessErr != nil {
+		return codes.Unset
+	}
 	return codes.Error
 }
 
+// asBusinessError returns err as an AppError if it belongs to the business category.
+func asBusinessError(err error) *apierrors.AppError {
+	var appErr *apierrors.AppError
+	if errors.As(err, &appErr) && appErr.Category == apierrors.CategoryBusiness {
+		return appErr
+	}
+	return nil
+}
+
 type StatusMapperFunc func(error) codes.Code
 
+// SpanKindFor infers the span kind from the component's layer: handlers receive
+// requests (SERVER), clients call other services (CLIENT), everything else is INTERNAL.
+func SpanKindFor(component string) trace.SpanKind {
+	switch {
+	case strings.HasSuffix(component, "_handler"):
+		return trace.SpanKindServer
+	case strings.HasSuffix(component, "_client"):
+		return trace.SpanKindClient
+	default:
+		return trace.SpanKindInternal
+	}
+}
+
 // StartSpan begins a new OTel span, inferring the operation name from the caller.
 // It uses a static tracer name and adds standard code attributes.
 // Enhanced to include component and operation as standard attributes.
+// The span kind is inferred from the component via SpanKindFor.
+// The returned span is never nil: without a configured provider it is a no-op span,
+// so callers need no nil checks.
 func StartSpan(ctx context.Context, component, operation string, initialAttrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return StartSpanWithKind(ctx, SpanKindFor(component), component, operation, initialAttrs...)
+}
+
+// StartSpanWithKind is StartSpan with an explicit span kind.
+func StartSpanWithKind(ctx context.Context, kind trace.SpanKind, component, operation string, initialAttrs ...attribute.KeyValue) (context.Context, trace.Span) {
 	// Add component and operation as standard attributes
 	standardAttrs := []attribute.KeyValue{
 		attribute.String("component", component),
@@ -46,7 +90,7 @@ func StartSpan(ctx context.Context, component, operation string, initialAttrs ..
 	// )
 
 	opts := []trace.SpanStartOption{
-		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithSpanKind(kind),
 		trace.WithAttributes(semconv.CodeFunctionKey.String(operationName)),
 		trace.WithAttributes(semconv.CodeNamespaceKey.String(tracerName)),
 	}
@@ -56,12 +100,15 @@ func StartSpan(ctx context.Context, component, operation string, initialAttrs ..
 
 	newCtx, span := tracer.Start(ctx, operationName, opts...)
 
-	return newCtx, span
+	return trackLayer(ctx, newCtx, component, span)
 }
 
 // EndSpan concludes the given span, automatically recording errors and setting status.
-// It expects a pointer to an error variable to check for failures.
+// It expects a pointer to an error variable to check for failures. A nil span is ignored.
 func EndSpan(span trace.Span, errPtr *error, statusMapper StatusMapperFunc, options ...trace.SpanEndOption) {
+	if span == nil {
+		return
+	}
 	defer span.End(options...)
 
 	if errPtr == nil || *errPtr == nil {
@@ -70,7 +117,11 @@ func EndSpan(span trace.Span, errPtr *error, statusMapper StatusMapperFunc, opti
 	}
 
 	err := *errPtr
-	span.RecordError(err, trace.WithStackTrace(true))
+	if businessErr := asBusinessError(err); businessErr != nil {
+		span.SetAttributes(attribute.String(AttrBusinessOutcome, businessErr.Code))
+	} else {
+		span.RecordError(err, trace.WithStackTrace(true))
+	}
 
 	mapper := statusMapper
 	if mapper == nil {
@@ -78,6 +129,10 @@ func EndSpan(span trace.Span, errPtr *error, statusMapper StatusMapperFunc, opti
 	}
 	statusCode := mapper(err)
 
+	if statusCode == codes.Unset {
+		return
+	}
+
 	statusMsg := ""
 	if statusCode == codes.Error {
 		statusMsg = err.Error()
@@ -0,0 +1,183 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	apierrors "github.com/narender/common/apierrors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// endedSpan ends a fresh span through EndSpan with err and returns what was recorded.
+func endedSpan(t *testing.T, err error, mapper StatusMapperFunc) tracetest.SpanStub {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(context.Background())
+
+	_, span := tp.Tracer("trace_test").Start(context.Background(), "operation")
+	EndSpan(span, &err, mapper)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	return spans[0]
+}
+
+func TestEndSpanStatusFollowsErrorCategory(t *testing.T) {
+	insufficientStock := apierrors.NewBusinessError(apierrors.ErrCodeInsufficientStock, "only 2 left", nil)
+	dbFailure := apierrors.NewApplicationError(apierrors.ErrCodeDatabaseAccess, "read failed", errors.New("disk"))
+
+	tests := []struct {
+		name          string
+		err           error
+		mapper        StatusMapperFunc
+		wantStatus    codes.Code
+		wantOutcome   string
+		wantException bool
+	}{
+		{name: "success", wantStatus: codes.Ok},
+		{name: "business rejection", err: insufficientStock, wantStatus: codes.Unset, wantOutcome: apierrors.ErrCodeInsufficientStock},
+		{name: "wrapped business rejection", err: fmt.Errorf("buy: %w", insufficientStock), wantStatus: codes.Unset, wantOutcome: apierrors.ErrCodeInsufficientStock},
+		{name: "database failure", err: dbFailure, wantStatus: codes.Error, wantException: true},
+		{name: "plain error", err: errors.New("boom"), wantStatus: codes.Error, wantException: true},
+		{name: "custom mapper", err: insufficientStock, mapper: func(error) codes.Code { return codes.Error }, wantStatus: codes.Error, wantOutcome: apierrors.ErrCodeInsufficientStock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := endedSpan(t, tt.err, tt.mapper)
+
+			if span.Status.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", span.Status.Code, tt.wantStatus)
+			}
+			var outcome string
+			for _, attr := range span.Attributes {
+				if attr.Key == AttrBusinessOutcome {
+					outcome = attr.Value.AsString()
+				}
+			}
+			if outcome != tt.wantOutcome {
+				t.Errorf("%s = %q, want %q", AttrBusinessOutcome, outcome, tt.wantOutcome)
+			}
+			var exception bool
+			for _, event := range span.Events {
+				exception = exception || event.Name == "exception"
+			}
+			if exception != tt.wantException {
+				t.Errorf("exception event recorded = %v, want %v", exception, tt.wantException)
+			}
+		})
+	}
+}
+
+func TestStartSpanKindFollowsTheLayer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		tp.Shutdown(context.Background())
+	})
+
+	tests := []struct {
+		name      string
+		component string
+		explicit  *trace.SpanKind
+		want      trace.SpanKind
+	}{
+		{name: "handler", component: "product_handler", want: trace.SpanKindServer},
+		{name: "service", component: "product_service", want: trace.SpanKindInternal},
+		{name: "repository", component: "product_repository", want: trace.SpanKindInternal},
+		{name: "database", component: "file_database", want: trace.SpanKindInternal},
+		{name: "client", component: "downstream_client", want: trace.SpanKindClient},
+		{name: "explicit kind wins", component: "product_handler", explicit: ptr(trace.SpanKindProducer), want: trace.SpanKindProducer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter.Reset()
+			var span trace.Span
+			if tt.explicit != nil {
+				_, span = StartSpanWithKind(context.Background(), *tt.explicit, tt.component, "op")
+			} else {
+				_, span = StartSpan(context.Background(), tt.component, "op")
+			}
+			span.End()
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("recorded %d spans, want 1", len(spans))
+			}
+			if got := spans[0].SpanKind; got != tt.want {
+				t.Errorf("%s span kind = %v, want %v", tt.component, got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestStartSpanNeverReturnsNil(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	tests := []struct {
+		name     string
+		provider trace.TracerProvider
+	}{
+		{name: "no-op provider", provider: noop.NewTracerProvider()},
+		{name: "SDK provider", provider: sdktrace.NewTracerProvider()},
+		{name: "SDK provider sampling nothing", provider: sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.NeverSample()))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			otel.SetTracerProvider(tt.provider)
+			ctx, span := StartSpan(context.Background(), "product_service", "buy_product")
+			if span == nil {
+				t.Fatalf("StartSpan returned a nil span")
+			}
+			if ctx == nil {
+				t.Fatalf("StartSpan returned a nil context")
+			}
+			err := errors.New("boom")
+			EndSpan(span, &err, nil)
+		})
+	}
+}
+
+func TestEndSpanIsNilSafe(t *testing.T) {
+	failure := error(apierrors.NewApplicationError(apierrors.ErrCodeDatabaseAccess, "disk full", nil))
+	var none error
+	tests := []struct {
+		name   string
+		errPtr *error
+		mapper StatusMapperFunc
+	}{
+		{name: "no error pointer", errPtr: nil},
+		{name: "nil error", errPtr: &none},
+		{name: "error", errPtr: &failure},
+		{name: "error with mapper", errPtr: &failure, mapper: func(error) codes.Code { return codes.Error }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("EndSpan panicked on a nil span: %v", r)
+				}
+			}()
+			EndSpan(nil, tt.errPtr, tt.mapper)
+		})
+	}
+}
@@ -6,51 +6,43 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/narender/common/debugutils"
+	commonMiddleware "github.com/narender/common/middleware"
 	commontrace "github.com/narender/common/telemetry/trace"
 	"go.opentelemetry.io/otel/attribute"
 
-	apierrors "github.com/narender/common/apierrors"
 	apirequests "github.com/narender/common/apirequests"
 	apiresponses "github.com/narender/common/apiresponses"
-	"github.com/narender/common/validator"
-	"go.opentelemetry.io/otel/codes"
 )
 
 func (h *ProductHandler) BuyProduct(c *fiber.Ctx) (err error) {
 	ctx := c.UserContext()
 
-	h.logger.InfoContext(ctx, "Purchase request received",
+	h.logger.DebugContext(ctx, "Purchase request received",
 		slog.String("component", "product_handler"),
 		slog.String("operation", "buy_product"),
 		slog.String("user_agent", c.Get("User-Agent")))
 
-	var req apirequests.ProductBuyRequest
-	if parseErr := c.BodyParser(&req); parseErr != nil {
-		h.logger.WarnContext(ctx, "Request rejected: invalid request format",
-			slog.String("component", "product_handler"),
-			slog.String("error", parseErr.Error()),
-			slog.String("operation", "buy_product"))
-		
-		
-
-		err = apierrors.NewApplicationError(
-			apierrors.ErrCodeRequestValidation,
-			"Invalid request body format",
-			parseErr)
-		return
-	}
+	newCtx, span := commontrace.StartSpan(ctx, "product_handler", "buy_product",
+		attribute.String(commonMiddleware.AttrEndUserID, commonMiddleware.ActorFromContext(ctx)))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if err != nil {
+			telemetryErr = err
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
 
-	if validatorErr := validator.ValidateRequest(&req); validatorErr != nil {
-		h.logger.WarnContext(ctx, "Request validation failed",
-			slog.String("component", "product_handler"),
-			slog.String("operation", "buy_product"),
-			slog.String("error", validatorErr.Error()))
-		err = validatorErr
+	var req apirequests.ProductBuyRequest
+	if err = h.decodeRequest(ctx, c, "buy_product", &req); err != nil {
 		return
 	}
 
 	productName := req.Name
 	quantity := req.Quantity
+	span.SetAttributes(
+		attribute.String("product.name", productName),
+		attribute.Int("product.purchase_quantity", quantity))
 
 	h.logger.DebugContext(ctx, "Processing purchase details",
 		slog.String("component", "product_handler"),
@@ -58,18 +50,6 @@ func (h *ProductHandler) BuyProduct(c *fiber.Ctx) (err error) {
 		slog.String("product_name", productName),
 		slog.Int("quantity", quantity))
 
-	newCtx, span := commontrace.StartSpan(ctx, "product_handler", "buy_product",
-		attribute.String("product.name", productName),
-		attribute.Int("product.purchase_quantity", quantity))
-	ctx = newCtx
-	defer func() {
-		var telemetryErr error
-		if err != nil {
-			telemetryErr = err
-		}
-		commontrace.EndSpan(span, &telemetryErr, nil)
-	}()
-
 	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
 		err = simAppErr
 		return
@@ -83,10 +63,6 @@ func (h *ProductHandler) BuyProduct(c *fiber.Ctx) (err error) {
 
 	revenue, appErr := h.service.BuyProduct(ctx, productName, quantity)
 	if appErr != nil {
-		if span != nil {
-			span.SetStatus(codes.Error, appErr.Error())
-		}
-
 		err = appErr
 		return
 	}
@@ -96,6 +72,7 @@ func (h *ProductHandler) BuyProduct(c *fiber.Ctx) (err error) {
 		slog.String("product_name", productName),
 		slog.Int("quantity", quantity),
 		slog.Float64("revenue", revenue),
+		slog.String("actor", commonMiddleware.ActorFromContext(ctx)),
 		slog.String("operation", "buy_product"),
 		slog.String("status", "success"))
 
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/debugutils"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+	apiresponses "github.com/narender/common/apiresponses"
+)
+
+// maxCompareNames bounds the number of products compared in one request.
+const maxCompareNames = 20
+
+// CompareProducts returns the products named in the comma-separated names query
+// parameter side by side. Unknown names are reported, not treated as errors.
+func (h *ProductHandler) CompareProducts(c *fiber.Ctx) (err error) {
+	ctx := c.UserContext()
+
+	names := parseCompareNames(c.Query("names"))
+
+	h.logger.DebugContext(ctx, "Product comparison request received",
+		slog.Int("compare_count", len(names)),
+		slog.String("operation", "compare_products"),
+		slog.String("component", "product_handler"))
+
+	if len(names) == 0 || len(names) > maxCompareNames {
+		h.logger.WarnContext(ctx, "Request validation failed: invalid names parameter",
+			slog.String("error_code", apierrors.ErrCodeRequestValidation),
+			slog.Int("compare_count", len(names)),
+			slog.String("operation", "compare_products"),
+			slog.String("component", "product_handler"),
+			slog.String("parameter_name", "names"))
+
+		err = apierrors.NewApplicationError(
+			apierrors.ErrCodeRequestValidation,
+			fmt.Sprintf("The 'names' query parameter must list between 1 and %d product names", maxCompareNames),
+			nil)
+		return
+	}
+
+	newCtx, span := commontrace.StartSpan(ctx, "product_handler", "compare_products",
+		attribute.Int("compare.count", len(names)))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if err != nil {
+			telemetryErr = err
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		err = simAppErr
+		return
+	}
+
+	comparison, appErr := h.service.Compare(ctx, names)
+	if appErr != nil {
+		err = appErr
+		return
+	}
+
+	span.SetAttributes(attribute.Int("products.returned.count", len(comparison.Products)))
+
+	h.logger.InfoContext(ctx, "Product comparison completed successfully",
+		slog.Int("found_count", len(comparison.Products)),
+		slog.Int("not_found_count", len(comparison.NotFound)),
+		slog.String("operation", "compare_products"),
+		slog.String("status", "success"))
+
+	response := apiresponses.NewSuccessResponse(comparison)
+
+	err = c.Status(http.StatusOK).JSON(response)
+	return
+}
+
+// parseCompareNames splits a comma-separated list, trimming blanks and dropping duplicates.
+func parseCompareNames(raw string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/featureflags"
+	commonMiddleware "github.com/narender/common/middleware"
+
+	apierrors "github.com/narender/common/apierrors"
+	apiresponses "github.com/narender/common/apiresponses"
+)
+
+// GetFeatureFlags reports every feature flag and its current value.
+func (h *ProductHandler) GetFeatureFlags(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	h.logger.DebugContext(ctx, "Feature flags requested",
+		slog.String("component", "product_handler"),
+		slog.String("operation", "get_feature_flags"))
+
+	response := apiresponses.NewSuccessResponse(featureflags.All())
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// UpdateFeatureFlags overrides feature flags at runtime from a {"name": bool} body.
+// Unknown flags are rejected and no flag is changed.
+func (h *ProductHandler) UpdateFeatureFlags(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var updates map[string]bool
+	if parseErr := c.BodyParser(&updates); parseErr != nil {
+		return apierrors.NewApplicationError(
+			apierrors.ErrCodeRequestValidation,
+			"Invalid request body format",
+			parseErr)
+	}
+
+	known := featureflags.All()
+	for name := range updates {
+		if _, ok := known[name]; !ok {
+			return apierrors.NewApplicationError(
+				apierrors.ErrCodeRequestValidation,
+				fmt.Sprintf("Unknown feature flag '%s'; known flags: %v", name, featureflags.Names()),
+				nil)
+		}
+	}
+
+	for name, enabled := range updates {
+		featureflags.Set(name, enabled)
+		h.logger.InfoContext(ctx, "Feature flag overridden",
+			slog.String("component", "product_handler"),
+			slog.String("operation", "update_feature_flags"),
+			slog.String("flag", name),
+			slog.Bool("enabled", enabled),
+			slog.String("actor", commonMiddleware.ActorFromContext(ctx)))
+	}
+
+	response := apiresponses.NewSuccessResponse(featureflags.All())
+	return c.Status(http.StatusOK).JSON(response)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	apiresponses "github.com/narender/common/apiresponses"
+)
+
+// GetHealthHistory returns the most recent readiness results, oldest first, to
+// help diagnose dependencies that flap between ready and not ready.
+func (h *ProductHandler) GetHealthHistory(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	results := h.healthHistory.Snapshot()
+
+	h.logger.DebugContext(ctx, "Health history requested",
+		slog.String("component", "product_handler"),
+		slog.String("operation", "get_health_history"),
+		slog.Int("results", len(results)))
+
+	response := apiresponses.NewSuccessResponse(fiber.Map{
+		"capacity": h.healthHistory.Capacity(),
+		"results":  results,
+	})
+	return c.Status(http.StatusOK).JSON(response)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	commonMiddleware "github.com/narender/common/middleware"
+	"github.com/narender/common/telemetry/metric"
+	"github.com/narender/common/telemetry/pipeline"
+
+	apiresponses "github.com/narender/common/apiresponses"
+)
+
+// ResetMetricAggregates zeroes the in-process aggregates (revenue total and
+// telemetry pipeline counts) to mark a test run or reporting period boundary.
+// Exported OTel counters are cumulative and are not touched.
+func (h *ProductHandler) ResetMetricAggregates(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	previousRevenueCents := metric.RevenueTotalCents()
+	metric.ResetAggregates()
+	pipeline.ResetAll()
+
+	h.logger.InfoContext(ctx, "In-memory metric aggregates reset",
+		slog.String("component", "product_handler"),
+		slog.String("operation", "reset_metric_aggregates"),
+		slog.Int64("previous_revenue_total_cents", previousRevenueCents),
+		slog.String("actor", commonMiddleware.ActorFromContext(ctx)))
+
+	response := apiresponses.NewSuccessResponse(
+		apiresponses.ActionConfirmation{Message: "Metric aggregates reset"},
+	)
+	return c.Status(http.StatusOK).JSON(response)
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TriggerPanic panics on purpose so the recovery path (500 response, span error,
+// recovered-panic counter) can be verified in a running deployment.
+func (h *ProductHandler) TriggerPanic(c *fiber.Ctx) error {
+	h.logger.WarnContext(c.UserContext(), "Triggering deliberate panic for recovery testing",
+		slog.String("component", "product_handler"),
+		slog.String("operation", "trigger_panic"))
+
+	panic("deliberate panic triggered via /debug/panic")
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	commontrace "github.com/narender/common/telemetry/trace"
+
+	apiresponses "github.com/narender/common/apiresponses"
+)
+
+// GetSamplingConfig reports the effective trace sampling configuration.
+func (h *ProductHandler) GetSamplingConfig(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	h.logger.DebugContext(ctx, "Sampling configuration requested",
+		slog.String("component", "product_handler"),
+		slog.String("operation", "get_sampling_config"))
+
+	response := apiresponses.NewSuccessResponse(commontrace.CurrentSamplingConfig())
+	return c.Status(http.StatusOK).JSON(response)
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/telemetry/pipeline"
+
+	apiresponses "github.com/narender/common/apiresponses"
+)
+
+// GetTelemetryHealth reports approximate queue depths and drop counts for the
+// trace, metric and log export pipelines.
+func (h *ProductHandler) GetTelemetryHealth(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	health := pipeline.CurrentHealth()
+
+	h.logger.DebugContext(ctx, "Telemetry pipeline health requested",
+		slog.String("component", "product_handler"),
+		slog.String("operation", "get_telemetry_health"),
+		slog.Int64("trace_queue_depth", health.Traces.QueueDepth),
+		slog.Int64("log_queue_depth", health.Logs.QueueDepth))
+
+	response := apiresponses.NewSuccessResponse(health)
+	return c.Status(http.StatusOK).JSON(response)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/globals"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+	apirequests "github.com/narender/common/apirequests"
+	"github.com/narender/common/validator"
+)
+
+// Validation outcomes recorded on the request decode span
+const (
+	decodeOutcomeValid     = "valid"
+	decodeOutcomeMalformed = "malformed"
+	decodeOutcomeInvalid   = "invalid"
+)
+
+// strictDecoding reports whether STRICT_REQUEST_DECODING covers operation.
+func strictDecoding(operation string) bool {
+	for _, name := range strings.Split(globals.Cfg().STRICT_REQUEST_DECODING, ",") {
+		if name = strings.TrimSpace(name); name == "*" || name == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeRequest parses the body into req, normalizes tagged fields and validates it
+// inside a request :: decode child span, so parse and validation time is traced and
+// malformed bodies still leave a span behind. ctx must carry the handler's span.
+func (h *ProductHandler) decodeRequest(ctx context.Context, c *fiber.Ctx, operation string, req interface{}) (err error) {
+	ctx, span := commontrace.StartSpan(ctx, "request", "decode",
+		attribute.Int("request.content_length", len(c.Body())))
+	defer commontrace.EndSpan(span, &err, nil)
+
+	// Only unknown fields are reported here; other decode errors are left to BodyParser
+	if strictDecoding(operation) && strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		decoder := json.NewDecoder(bytes.NewReader(c.Body()))
+		decoder.DisallowUnknownFields()
+		if decodeErr := decoder.Decode(req); decodeErr != nil {
+			if field, ok := strings.CutPrefix(decodeErr.Error(), "json: unknown field "); ok {
+				span.SetAttributes(attribute.String("request.validation.outcome", decodeOutcomeInvalid))
+				h.logger.WarnContext(ctx, "Request rejected: unknown field",
+					slog.String("component", "product_handler"),
+					slog.String("field", field),
+					slog.String("operation", operation))
+
+				return apierrors.NewApplicationError(
+					apierrors.ErrCodeRequestValidation,
+					fmt.Sprintf("Unknown field %s in request body", field),
+					decodeErr)
+			}
+		}
+	}
+
+	if parseErr := c.BodyParser(req); parseErr != nil {
+		span.SetAttributes(attribute.String("request.validation.outcome", decodeOutcomeMalformed))
+		h.logger.WarnContext(ctx, "Request rejected: invalid request format",
+			slog.String("component", "product_handler"),
+			slog.String("error", parseErr.Error()),
+			slog.String("operation", operation))
+
+		return apierrors.NewApplicationError(
+			apierrors.ErrCodeRequestValidation,
+			"Invalid request body format",
+			parseErr)
+	}
+
+	// Normalize before validation so a whitespace-only name is rejected as missing
+	for _, field := range apirequests.Normalize(req) {
+		span.AddEvent(field + ".normalized")
+	}
+
+	if validatorErr := validator.ValidateRequest(req); validatorErr != nil {
+		span.SetAttributes(attribute.String("request.validation.outcome", decodeOutcomeInvalid))
+		h.logger.WarnContext(ctx, "Request validation failed",
+			slog.String("component", "product_handler"),
+			slog.String("operation", operation),
+			slog.String("error", validatorErr.Error()))
+
+		return validatorErr
+	}
+
+	span.SetAttributes(attribute.String("request.validation.outcome", decodeOutcomeValid))
+	return nil
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ExportFormatVersion is sent in ExportVersionHeader so importers can detect
+// changes to the exported record layout. No bulk import endpoint exists yet;
+// the round trip through it is untested until one does.
+const ExportFormatVersion = 1
+
+// ExportVersionHeader carries ExportFormatVersion on export responses.
+const ExportVersionHeader = "X-Export-Version"
+
+// ExportProducts streams the complete catalog, soft-deleted products included, as
+// newline-delimited JSON with one product per line, in data file order. Products
+// are read from the data file one at a time while the body is written, so the
+// catalog is never held in memory. The standard response envelope is bypassed so
+// the output can be processed line by line. Errors after the first byte cannot
+// change the status any more; they end the stream early and are logged and
+// recorded on the span, which is ended once the body has been written so
+// export.count and export.bytes cover the whole stream.
+func (h *ProductHandler) ExportProducts(c *fiber.Ctx) (err error) {
+	ctx := c.UserContext()
+
+	newCtx, span := commontrace.StartSpan(ctx, "product_handler", "export_products")
+	ctx = newCtx
+	streaming := false
+	defer func() {
+		if streaming {
+			return
+		}
+		var telemetryErr error
+		if err != nil {
+			telemetryErr = err
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		err = simAppErr
+		return
+	}
+
+	c.Status(fiber.StatusOK)
+	c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	c.Set(ExportVersionHeader, strconv.Itoa(ExportFormatVersion))
+	streaming = true
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		var streamErr error
+		counter := &countingWriter{w: w}
+		written := 0
+		defer func() {
+			span.SetAttributes(
+				attribute.Int("export.count", written),
+				attribute.Int64("export.bytes", counter.n))
+			commontrace.EndSpan(span, &streamErr, nil)
+		}()
+
+		// Encoder terminates each record with a newline, which is the NDJSON separator
+		enc := json.NewEncoder(counter)
+		if appErr := h.service.ExportProducts(ctx, func(product models.Product) error {
+			if err := enc.Encode(product); err != nil {
+				return err
+			}
+			written++
+			if written%streamFlushEvery == 0 {
+				return w.Flush()
+			}
+			return nil
+		}); appErr != nil {
+			streamErr = appErr
+			h.logger.ErrorContext(ctx, "Product export ended early",
+				slog.String("component", "product_handler"),
+				slog.String("operation", "export_products"),
+				slog.Int("products_written", written),
+				slog.String("error", appErr.Error()))
+			return
+		}
+		streamErr = w.Flush()
+
+		h.logger.InfoContext(ctx, "Exported product catalog",
+			slog.String("component", "product_handler"),
+			slog.String("operation", "export_products"),
+			slog.Int("product_count", written),
+			slog.Int64("bytes", counter.n))
+	})
+	return nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w *bufio.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -6,17 +6,18 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/narender/common/debugutils"
+	"github.com/narender/common/globals"
 	commontrace "github.com/narender/common/telemetry/trace"
+	"github.com/narender/product-service/src/services"
 	"go.opentelemetry.io/otel/attribute"
 
 	apiresponses "github.com/narender/common/apiresponses"
-	"go.opentelemetry.io/otel/codes"
 )
 
 func (h *ProductHandler) GetAllProducts(c *fiber.Ctx) (err error) {
 	ctx := c.UserContext()
 
-	h.logger.InfoContext(ctx, "Initiating request processing for retrieving all products",
+	h.logger.DebugContext(ctx, "Initiating request processing for retrieving all products",
 		slog.String("component", "product_handler"),
 		slog.String("operation", "get_all_products"),
 		slog.String("user_agent", c.Get("User-Agent")))
@@ -40,11 +41,20 @@ func (h *ProductHandler) GetAllProducts(c *fiber.Ctx) (err error) {
 		slog.String("operation", "fetch_all_products"),
 		slog.String("component", "product_handler"))
 
-	products, appErr := h.service.GetAll(ctx)
+	sortOpts, appErr := services.ParseSortOptions(c.Query("sortBy"), c.Query("order"))
+	if appErr != nil {
+		err = appErr
+		return
+	}
+
+	fields, appErr := parseResponseFields(c, span)
+	if appErr != nil {
+		err = appErr
+		return
+	}
+
+	products, appErr := h.service.GetAll(ctx, sortOpts, c.QueryBool("includeDeleted", false))
 	if appErr != nil {
-		if span != nil {
-			span.SetStatus(codes.Error, appErr.Error())
-		}
 		err = appErr
 		return
 	}
@@ -58,8 +68,20 @@ func (h *ProductHandler) GetAllProducts(c *fiber.Ctx) (err error) {
 
 	span.SetAttributes(attribute.Int("products.count", productCount))
 
+	threshold := globals.Cfg().RESPONSE_STREAM_THRESHOLD
+	streamed := threshold > 0 && productCount > threshold
+	span.SetAttributes(attribute.Bool("response.streamed", streamed))
+	if streamed {
+		h.streamProducts(c, products, fields)
+		return nil
+	}
+
 	// Create response without request ID
-	response := apiresponses.NewSuccessResponse(products)
+	var data interface{} = products
+	if fields != nil {
+		data = shapeProducts(products, fields)
+	}
+	response := apiresponses.NewSuccessResponse(data)
 
 	err = c.Status(http.StatusOK).JSON(response)
 	return
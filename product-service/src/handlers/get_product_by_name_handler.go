@@ -9,51 +9,18 @@ import (
 	commontrace "github.com/narender/common/telemetry/trace"
 	"go.opentelemetry.io/otel/attribute"
 
-	apierrors "github.com/narender/common/apierrors"
 	apirequests "github.com/narender/common/apirequests"
 	apiresponses "github.com/narender/common/apiresponses"
-	"github.com/narender/common/validator"
-	"go.opentelemetry.io/otel/codes"
 )
 
 func (h *ProductHandler) GetProductByName(c *fiber.Ctx) (err error) {
 	ctx := c.UserContext()
 
-	var req apirequests.GetByNameRequest
-	if parseErr := c.BodyParser(&req); parseErr != nil {
-		h.logger.WarnContext(ctx, "Request rejected: invalid request format",
-			slog.String("component", "product_handler"),
-			slog.String("error", parseErr.Error()),
-			slog.String("error_code", apierrors.ErrCodeRequestValidation),
-			slog.String("operation", "get_product_by_name"))
-
-		err = apierrors.NewApplicationError(
-			apierrors.ErrCodeRequestValidation,
-			"Invalid request body format",
-			parseErr)
-		return
-	}
-
-	if validatorErr := validator.ValidateRequest(&req); validatorErr != nil {
-		h.logger.WarnContext(ctx, "Request validation failed",
-			slog.String("component", "product_handler"),
-			slog.String("error_code", validatorErr.Code),
-			slog.String("operation", "get_product_by_name"))
-
-		err = validatorErr
-		return
-	}
-
-	productName := req.Name
-
-	h.logger.InfoContext(ctx, "Product details request received",
+	h.logger.DebugContext(ctx, "Product details request received",
 		slog.String("component", "product_handler"),
-		slog.String("product_name", productName),
 		slog.String("operation", "get_product_by_name"))
 
-	productNameAttr := attribute.String("product.name", productName)
-
-	newCtx, span := commontrace.StartSpan(ctx, "product_handler", "get_product_by_name", productNameAttr)
+	newCtx, span := commontrace.StartSpan(ctx, "product_handler", "get_product_by_name")
 	ctx = newCtx
 	defer func() {
 		var telemetryErr error
@@ -63,6 +30,14 @@ func (h *ProductHandler) GetProductByName(c *fiber.Ctx) (err error) {
 		commontrace.EndSpan(span, &telemetryErr, nil)
 	}()
 
+	var req apirequests.GetByNameRequest
+	if err = h.decodeRequest(ctx, c, "get_product_by_name", &req); err != nil {
+		return
+	}
+
+	productName := req.Name
+	span.SetAttributes(attribute.String("product.name", productName))
+
 	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
 		err = simAppErr
 		return
@@ -73,12 +48,14 @@ func (h *ProductHandler) GetProductByName(c *fiber.Ctx) (err error) {
 		slog.String("product_name", productName),
 		slog.String("operation", "service_get_by_name"))
 
-	product, appErr := h.service.GetByName(ctx, productName)
+	fields, appErr := parseResponseFields(c, span)
 	if appErr != nil {
-		if span != nil {
-			span.SetStatus(codes.Error, appErr.Error())
-		}
+		err = appErr
+		return
+	}
 
+	product, appErr := h.service.GetByName(ctx, productName)
+	if appErr != nil {
 		err = appErr
 		return
 	}
@@ -90,7 +67,11 @@ func (h *ProductHandler) GetProductByName(c *fiber.Ctx) (err error) {
 		slog.String("status", "success"))
 
 	// Create response without RequestID
-	response := apiresponses.NewSuccessResponse(product)
+	var data interface{} = product
+	if fields != nil {
+		data = shapeProduct(product, fields)
+	}
+	response := apiresponses.NewSuccessResponse(data)
 
 	err = c.Status(http.StatusOK).JSON(response)
 	return
@@ -7,11 +7,11 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/narender/common/debugutils"
 	commontrace "github.com/narender/common/telemetry/trace"
+	"github.com/narender/product-service/src/services"
 	"go.opentelemetry.io/otel/attribute"
 
 	apierrors "github.com/narender/common/apierrors"
 	apiresponses "github.com/narender/common/apiresponses"
-	"go.opentelemetry.io/otel/codes"
 )
 
 func (h *ProductHandler) GetProductsByCategory(c *fiber.Ctx) (err error) {
@@ -19,7 +19,7 @@ func (h *ProductHandler) GetProductsByCategory(c *fiber.Ctx) (err error) {
 
 	category := c.Query("category")
 
-	h.logger.InfoContext(ctx, "Initiating category-filtered product retrieval request",
+	h.logger.DebugContext(ctx, "Initiating category-filtered product retrieval request",
 		slog.String("category", category),
 		slog.String("operation", "get_products_by_category"),
 		slog.String("component", "product_handler"),
@@ -60,11 +60,20 @@ func (h *ProductHandler) GetProductsByCategory(c *fiber.Ctx) (err error) {
 		slog.String("operation", "fetch_category_products"),
 		slog.String("component", "product_handler"))
 
-	products, appErr := h.service.GetByCategory(ctx, category)
+	sortOpts, appErr := services.ParseSortOptions(c.Query("sortBy"), c.Query("order"))
+	if appErr != nil {
+		err = appErr
+		return
+	}
+
+	fields, appErr := parseResponseFields(c, span)
+	if appErr != nil {
+		err = appErr
+		return
+	}
+
+	products, appErr := h.service.GetByCategory(ctx, category, sortOpts, c.QueryBool("includeDeleted", false))
 	if appErr != nil {
-		if span != nil {
-			span.SetStatus(codes.Error, appErr.Error())
-		}
 		err = appErr
 		return
 	}
@@ -80,7 +89,11 @@ func (h *ProductHandler) GetProductsByCategory(c *fiber.Ctx) (err error) {
 	span.SetAttributes(attribute.Int("products.returned.count", productCount))
 
 	// Create response without request ID
-	response := apiresponses.NewSuccessResponse(products)
+	var data interface{} = products
+	if fields != nil {
+		data = shapeProducts(products, fields)
+	}
+	response := apiresponses.NewSuccessResponse(data)
 
 	err = c.Status(http.StatusOK).JSON(response)
 	return
@@ -4,17 +4,21 @@ import (
 	"log/slog"
 
 	"github.com/narender/common/globals"
+	"github.com/narender/common/health"
 	"github.com/narender/product-service/src/services"
 )
 
 type ProductHandler struct {
 	service services.ProductService // Adjusted to use services.ProductService
 	logger  *slog.Logger
+	// Recent readiness results served by the health history debug endpoint
+	healthHistory *health.History
 }
 
 func NewProductHandler(svc services.ProductService) *ProductHandler { // Adjusted to use services.ProductService
 	return &ProductHandler{
-		service: svc,
-		logger:  globals.Logger(),
+		service:       svc,
+		logger:        globals.Logger(),
+		healthHistory: health.NewHistory(globals.Cfg().HEALTH_HISTORY_SIZE),
 	}
 }
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/debugutils"
+	commonMiddleware "github.com/narender/common/middleware"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+	apiresponses "github.com/narender/common/apiresponses"
+)
+
+// PurgeProduct permanently removes a soft-deleted product from the data file.
+func (h *ProductHandler) PurgeProduct(c *fiber.Ctx) (err error) {
+	ctx := c.UserContext()
+
+	// Product names contain spaces, so the path segment arrives escaped
+	productName, unescapeErr := url.PathUnescape(c.Params("name"))
+	if unescapeErr != nil {
+		err = apierrors.NewApplicationError(
+			apierrors.ErrCodeRequestValidation,
+			"Invalid product name in path",
+			unescapeErr)
+		return
+	}
+
+	newCtx, span := commontrace.StartSpan(ctx, "product_handler", "purge_product",
+		attribute.String("product.name", productName),
+		attribute.String(commonMiddleware.AttrEndUserID, commonMiddleware.ActorFromContext(ctx)))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if err != nil {
+			telemetryErr = err
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		err = simAppErr
+		return
+	}
+
+	if appErr := h.service.PurgeProduct(ctx, productName); appErr != nil {
+		err = appErr
+		return
+	}
+
+	h.logger.InfoContext(ctx, "Product purge completed successfully",
+		slog.String("component", "product_handler"),
+		slog.String("product_name", productName),
+		slog.String("actor", commonMiddleware.ActorFromContext(ctx)),
+		slog.String("operation", "purge_product"),
+		slog.String("status", "success"))
+
+	response := apiresponses.NewSuccessResponse(
+		apiresponses.ActionConfirmation{Message: "Product purged successfully"},
+	)
+
+	err = c.Status(http.StatusOK).JSON(response)
+	return
+}
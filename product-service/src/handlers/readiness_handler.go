@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/globals"
+	"github.com/narender/common/health"
+	"github.com/narender/common/telemetry/metric"
+)
+
+// Readiness reports whether the service can serve traffic: the product data file
+// must be readable and every OTLP exporter connected. Each result is recorded in
+// the health history.
+func (h *ProductHandler) Readiness(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var failing []string
+	if info, err := os.Stat(globals.Cfg().PRODUCT_DATA_FILE_PATH); err != nil || !info.Mode().IsRegular() {
+		failing = append(failing, "data_file")
+	}
+	for signal, connected := range metric.ExporterConnections() {
+		if !connected {
+			failing = append(failing, "exporter."+signal)
+		}
+	}
+	sort.Strings(failing)
+
+	result := health.Result{Time: time.Now().UTC(), Ready: len(failing) == 0, Failing: failing}
+	h.healthHistory.Record(result)
+
+	if !result.Ready {
+		h.logger.WarnContext(ctx, "Readiness check failed",
+			slog.String("component", "product_handler"),
+			slog.String("operation", "readiness"),
+			slog.Any("failing", failing))
+
+		return c.Status(http.StatusServiceUnavailable).JSON(fiber.Map{
+			"status":  "not_ready",
+			"failing": failing,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"status": "ready",
+	})
+}
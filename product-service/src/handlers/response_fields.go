@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// productFieldIndex maps each product JSON field name to its struct field index.
+var productFieldIndex = buildProductFieldIndex()
+
+func buildProductFieldIndex() map[string]int {
+	t := reflect.TypeOf(models.Product{})
+	index := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		index[name] = i
+	}
+	return index
+}
+
+// parseResponseFields reads the fields query parameter (e.g. "name,price") and
+// returns the requested product fields, or nil when the full product was asked for.
+// Unknown names are rejected so typos don't silently produce empty objects.
+func parseResponseFields(c *fiber.Ctx, span trace.Span) ([]string, *apierrors.AppError) {
+	raw := strings.TrimSpace(c.Query("fields"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		name := strings.TrimSpace(part)
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := productFieldIndex[name]; !ok {
+			known := make([]string, 0, len(productFieldIndex))
+			for k := range productFieldIndex {
+				known = append(known, k)
+			}
+			sort.Strings(known)
+			return nil, apierrors.NewApplicationError(
+				apierrors.ErrCodeRequestValidation,
+				fmt.Sprintf("Unknown field '%s' in fields parameter; allowed: %s", name, strings.Join(known, ",")),
+				nil)
+		}
+		seen[name] = true
+		fields = append(fields, name)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	span.SetAttributes(attribute.String("response.fields", strings.Join(fields, ",")))
+	return fields, nil
+}
+
+// shapeProduct projects a product onto the requested fields. Values keep their
+// own types so Money and time fields marshal exactly as in the full response.
+func shapeProduct(product models.Product, fields []string) map[string]interface{} {
+	v := reflect.ValueOf(product)
+	shaped := make(map[string]interface{}, len(fields))
+	for _, name := range fields {
+		shaped[name] = v.Field(productFieldIndex[name]).Interface()
+	}
+	return shaped
+}
+
+// shapeProducts projects every product onto the requested fields.
+func shapeProducts(products []models.Product, fields []string) []map[string]interface{} {
+	shaped := make([]map[string]interface{}, len(products))
+	for i := range products {
+		shaped[i] = shapeProduct(products[i], fields)
+	}
+	return shaped
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/debugutils"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+	apiresponses "github.com/narender/common/apiresponses"
+)
+
+func (h *ProductHandler) SearchProducts(c *fiber.Ctx) (err error) {
+	ctx := c.UserContext()
+
+	query := c.Query("q")
+	inDescription := c.QueryBool("inDescription", false)
+
+	h.logger.DebugContext(ctx, "Product search request received",
+		slog.String("query", query),
+		slog.Bool("in_description", inDescription),
+		slog.String("operation", "search_products"),
+		slog.String("component", "product_handler"))
+
+	if query == "" {
+		h.logger.WarnContext(ctx, "Request validation failed: required q parameter not provided",
+			slog.String("error_code", apierrors.ErrCodeRequestValidation),
+			slog.String("operation", "search_products"),
+			slog.String("component", "product_handler"),
+			slog.String("parameter_name", "q"))
+
+		err = apierrors.NewApplicationError(
+			apierrors.ErrCodeRequestValidation,
+			"Missing 'q' query parameter",
+			nil)
+		return
+	}
+
+	newCtx, span := commontrace.StartSpan(ctx, "product_handler", "search_products",
+		attribute.String("search.query", query),
+		attribute.Bool("search.in_description", inDescription))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if err != nil {
+			telemetryErr = err
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		err = simAppErr
+		return
+	}
+
+	results, appErr := h.service.Search(ctx, query, inDescription, c.QueryBool("includeDeleted", false))
+	if appErr != nil {
+		err = appErr
+		return
+	}
+
+	span.SetAttributes(attribute.Int("products.returned.count", len(results)))
+
+	h.logger.InfoContext(ctx, "Product search completed successfully",
+		slog.String("query", query),
+		slog.Int("result_count", len(results)),
+		slog.String("operation", "search_products"),
+		slog.String("status", "success"))
+
+	response := apiresponses.NewSuccessResponse(results)
+
+	err = c.Status(http.StatusOK).JSON(response)
+	return
+}
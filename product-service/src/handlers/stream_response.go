@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/models"
+)
+
+// streamFlushEvery is the number of products encoded between flushes of a streamed response.
+const streamFlushEvery = 100
+
+// streamProducts writes products in the standard success envelope, encoding and
+// flushing them incrementally with chunked transfer encoding instead of building the
+// whole payload in memory first. Encoding happens after the handler returns, so
+// failures can only be logged; the client sees a truncated body. A non-nil fields
+// list projects each product as it is encoded.
+func (h *ProductHandler) streamProducts(c *fiber.Ctx, products []models.Product, fields []string) {
+	ctx := c.UserContext()
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+
+	c.Status(fiber.StatusOK)
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		enc := json.NewEncoder(w)
+		w.WriteString(`{"status":"success","data":[`)
+		for i := range products {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			// Encoder appends a newline, which is valid whitespace between array elements
+			var item interface{} = products[i]
+			if fields != nil {
+				item = shapeProduct(products[i], fields)
+			}
+			if err := enc.Encode(item); err != nil {
+				h.logger.ErrorContext(ctx, "Failed to encode product in streamed response",
+					slog.String("component", "product_handler"),
+					slog.String("product_name", products[i].Name),
+					slog.String("error", err.Error()))
+				return
+			}
+			if (i+1)%streamFlushEvery == 0 {
+				if err := w.Flush(); err != nil {
+					h.logger.WarnContext(ctx, "Client went away during streamed response",
+						slog.String("component", "product_handler"),
+						slog.Int("products_written", i+1),
+						slog.String("error", err.Error()))
+					return
+				}
+			}
+		}
+		w.WriteString(`],"timestamp":"` + timestamp + `"}`)
+		w.Flush()
+	})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/models"
+	"github.com/valyala/fasthttp"
+)
+
+// catalogApp returns size products and an app serving them streamed on /streamed
+// and buffered on /buffered.
+func catalogApp(size int) ([]models.Product, *fiber.App) {
+	products := make([]models.Product, size)
+	for i := range products {
+		products[i] = models.Product{
+			Name:        fmt.Sprintf("Product %05d", i),
+			Description: "A product used to measure streamed responses",
+			Price:       models.MoneyFromFloat(float64(i%500) + 0.99),
+			Stock:       i % 100,
+			Category:    "Kitchenware",
+		}
+	}
+	h := &ProductHandler{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	app := fiber.New()
+	app.Get("/streamed", func(c *fiber.Ctx) error {
+		h.streamProducts(c, products, nil)
+		return nil
+	})
+	app.Get("/buffered", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"status": "success", "data": products})
+	})
+	return products, app
+}
+
+// serveCatalog runs one GET of path against app and writes the response body to w.
+func serveCatalog(tb testing.TB, app *fiber.App, path string, w io.Writer) {
+	tb.Helper()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI(path)
+
+	app.Handler()(ctx)
+	out := bufio.NewWriter(w)
+	if err := ctx.Response.BodyWriteTo(out); err != nil {
+		tb.Fatalf("write %s response: %v", path, err)
+	}
+	out.Flush()
+}
+
+func TestStreamProducts(t *testing.T) {
+	products, app := catalogApp(20000)
+
+	var body bytes.Buffer
+	serveCatalog(t, app, "/streamed", &body)
+	var envelope struct {
+		Status string           `json:"status"`
+		Data   []models.Product `json:"data"`
+	}
+	if err := json.Unmarshal(body.Bytes(), &envelope); err != nil {
+		t.Fatalf("streamed response is not valid JSON: %v", err)
+	}
+	if envelope.Status != "success" || len(envelope.Data) != len(products) || envelope.Data[len(products)-1] != products[len(products)-1] {
+		t.Fatalf("streamed envelope has status %q and %d products, want success and all %d", envelope.Status, len(envelope.Data), len(products))
+	}
+}
+
+// BenchmarkStreamProducts reports the allocations of streamed and buffered responses.
+// Streaming never holds the whole payload in memory; buffering builds it first in a
+// body buffer that is pooled across requests.
+func BenchmarkStreamProducts(b *testing.B) {
+	_, app := catalogApp(20000)
+	for _, path := range []string{"/streamed", "/buffered"} {
+		b.Run(path[1:], func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				serveCatalog(b, app, path, io.Discard)
+			}
+		})
+	}
+}
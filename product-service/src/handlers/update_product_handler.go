@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/debugutils"
+	commonMiddleware "github.com/narender/common/middleware"
+	"github.com/narender/common/models"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+	apirequests "github.com/narender/common/apirequests"
+	apiresponses "github.com/narender/common/apiresponses"
+)
+
+// UpdateProduct applies a sparse update to the product named in the path.
+// Only fields present in the body are changed.
+func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) (err error) {
+	ctx := c.UserContext()
+
+	// Product names contain spaces, so the path segment arrives escaped
+	productName, unescapeErr := url.PathUnescape(c.Params("name"))
+	if unescapeErr != nil {
+		err = apierrors.NewApplicationError(
+			apierrors.ErrCodeRequestValidation,
+			"Invalid product name in path",
+			unescapeErr)
+		return
+	}
+
+	newCtx, span := commontrace.StartSpan(ctx, "product_handler", "update_product",
+		attribute.String("product.name", productName),
+		attribute.String(commonMiddleware.AttrEndUserID, commonMiddleware.ActorFromContext(ctx)))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if err != nil {
+			telemetryErr = err
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	var req apirequests.UpdateProductRequest
+	if err = h.decodeRequest(ctx, c, "update_product", &req); err != nil {
+		return
+	}
+
+	update := models.ProductUpdate{
+		Description: req.Description,
+		Category:    req.Category,
+		Stock:       req.Stock,
+	}
+	if req.Price != nil {
+		price := models.MoneyFromFloat(*req.Price)
+		// Validation checked the float; a price under half a cent rounds to zero
+		if price.Cents() <= 0 {
+			err = apierrors.NewApplicationError(
+				apierrors.ErrCodeRequestValidation,
+				"Price must be at least 0.01",
+				nil).WithContext("price", *req.Price)
+			return
+		}
+		update.Price = &price
+	}
+	if update.IsEmpty() {
+		err = apierrors.NewApplicationError(
+			apierrors.ErrCodeRequestValidation,
+			"Request body must contain at least one of price, description, category, stock",
+			nil)
+		return
+	}
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		err = simAppErr
+		return
+	}
+
+	product, appErr := h.service.UpdateProduct(ctx, productName, update)
+	if appErr != nil {
+		err = appErr
+		return
+	}
+
+	h.logger.InfoContext(ctx, "Product update completed successfully",
+		slog.String("component", "product_handler"),
+		slog.String("product_name", productName),
+		slog.String("actor", commonMiddleware.ActorFromContext(ctx)),
+		slog.String("operation", "update_product"),
+		slog.String("status", "success"))
+
+	response := apiresponses.NewSuccessResponse(product)
+
+	err = c.Status(http.StatusOK).JSON(response)
+	return
+}
@@ -6,50 +6,42 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/narender/common/debugutils"
+	commonMiddleware "github.com/narender/common/middleware"
 	commontrace "github.com/narender/common/telemetry/trace"
 	"go.opentelemetry.io/otel/attribute"
 
-	apierrors "github.com/narender/common/apierrors"
 	apirequests "github.com/narender/common/apirequests"
 	apiresponses "github.com/narender/common/apiresponses"
-	"github.com/narender/common/validator"
-	"go.opentelemetry.io/otel/codes"
 )
 
 func (h *ProductHandler) UpdateProductStock(c *fiber.Ctx) (err error) {
 	ctx := c.UserContext()
 
-	h.logger.InfoContext(ctx, "Stock update request received",
+	h.logger.DebugContext(ctx, "Stock update request received",
 		slog.String("component", "product_handler"),
 		slog.String("operation", "update_product_stock"))
 
-	var req apirequests.UpdateStockRequest
-	if parseErr := c.BodyParser(&req); parseErr != nil {
-		h.logger.WarnContext(ctx, "Request rejected: invalid request format",
-			slog.String("component", "product_handler"),
-			slog.String("error", parseErr.Error()),
-			slog.String("operation", "update_product_stock"))
-
-		err = apierrors.NewApplicationError(
-			apierrors.ErrCodeRequestValidation,
-			"Invalid request body format",
-			parseErr)
-		return
-	}
-
-	if validatorErr := validator.ValidateRequest(&req); validatorErr != nil {
-		h.logger.WarnContext(ctx, "Request validation failed",
-			slog.String("component", "product_handler"),
-			slog.String("operation", "update_product_stock"),
-			slog.String("error", validatorErr.Error()),
-		)
+	newCtx, span := commontrace.StartSpan(ctx, "product_handler", "update_product_stock",
+		attribute.String(commonMiddleware.AttrEndUserID, commonMiddleware.ActorFromContext(ctx)))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if err != nil {
+			telemetryErr = err
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
 
-		err = validatorErr
+	var req apirequests.UpdateStockRequest
+	if err = h.decodeRequest(ctx, c, "update_product_stock", &req); err != nil {
 		return
 	}
 
 	productName := req.Name
 	newStock := req.Stock
+	span.SetAttributes(
+		attribute.String("product.name", productName),
+		attribute.Int("product.update_stock_to", newStock))
 
 	h.logger.DebugContext(ctx, "Processing stock update request",
 		slog.String("component", "product_handler"),
@@ -57,18 +49,6 @@ func (h *ProductHandler) UpdateProductStock(c *fiber.Ctx) (err error) {
 		slog.Int("new_stock", newStock),
 		slog.String("operation", "update_product_stock"))
 
-	newCtx, span := commontrace.StartSpan(c.UserContext(), "product_handler", "update_product_stock",
-		attribute.String("product.name", productName),
-		attribute.Int("product.update_stock_to", newStock))
-	ctx = newCtx
-	defer func() {
-		var telemetryErr error
-		if err != nil {
-			telemetryErr = err
-		}
-		commontrace.EndSpan(span, &telemetryErr, nil)
-	}()
-
 	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
 		err = simAppErr
 		return
@@ -80,12 +60,8 @@ func (h *ProductHandler) UpdateProductStock(c *fiber.Ctx) (err error) {
 		slog.Int("new_stock", newStock),
 		slog.String("operation", "update_product_stock"))
 
-	appErr := h.service.UpdateStock(ctx, productName, newStock)
+	appErr := h.service.UpdateStock(ctx, productName, newStock, req.ExpectedStock)
 	if appErr != nil {
-		if span != nil {
-			span.SetStatus(codes.Error, appErr.Error())
-		}
-
 		err = appErr
 		return
 	}
@@ -94,6 +70,7 @@ func (h *ProductHandler) UpdateProductStock(c *fiber.Ctx) (err error) {
 		slog.String("component", "product_handler"),
 		slog.String("product_name", productName),
 		slog.Int("new_stock", newStock),
+		slog.String("actor", commonMiddleware.ActorFromContext(ctx)),
 		slog.String("operation", "update_product_stock"),
 		slog.String("status", "success"))
 
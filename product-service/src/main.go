@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"runtime"
 
 	"github.com/gofiber/contrib/otelfiber/v2"
 	"github.com/gofiber/fiber/v2"
@@ -13,6 +14,9 @@ import (
 	"github.com/narender/common/globals"
 	// Import common packages
 	commonMiddleware "github.com/narender/common/middleware"
+	"github.com/narender/common/shutdown"
+	"github.com/narender/common/telemetry"
+	commonResource "github.com/narender/common/telemetry/resource"
 
 	// Import structured packages
 	"github.com/narender/product-service/src/handlers"
@@ -36,9 +40,43 @@ func main() {
 	handler := handlers.NewProductHandler(service)
 
 	// --- Service Information Logging ---
-	logger.Info("Starting product-service")
+	logger.Info("Starting product-service",
+		slog.String("version", globals.Cfg().SERVICE_VERSION),
+		slog.String("build_commit", commonResource.BuildCommit),
+		slog.String("build_date", commonResource.BuildDate),
+		slog.String("go_version", runtime.Version()))
 
-	// --- Fiber App Initialization with Error Handler ---
+	app := newApp(handler)
+	logger.Info("Routes registered")
+
+	// --- Server Startup ---
+	addr := fmt.Sprintf(":%s", globals.Cfg().PRODUCT_SERVICE_PORT)
+	logger.Info("Server starting to listen", slog.String("address", addr))
+
+	shutdownManager := shutdown.NewManager()
+	shutdownManager.Register("telemetry", telemetry.Shutdown)
+	shutdownManager.Register("session_summary", commonMiddleware.SessionSummary)
+	shutdownManager.Register("http_server", app.ShutdownWithContext)
+	if globals.Cfg().CONFIG_RELOAD_ON_SIGHUP {
+		shutdownManager.RegisterReload("config", globals.Reload)
+	}
+
+	go func() {
+		if err := app.Listen(addr); err != nil {
+			logger.Error("Server listener failed", slog.Any("error", err))
+			os.Exit(1)
+		}
+	}()
+
+	if err := shutdownManager.Wait(); err != nil {
+		logger.Error("Graceful shutdown completed with errors", slog.Any("error", err))
+		os.Exit(1)
+	}
+	logger.Info("Server stopped")
+}
+
+// newApp creates the Fiber app with its error handler, middleware chain and routes.
+func newApp(handler *handlers.ProductHandler) *fiber.App {
 	app := fiber.New(fiber.Config{
 		ErrorHandler: commonMiddleware.ErrorHandler(),
 	})
@@ -46,31 +84,53 @@ func main() {
 	// --- Middleware Configuration ---
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept",
+		AllowHeaders: "Origin, Content-Type, Accept, X-Actor, X-Deadline-Ms, X-Request-ID",
 	}))
-	app.Use(commonMiddleware.RecoverMiddleware()) // Custom panic recovery
-	app.Use(otelfiber.Middleware())               // otelfiber instrumentation
+	app.Use(otelfiber.Middleware()) // otelfiber instrumentation
+	app.Use(commonMiddleware.TraceResponseHeaderMiddleware())
+	app.Use(commonMiddleware.RequestLogFieldsMiddleware())
+	app.Use(commonMiddleware.LatencyBreakdownMiddleware())
+	app.Use(commonMiddleware.AccessLogMiddleware())
+	app.Use(commonMiddleware.RouteMetricsMiddleware())
+	// Recovery runs inside the request span and the logging and metrics middleware,
+	// so a recovered panic is recorded on a live span and reported as a 500 by them
+	app.Use(commonMiddleware.RecoverMiddleware())
+	app.Use(commonMiddleware.ActorMiddleware())
+	app.Use(commonMiddleware.DeadlineMiddleware())
 
 	// --- Route Definitions ---
 	setupRoutes(app, handler)
-	logger.Info("Routes registered")
-
-	// --- Server Startup ---
-	addr := fmt.Sprintf(":%s", globals.Cfg().PRODUCT_SERVICE_PORT)
-	logger.Info("Server starting to listen", slog.String("address", addr))
-
-	if err := app.Listen(addr); err != nil {
-		logger.Error("Server listener failed", slog.Any("error", err))
-		os.Exit(1)
-	}
+	return app
 }
 
 // setupRoutes function to keep main clean
 func setupRoutes(app *fiber.App, handler *handlers.ProductHandler) {
 	app.Get("/health", handler.HealthCheck)
+	app.Get("/ready", handler.Readiness)
+	app.Get("/health/ready", handler.Readiness)
 	app.Get("/products", handler.GetAllProducts)
 	app.Get("/products/category", handler.GetProductsByCategory)
+	app.Get("/products/search", handler.SearchProducts)
+	app.Get("/products/compare", handler.CompareProducts)
+	app.Get("/products/export", handler.ExportProducts)
 	app.Post("/products/details", handler.GetProductByName)
-	app.Patch("/products/stock", handler.UpdateProductStock)
-	app.Post("/products/buy", handler.BuyProduct)
+	app.Patch("/products/stock", commonMiddleware.MaintenanceGuard(), handler.UpdateProductStock)
+	app.Post("/products/buy", commonMiddleware.MaintenanceGuard(), handler.BuyProduct)
+	// Registered after the fixed /products/* routes so those take precedence
+	app.Patch("/products/:name", commonMiddleware.MaintenanceGuard(), handler.UpdateProduct)
+	app.Delete("/products/:name", commonMiddleware.MaintenanceGuard(), handler.DeleteProduct)
+	app.Delete("/products/:name/purge", commonMiddleware.MaintenanceGuard(), handler.PurgeProduct)
+
+	if globals.Cfg().DEBUG_ENDPOINTS_ENABLED {
+		app.Get("/debug/sampling", handler.GetSamplingConfig)
+		app.Get("/debug/telemetry-health", handler.GetTelemetryHealth)
+		app.Get("/debug/health-history", handler.GetHealthHistory)
+		app.Get("/debug/flags", handler.GetFeatureFlags)
+		app.Patch("/debug/flags", handler.UpdateFeatureFlags)
+		app.Post("/debug/metrics/reset", handler.ResetMetricAggregates)
+		// Never expose a panic trigger in production, even if debug endpoints are on
+		if globals.Cfg().ENVIRONMENT != "production" {
+			app.Get("/debug/panic", handler.TriggerPanic)
+		}
+	}
 }
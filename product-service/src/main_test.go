@@ -0,0 +1,2018 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/narender/common/apiresponses"
+	"github.com/narender/common/config"
+	"github.com/narender/common/globals"
+	commonMiddleware "github.com/narender/common/middleware"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry/metric"
+	"github.com/narender/common/telemetry/pipeline"
+	"github.com/narender/common/telemetry/telemetrytest"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"github.com/narender/product-service/src/handlers"
+	"github.com/narender/product-service/src/repositories"
+	"github.com/narender/product-service/src/services"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// harness records the telemetry of every test in this package; the metric
+// instruments bind to the first provider installed, so there is only one.
+var harness *telemetrytest.Harness
+
+func TestMain(m *testing.M) {
+	harness = telemetrytest.NewTestHarness()
+	code := m.Run()
+	harness.Teardown()
+	os.Exit(code)
+}
+
+const testCatalog = `{
+  "Blender Pro": {"name": "Blender Pro", "description": "High-speed blender for smoothies", "price": 74.99, "stock": 30, "category": "Kitchenware"},
+  "Coffee Mug": {"name": "Coffee Mug", "description": "Ceramic mug", "price": 9.5, "stock": 20, "category": "Kitchenware"},
+  "Reading Lamp": {"name": "Reading Lamp", "description": "Warm light for reading", "price": 19.99, "stock": 8, "category": "Furniture"}
+}`
+
+// newTestApp builds the product-service app as main does, backed by a temporary
+// copy of testCatalog, with debug endpoints enabled. overrides are applied to the
+// configuration last.
+func newTestApp(t *testing.T, overrides ...func(*config.Config)) *fiber.App {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := os.WriteFile(path, []byte(testCatalog), 0o644); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+	globals.InitForTest(t, append([]func(*config.Config){func(c *config.Config) {
+		c.PRODUCT_DATA_FILE_PATH = path
+		c.DEBUG_ENDPOINTS_ENABLED = true
+		c.ENVIRONMENT = "test"
+	}}, overrides...)...)
+	harness.Reset()
+
+	repo := repositories.NewProductRepository()
+	return newApp(handlers.NewProductHandler(services.NewProductService(repo)))
+}
+
+// serverSpan returns the single server span recorded, failing the test otherwise.
+func serverSpan(t *testing.T, spans tracetest.SpanStubs) tracetest.SpanStub {
+	t.Helper()
+	var found []tracetest.SpanStub
+	for _, span := range spans {
+		if span.SpanKind == trace.SpanKindServer {
+			found = append(found, span)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("recorded %d server spans, want 1", len(found))
+	}
+	return found[0]
+}
+
+// doRequest sends a request with an optional JSON body through app.
+func doRequest(t *testing.T, app *fiber.App, method, target, body string) *http.Response {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	if body != "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, target, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// decodeData decodes the data of a success envelope into v, rejecting fields v
+// does not declare.
+func decodeData(t *testing.T, resp *http.Response, v any) {
+	t.Helper()
+	var envelope struct {
+		Status string          `json:"status"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		t.Fatalf("decode response envelope: %v", err)
+	}
+	if envelope.Status != "success" {
+		t.Fatalf("response status = %q, want success: %s", envelope.Status, envelope.Data)
+	}
+	dec := json.NewDecoder(bytes.NewReader(envelope.Data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		t.Fatalf("decode response data into %T: %v: %s", v, err, envelope.Data)
+	}
+}
+
+// decodeError decodes an error envelope.
+func decodeError(t *testing.T, resp *http.Response) apiresponses.ErrorResponse {
+	t.Helper()
+	var body apiresponses.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	return body
+}
+
+func TestProductWireFormatMatchesSharedModel(t *testing.T) {
+	app := newTestApp(t)
+
+	resp := doRequest(t, app, http.MethodPost, "/products/details", `{"name": "Coffee Mug"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var product models.Product
+	decodeData(t, resp, &product)
+
+	want := models.Product{Name: "Coffee Mug", Description: "Ceramic mug", Price: models.MoneyFromFloat(9.5), Stock: 20, Category: "Kitchenware"}
+	if product != want {
+		t.Errorf("decoded product = %+v, want %+v", product, want)
+	}
+
+	resp = doRequest(t, app, http.MethodGet, "/products", "")
+	var products []models.Product
+	decodeData(t, resp, &products)
+	if len(products) != 3 {
+		t.Errorf("decoded %d products from /products, want 3", len(products))
+	}
+}
+
+func TestDebugPanicIsRecoveredInsideTheRequestSpan(t *testing.T) {
+	app := newTestApp(t)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/debug/panic", nil), -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusInternalServerError)
+	}
+	var body apiresponses.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if body.Error.Code != apierrors.ErrCodeSystemPanic {
+		t.Errorf("error code = %q, want %q", body.Error.Code, apierrors.ErrCodeSystemPanic)
+	}
+
+	span := serverSpan(t, harness.Spans())
+	if span.Status.Code != codes.Error {
+		t.Errorf("server span status = %v, want Error", span.Status.Code)
+	}
+	recordedPanic := false
+	for _, event := range span.Events {
+		if event.Name == "exception" {
+			recordedPanic = true
+		}
+	}
+	if !recordedPanic {
+		t.Errorf("server span has no exception event for the panic")
+	}
+	// Set by RouteMetricsMiddleware, which a panic used to skip
+	routeRecorded := false
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == metric.AttrHTTPRoute && attr.Value.AsString() == "/debug/panic" {
+			routeRecorded = true
+		}
+	}
+	if !routeRecorded {
+		t.Errorf("server span is missing %s=/debug/panic", metric.AttrHTTPRoute)
+	}
+}
+
+func TestDebugPanicNotRegisteredInProduction(t *testing.T) {
+	globals.InitForTest(t, func(c *config.Config) {
+		c.DEBUG_ENDPOINTS_ENABLED = true
+		c.ENVIRONMENT = "production"
+	})
+	app := newApp(handlers.NewProductHandler(nil))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/debug/panic", nil), -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+// TestBuyFlowSpanTree locks in the span tree of a successful purchase. The golden
+// file is written on the first run; rerun with UPDATE_GOLDEN=1 after an intended
+// change to the buy flow's telemetry.
+func TestBuyFlowSpanTree(t *testing.T) {
+	// Slow-operation events depend on timing, not on the flow
+	app := newTestApp(t, func(c *config.Config) { c.DB_SLOW_THRESHOLD_MS = 0 })
+
+	req := httptest.NewRequest(http.MethodPost, "/products/buy", strings.NewReader(`{"name": "Coffee Mug", "quantity": 2}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	spans := harness.Spans()
+	// Parentage is checked here as well so the test is meaningful before a golden
+	// file exists
+	tree := telemetrytest.SpanTree(spans)
+	if len(tree) != 1 {
+		t.Fatalf("recorded %d root spans, want the server span only", len(tree))
+	}
+	handler := findShape(tree[0], "product_handler :: buy_product")
+	if handler == nil {
+		t.Fatalf("no product_handler :: buy_product span under %s", tree[0].Name)
+	}
+	service := findShape(*handler, "product_service :: buy_product")
+	if service == nil {
+		t.Fatalf("no product_service :: buy_product span under the handler span")
+	}
+	if findShape(*service, "product_repository :: update_stock") == nil {
+		t.Errorf("no product_repository :: update_stock span under the service span")
+	}
+
+	telemetrytest.AssertGoldenSpans(t, filepath.Join("testdata", "buy_flow.golden.json"), spans)
+}
+
+// findShape returns the first span named name below root, depth first.
+func findShape(root telemetrytest.SpanShape, name string) *telemetrytest.SpanShape {
+	for i := range root.Children {
+		if root.Children[i].Name == name {
+			return &root.Children[i]
+		}
+		if found := findShape(root.Children[i], name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// spanAttr returns the value of key on span and whether it is set.
+func spanAttr(span tracetest.SpanStub, key string) (string, bool) {
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value.Emit(), true
+		}
+	}
+	return "", false
+}
+
+// onlySpan returns the single span named name, failing the test otherwise.
+func onlySpan(t *testing.T, name string) tracetest.SpanStub {
+	t.Helper()
+	spans := harness.SpansNamed(name)
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d %q spans, want 1", len(spans), name)
+	}
+	return spans[0]
+}
+
+func TestStockUpdateCarriesItsCause(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		body      string
+		wantCause map[string]string
+	}{
+		{
+			name:   "purchase",
+			method: http.MethodPost, target: "/products/buy", body: `{"name": "Coffee Mug", "quantity": 2}`,
+			wantCause: map[string]string{"caused_by": "buy_product", "purchase.quantity": "2", "stock.before": "20", "stock.after": "18"},
+		},
+		{
+			name:   "direct stock update",
+			method: http.MethodPatch, target: "/products/stock", body: `{"name": "Coffee Mug", "stock": 25}`,
+			wantCause: map[string]string{"caused_by": "", "purchase.quantity": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			resp := doRequest(t, app, tt.method, tt.target, tt.body)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			span := onlySpan(t, "product_repository :: update_stock")
+			for key, want := range tt.wantCause {
+				got, _ := spanAttr(span, key)
+				if got != want {
+					t.Errorf("update_stock %s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExportProductsIsValidNDJSON(t *testing.T) {
+	app := newTestApp(t)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/products/export", nil), -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := resp.Header.Get(fiber.HeaderContentType); got != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want application/x-ndjson", got)
+	}
+	if got := resp.Header.Get(handlers.ExportVersionHeader); got != strconv.Itoa(handlers.ExportFormatVersion) {
+		t.Errorf("%s = %q, want %d", handlers.ExportVersionHeader, got, handlers.ExportFormatVersion)
+	}
+
+	want := map[string]bool{"Blender Pro": true, "Coffee Mug": true, "Reading Lamp": true}
+	scanner := bufio.NewScanner(resp.Body)
+	lines := 0
+	for scanner.Scan() {
+		lines++
+		var product models.Product
+		if err := json.Unmarshal(scanner.Bytes(), &product); err != nil {
+			t.Fatalf("line %d is not a JSON product: %v: %s", lines, err, scanner.Text())
+		}
+		if !want[product.Name] {
+			t.Errorf("line %d: unexpected or repeated product %q", lines, product.Name)
+		}
+		delete(want, product.Name)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if len(want) != 0 {
+		t.Errorf("export is missing %v", want)
+	}
+
+	spans := harness.SpansNamed("product_handler :: export_products")
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d export spans, want 1", len(spans))
+	}
+	counted := false
+	for _, attr := range spans[0].Attributes {
+		if attr.Key == "export.count" {
+			counted = true
+			if attr.Value.AsInt64() != int64(lines) {
+				t.Errorf("export.count = %d, want %d", attr.Value.AsInt64(), lines)
+			}
+		}
+	}
+	if !counted {
+		t.Errorf("export span is missing export.count")
+	}
+}
+
+func TestSamplingEndpointReflectsTheSampler(t *testing.T) {
+	cfg := globals.InitForTest(t, func(c *config.Config) {
+		c.OTEL_TRACE_SAMPLE_RATIO = 0.25
+		c.OTEL_TRACE_SAMPLE_WARMUP = 2 * time.Minute
+	})
+	commontrace.NewSampler(cfg)
+	app := newTestApp(t)
+
+	tests := []struct {
+		name       string
+		change     func()
+		wantRatio  float64
+		wantWarmup string
+	}{
+		{name: "configured sampler", change: func() {}, wantRatio: 0.25, wantWarmup: "2m0s"},
+		{name: "ratio changed at runtime", change: func() {
+			if err := commontrace.SetSampleRatio(0.05); err != nil {
+				t.Fatalf("SetSampleRatio: %v", err)
+			}
+		}, wantRatio: 0.05, wantWarmup: "0s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change()
+
+			var got commontrace.SamplingConfig
+			decodeData(t, doRequest(t, app, http.MethodGet, "/debug/sampling", ""), &got)
+
+			if got.Ratio != tt.wantRatio || got.Warmup != tt.wantWarmup || !got.ParentBased {
+				t.Errorf("GET /debug/sampling = %+v, want ratio %v, warmup %s, parent based", got, tt.wantRatio, tt.wantWarmup)
+			}
+			if want := commontrace.CurrentSamplingConfig().Description; got.Description != want {
+				t.Errorf("description = %q, want %q", got.Description, want)
+			}
+		})
+	}
+}
+
+func TestSamplingEndpointRequiresDebugEndpoints(t *testing.T) {
+	app := newTestApp(t, func(c *config.Config) { c.DEBUG_ENDPOINTS_ENABLED = false })
+
+	if resp := doRequest(t, app, http.MethodGet, "/debug/sampling", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestBusinessRejectionsDoNotMarkSpansAsErrored(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		corrupt     bool
+		wantStatus  int
+		wantErrored bool
+		wantOutcome string
+	}{
+		{name: "insufficient stock", body: `{"name": "Reading Lamp", "quantity": 50}`,
+			wantStatus: http.StatusBadRequest, wantOutcome: apierrors.ErrCodeInsufficientStock},
+		{name: "unknown product", body: `{"name": "Teapot", "quantity": 1}`,
+			wantStatus: http.StatusNotFound, wantOutcome: apierrors.ErrCodeProductNotFound},
+		{name: "database failure", body: `{"name": "Coffee Mug", "quantity": 1}`, corrupt: true,
+			wantStatus: http.StatusInternalServerError, wantErrored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			if tt.corrupt {
+				if err := os.WriteFile(globals.Cfg().PRODUCT_DATA_FILE_PATH, []byte("{not json"), 0o644); err != nil {
+					t.Fatalf("corrupt catalog: %v", err)
+				}
+			}
+
+			resp := doRequest(t, app, http.MethodPost, "/products/buy", tt.body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			span := onlySpan(t, "product_service :: buy_product")
+			if errored := span.Status.Code == codes.Error; errored != tt.wantErrored {
+				t.Errorf("service span status = %v, want errored %v", span.Status.Code, tt.wantErrored)
+			}
+			if outcome, _ := spanAttr(span, commontrace.AttrBusinessOutcome); outcome != tt.wantOutcome {
+				t.Errorf("%s = %q, want %q", commontrace.AttrBusinessOutcome, outcome, tt.wantOutcome)
+			}
+		})
+	}
+}
+
+// newTestAppWithLogs is newTestApp with the service's log output captured.
+func newTestAppWithLogs(t *testing.T, overrides ...func(*config.Config)) (*fiber.App, *bytes.Buffer) {
+	t.Helper()
+	newTestApp(t, overrides...)
+	logs := globals.CaptureLogsForTest(t)
+	// Components take the logger when built, so build the app again around it
+	repo := repositories.NewProductRepository()
+	return newApp(handlers.NewProductHandler(services.NewProductService(repo))), logs
+}
+
+// logRecord returns the first JSON log record with message msg logged by component.
+func logRecord(t *testing.T, logs *bytes.Buffer, component, msg string) map[string]any {
+	t.Helper()
+	scanner := bufio.NewScanner(bytes.NewReader(logs.Bytes()))
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil && record["msg"] == msg && record["component"] == component {
+			return record
+		}
+	}
+	t.Fatalf("no %q log record from %s in:\n%s", msg, component, logs.String())
+	return nil
+}
+
+func TestActorFlowsToSpanAndAuditLog(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		body      string
+		span      string
+		auditMsg  string
+		actor     string
+		wantActor string
+	}{
+		{name: "purchase", method: http.MethodPost, target: "/products/buy", body: `{"name": "Coffee Mug", "quantity": 1}`,
+			span: "product_handler :: buy_product", auditMsg: "Purchase completed successfully", actor: "alice", wantActor: "alice"},
+		{name: "stock update", method: http.MethodPatch, target: "/products/stock", body: `{"name": "Coffee Mug", "stock": 40}`,
+			span: "product_handler :: update_product_stock", auditMsg: "Stock update completed successfully", actor: "ops-bot", wantActor: "ops-bot"},
+		{name: "anonymous purchase", method: http.MethodPost, target: "/products/buy", body: `{"name": "Coffee Mug", "quantity": 1}`,
+			span: "product_handler :: buy_product", auditMsg: "Purchase completed successfully", wantActor: "anonymous"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, logs := newTestAppWithLogs(t)
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			if tt.actor != "" {
+				req.Header.Set(commonMiddleware.ActorHeader, tt.actor)
+			}
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			if got, _ := spanAttr(onlySpan(t, tt.span), commonMiddleware.AttrEndUserID); got != tt.wantActor {
+				t.Errorf("span %s = %q, want %q", commonMiddleware.AttrEndUserID, got, tt.wantActor)
+			}
+			if got := logRecord(t, logs, "product_handler", tt.auditMsg)["actor"]; got != tt.wantActor {
+				t.Errorf("audit record actor = %v, want %q", got, tt.wantActor)
+			}
+		})
+	}
+}
+
+func TestBuyRejectsInvalidQuantitiesWithoutChangingStock(t *testing.T) {
+	tests := []struct {
+		name      string
+		quantity  int
+		wantCode  string
+		wantEvent bool
+	}{
+		{name: "zero", quantity: 0, wantCode: apierrors.ErrCodeRequestValidation},
+		{name: "negative", quantity: -3, wantCode: apierrors.ErrCodeRequestValidation},
+		{name: "over the limit", quantity: 11, wantCode: apierrors.ErrCodeOrderLimitExceeded, wantEvent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, func(c *config.Config) { c.MAX_PURCHASE_QUANTITY = 10 })
+
+			resp := doRequest(t, app, http.MethodPost, "/products/buy", fmt.Sprintf(`{"name": "Reading Lamp", "quantity": %d}`, tt.quantity))
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			}
+			if got := decodeError(t, resp).Error.Code; got != tt.wantCode {
+				t.Errorf("error code = %q, want %q", got, tt.wantCode)
+			}
+			if tt.wantEvent {
+				span := onlySpan(t, "product_service :: buy_product")
+				if !hasSpanEvent(span, "quantity.invalid") {
+					t.Errorf("no quantity.invalid event on the service span")
+				}
+			}
+
+			var product models.Product
+			decodeData(t, doRequest(t, app, http.MethodPost, "/products/details", `{"name": "Reading Lamp"}`), &product)
+			if product.Stock != 8 {
+				t.Errorf("stock = %d after a rejected purchase, want 8", product.Stock)
+			}
+		})
+	}
+}
+
+func hasSpanEvent(span tracetest.SpanStub, name string) bool {
+	for _, event := range span.Events {
+		if event.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestListEndpointsSortProducts(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		want      string
+		wantField string
+		wantOrder string
+	}{
+		{name: "default", target: "/products", want: "Blender Pro,Coffee Mug,Reading Lamp", wantField: "name", wantOrder: "asc"},
+		{name: "by price descending", target: "/products?sortBy=price&order=desc", want: "Blender Pro,Reading Lamp,Coffee Mug", wantField: "price", wantOrder: "desc"},
+		{name: "by stock", target: "/products?sortBy=stock", want: "Reading Lamp,Coffee Mug,Blender Pro", wantField: "stock", wantOrder: "asc"},
+		{name: "category by price", target: "/products/category?category=Kitchenware&sortBy=price", want: "Coffee Mug,Blender Pro", wantField: "price", wantOrder: "asc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+
+			// Map iteration order differs between calls, so repeat to catch instability
+			for i := 0; i < 5; i++ {
+				var products []models.Product
+				decodeData(t, doRequest(t, app, http.MethodGet, tt.target, ""), &products)
+				names := make([]string, len(products))
+				for j, p := range products {
+					names[j] = p.Name
+				}
+				if got := strings.Join(names, ","); got != tt.want {
+					t.Fatalf("call %d: GET %s = %s, want %s", i+1, tt.target, got, tt.want)
+				}
+			}
+
+			var services int
+			for _, span := range harness.Spans() {
+				if !strings.HasPrefix(span.Name, "product_service :: ") {
+					continue
+				}
+				services++
+				field, _ := spanAttr(span, "sort.field")
+				order, _ := spanAttr(span, "sort.order")
+				if field != tt.wantField || order != tt.wantOrder {
+					t.Errorf("%s sort = %s %s, want %s %s", span.Name, field, order, tt.wantField, tt.wantOrder)
+				}
+			}
+			if services == 0 {
+				t.Errorf("no product_service spans recorded")
+			}
+		})
+	}
+}
+
+func TestListEndpointsRejectUnknownSortField(t *testing.T) {
+	app := newTestApp(t)
+
+	resp := doRequest(t, app, http.MethodGet, "/products?sortBy=rating", "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+	if got := decodeError(t, resp).Error.Code; got != apierrors.ErrCodeRequestValidation {
+		t.Errorf("error code = %q, want %q", got, apierrors.ErrCodeRequestValidation)
+	}
+}
+
+func TestRepositorySpansRecordScannedAndReturnedCounts(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		body         string
+		span         string
+		wantScanned  string
+		wantReturned string
+	}{
+		{name: "category subset", method: http.MethodGet, target: "/products/category?category=Kitchenware",
+			span: "product_repository :: get_by_category", wantScanned: "3", wantReturned: "2"},
+		{name: "search", method: http.MethodGet, target: "/products/search?q=lamp",
+			span: "product_repository :: search", wantScanned: "3", wantReturned: "1"},
+		{name: "lookup by name", method: http.MethodPost, target: "/products/details", body: `{"name": "Coffee Mug"}`,
+			span: "product_repository :: get_by_name", wantScanned: "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			if resp := doRequest(t, app, tt.method, tt.target, tt.body); resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			span := onlySpan(t, tt.span)
+			if got, _ := spanAttr(span, "products.scanned.count"); got != tt.wantScanned {
+				t.Errorf("products.scanned.count = %q, want %q", got, tt.wantScanned)
+			}
+			if got, _ := spanAttr(span, "products.returned.count"); got != tt.wantReturned {
+				t.Errorf("products.returned.count = %q, want %q", got, tt.wantReturned)
+			}
+		})
+	}
+}
+
+func TestMaintenanceModeBlocksWritesOnly(t *testing.T) {
+	app := newTestApp(t, func(c *config.Config) { c.MaintenanceRetryAfterSec = 90 })
+	// Operators switch maintenance on at runtime through the flags endpoint
+	if resp := doRequest(t, app, http.MethodPatch, "/debug/flags", `{"maintenance_mode": true}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("enable maintenance mode: status = %d", resp.StatusCode)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		body        string
+		route       string
+		wantBlocked bool
+	}{
+		{name: "buy", method: http.MethodPost, target: "/products/buy", body: `{"name": "Coffee Mug", "quantity": 1}`, route: "/products/buy", wantBlocked: true},
+		{name: "stock update", method: http.MethodPatch, target: "/products/stock", body: `{"name": "Coffee Mug", "stock": 1}`, route: "/products/stock", wantBlocked: true},
+		{name: "product update", method: http.MethodPatch, target: "/products/Coffee%20Mug", body: `{"price": 1}`, route: "/products/:name", wantBlocked: true},
+		{name: "delete", method: http.MethodDelete, target: "/products/Coffee%20Mug", route: "/products/:name", wantBlocked: true},
+		{name: "list", method: http.MethodGet, target: "/products"},
+		{name: "details", method: http.MethodPost, target: "/products/details", body: `{"name": "Coffee Mug"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			harness.Reset()
+			labels := []attribute.KeyValue{
+				attribute.String(metric.AttrHTTPRoute, tt.route),
+				attribute.String(metric.AttrHTTPMethod, tt.method),
+			}
+			before, _ := harness.MetricValueWith(metric.MaintenanceBlockedMetric, labels...)
+
+			resp := doRequest(t, app, tt.method, tt.target, tt.body)
+
+			if !tt.wantBlocked {
+				if resp.StatusCode != http.StatusOK {
+					t.Errorf("status = %d, want reads to keep serving", resp.StatusCode)
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusServiceUnavailable {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+			}
+			if got := resp.Header.Get(fiber.HeaderRetryAfter); got != "90" {
+				t.Errorf("Retry-After = %q, want 90", got)
+			}
+			if got := decodeError(t, resp).Error.Code; got != apierrors.ErrCodeServiceUnavailable {
+				t.Errorf("error code = %q, want %q", got, apierrors.ErrCodeServiceUnavailable)
+			}
+			if got, _ := spanAttr(serverSpan(t, harness.Spans()), commonMiddleware.AttrMaintenanceMode); got != "true" {
+				t.Errorf("span %s = %q, want true", commonMiddleware.AttrMaintenanceMode, got)
+			}
+			if after, _ := harness.MetricValueWith(metric.MaintenanceBlockedMetric, labels...); after-before != 1 {
+				t.Errorf("%s rose by %v, want 1", metric.MaintenanceBlockedMetric, after-before)
+			}
+		})
+	}
+
+	// Later requests reuse Fiber's request buffer; each blocked series must keep its own method
+	for _, tt := range tests {
+		if !tt.wantBlocked {
+			continue
+		}
+		if count, _ := harness.MetricValueWith(metric.MaintenanceBlockedMetric,
+			attribute.String(metric.AttrHTTPRoute, tt.route),
+			attribute.String(metric.AttrHTTPMethod, tt.method)); count < 1 {
+			t.Errorf("%s: no %s series for %s %s after later requests", tt.name, metric.MaintenanceBlockedMetric, tt.method, tt.route)
+		}
+	}
+
+	var product models.Product
+	decodeData(t, doRequest(t, app, http.MethodPost, "/products/details", `{"name": "Coffee Mug"}`), &product)
+	if product.Stock != 20 || product.Deleted {
+		t.Errorf("Coffee Mug = stock %d, deleted %v after blocked writes, want it untouched", product.Stock, product.Deleted)
+	}
+}
+
+func TestBuyFlowSpanKinds(t *testing.T) {
+	app := newTestApp(t)
+	resp := doRequest(t, app, http.MethodPost, "/products/buy", `{"name": "Coffee Mug", "quantity": 1}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	tests := []struct {
+		span string
+		want trace.SpanKind
+	}{
+		{span: "product_handler :: buy_product", want: trace.SpanKindServer},
+		{span: "product_service :: buy_product", want: trace.SpanKindInternal},
+		{span: "product_repository :: update_stock", want: trace.SpanKindInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.span, func(t *testing.T) {
+			if got := onlySpan(t, tt.span).SpanKind; got != tt.want {
+				t.Errorf("span kind = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUncategorizedProductsAreCountedOnTheRepositorySpan(t *testing.T) {
+	app := newTestApp(t)
+	catalog := strings.Replace(testCatalog, `"category": "Furniture"`, `"category": ""`, 1)
+	if err := os.WriteFile(globals.Cfg().PRODUCT_DATA_FILE_PATH, []byte(catalog), 0o644); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+
+	resp := doRequest(t, app, http.MethodGet, "/products/category?category=uncategorized", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var products []models.Product
+	decodeData(t, resp, &products)
+	if len(products) != 1 || products[0].Name != "Reading Lamp" || products[0].Category != "uncategorized" {
+		t.Errorf("products = %+v, want Reading Lamp under uncategorized", products)
+	}
+
+	span := onlySpan(t, "product_repository :: get_by_category")
+	if got, _ := spanAttr(span, "products.normalized.count"); got != "1" {
+		t.Errorf("products.normalized.count = %q, want %q", got, "1")
+	}
+}
+
+func TestEmptyCatalogIsReported(t *testing.T) {
+	tests := []struct {
+		name      string
+		catalog   *string // nil removes the data file
+		wantSize  float64
+		wantEvent bool
+	}{
+		{name: "populated", catalog: ptr(testCatalog), wantSize: 3},
+		{name: "empty object", catalog: ptr(`{}`), wantSize: 0, wantEvent: true},
+		{name: "missing data file", wantSize: 0, wantEvent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			path := globals.Cfg().PRODUCT_DATA_FILE_PATH
+			if tt.catalog == nil {
+				if err := os.Remove(path); err != nil {
+					t.Fatalf("remove data file: %v", err)
+				}
+			} else if err := os.WriteFile(path, []byte(*tt.catalog), 0o644); err != nil {
+				t.Fatalf("write catalog: %v", err)
+			}
+
+			resp := doRequest(t, app, http.MethodGet, "/products", "")
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			if got, found := harness.MetricValue(metric.CatalogSizeMetric); !found || got != tt.wantSize {
+				t.Errorf("%s = %v (found %v), want %v", metric.CatalogSizeMetric, got, found, tt.wantSize)
+			}
+			if got := hasSpanEvent(onlySpan(t, "product_repository :: get_all"), "catalog.empty"); got != tt.wantEvent {
+				t.Errorf("catalog.empty event recorded = %v, want %v", got, tt.wantEvent)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestPartialProductUpdates(t *testing.T) {
+	original := models.Product{Name: "Coffee Mug", Description: "Ceramic mug", Price: models.MoneyFromFloat(9.5), Stock: 20, Category: "Kitchenware"}
+
+	tests := []struct {
+		name        string
+		body        string
+		want        func(p *models.Product)
+		wantChanged string
+	}{
+		{name: "price only", body: `{"price": 12.25}`,
+			want: func(p *models.Product) { p.Price = models.MoneyFromFloat(12.25) }, wantChanged: `["price"]`},
+		{name: "description and stock", body: `{"description": "Large ceramic mug", "stock": 35}`,
+			want: func(p *models.Product) { p.Description = "Large ceramic mug"; p.Stock = 35 }, wantChanged: `["description","stock"]`},
+		{name: "every field", body: `{"price": 11, "description": "Mug", "category": "Tableware", "stock": 5}`,
+			want: func(p *models.Product) {
+				p.Price = models.MoneyFromFloat(11)
+				p.Description = "Mug"
+				p.Category = "Tableware"
+				p.Stock = 5
+			}, wantChanged: `["price","description","category","stock"]`},
+		{name: "unchanged value", body: `{"stock": 20}`, want: func(p *models.Product) {}, wantChanged: "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			want := original
+			tt.want(&want)
+
+			resp := doRequest(t, app, http.MethodPatch, "/products/Coffee%20Mug", tt.body)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %+v", resp.StatusCode, http.StatusOK, decodeError(t, resp))
+			}
+			var updated models.Product
+			decodeData(t, resp, &updated)
+			if updated != want {
+				t.Errorf("response = %+v, want %+v", updated, want)
+			}
+			if got, _ := spanAttr(onlySpan(t, "product_repository :: update_product"), "product.fields_changed"); got != tt.wantChanged {
+				t.Errorf("product.fields_changed = %s, want %s", got, tt.wantChanged)
+			}
+
+			// Omitted fields must be untouched in the stored product too
+			var stored models.Product
+			decodeData(t, doRequest(t, app, http.MethodPost, "/products/details", `{"name": "Coffee Mug"}`), &stored)
+			if stored != want {
+				t.Errorf("stored product = %+v, want %+v", stored, want)
+			}
+			if stock, found := harness.MetricValueWith(metric.ProductStockCountMetric,
+				attribute.String(metric.AttrProductName, "Coffee Mug"),
+				attribute.String(metric.AttrProductCategory, want.Category)); tt.wantChanged != "[]" && (!found || stock != float64(want.Stock)) {
+				t.Errorf("%s{Coffee Mug, %s} = %v (found %v), want %d", metric.ProductStockCountMetric, want.Category, stock, found, want.Stock)
+			}
+			if want.Category != original.Category {
+				if _, found := harness.MetricValueWith(metric.ProductStockCountMetric,
+					attribute.String(metric.AttrProductName, "Coffee Mug"),
+					attribute.String(metric.AttrProductCategory, original.Category)); found {
+					t.Errorf("%s still reports Coffee Mug under %s", metric.ProductStockCountMetric, original.Category)
+				}
+			}
+		})
+	}
+}
+
+func TestPartialProductUpdatesAreValidated(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		body     string
+		wantCode int
+		wantErr  string
+	}{
+		{name: "empty update", target: "/products/Coffee%20Mug", body: `{}`, wantCode: http.StatusBadRequest, wantErr: apierrors.ErrCodeRequestValidation},
+		{name: "non-positive price", target: "/products/Coffee%20Mug", body: `{"price": 0}`, wantCode: http.StatusBadRequest, wantErr: apierrors.ErrCodeRequestValidation},
+		{name: "price rounding to zero cents", target: "/products/Coffee%20Mug", body: `{"price": 0.004}`, wantCode: http.StatusBadRequest, wantErr: apierrors.ErrCodeRequestValidation},
+		{name: "negative stock", target: "/products/Coffee%20Mug", body: `{"stock": -3}`, wantCode: http.StatusBadRequest, wantErr: apierrors.ErrCodeRequestValidation},
+		{name: "blank category", target: "/products/Coffee%20Mug", body: `{"category": "   "}`, wantCode: http.StatusBadRequest, wantErr: apierrors.ErrCodeRequestValidation},
+		{name: "unknown product", target: "/products/Teapot", body: `{"price": 5}`, wantCode: http.StatusNotFound, wantErr: apierrors.ErrCodeProductNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			before, err := os.ReadFile(globals.Cfg().PRODUCT_DATA_FILE_PATH)
+			if err != nil {
+				t.Fatalf("read data file: %v", err)
+			}
+
+			resp := doRequest(t, app, http.MethodPatch, tt.target, tt.body)
+			if resp.StatusCode != tt.wantCode {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+			if got := decodeError(t, resp).Error.Code; got != tt.wantErr {
+				t.Errorf("error code = %s, want %s", got, tt.wantErr)
+			}
+			if after, _ := os.ReadFile(globals.Cfg().PRODUCT_DATA_FILE_PATH); string(after) != string(before) {
+				t.Errorf("rejected update changed the data file")
+			}
+		})
+	}
+}
+
+func TestCatalogReadReportsPriceBands(t *testing.T) {
+	app := newTestApp(t)
+	if resp := doRequest(t, app, http.MethodGet, "/products", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	// testCatalog prices: 9.5, 19.99 and 74.99
+	for band, want := range map[string]float64{"0-10": 1, "10-50": 1, "50+": 1} {
+		got, found := harness.MetricValueWith(metric.ProductsByPriceBandMetric, attribute.String(metric.AttrPriceBand, band))
+		if !found || got != want {
+			t.Errorf("%s{%s} = %v (found %v), want %v", metric.ProductsByPriceBandMetric, band, got, found, want)
+		}
+	}
+}
+
+func TestBuyRevenueIsExactInCents(t *testing.T) {
+	app := newTestApp(t)
+	before := metric.RevenueTotalCents()
+
+	// 3 × 74.99 is 224.96999999999997 in float64
+	resp := doRequest(t, app, http.MethodPost, "/products/buy", `{"name": "Blender Pro", "quantity": 3}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := metric.RevenueTotalCents() - before; got != 22497 {
+		t.Errorf("revenue rose by %d cents, want 22497", got)
+	}
+
+	var stored models.Product
+	decodeData(t, doRequest(t, app, http.MethodPost, "/products/details", `{"name": "Blender Pro"}`), &stored)
+	if stored.Price != models.MoneyFromFloat(74.99) {
+		t.Errorf("stored price = %s after a write, want 74.99", stored.Price)
+	}
+}
+
+func TestRequestDecodeIsTraced(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		body        string
+		handler     string
+		wantOutcome string
+	}{
+		{name: "buy with malformed body", method: http.MethodPost, target: "/products/buy", body: `{"name": "Coffee Mug",`,
+			handler: "product_handler :: buy_product", wantOutcome: "malformed"},
+		{name: "buy with invalid body", method: http.MethodPost, target: "/products/buy", body: `{"name": "", "quantity": 1}`,
+			handler: "product_handler :: buy_product", wantOutcome: "invalid"},
+		{name: "buy with valid body", method: http.MethodPost, target: "/products/buy", body: `{"name": "Coffee Mug", "quantity": 1}`,
+			handler: "product_handler :: buy_product", wantOutcome: "valid"},
+		{name: "stock update with malformed body", method: http.MethodPatch, target: "/products/stock", body: `not json`,
+			handler: "product_handler :: update_product_stock", wantOutcome: "malformed"},
+		{name: "product update with malformed body", method: http.MethodPatch, target: "/products/Coffee%20Mug", body: `{"price": "cheap"`,
+			handler: "product_handler :: update_product", wantOutcome: "malformed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			resp := doRequest(t, app, tt.method, tt.target, tt.body)
+			resp.Body.Close()
+			if failed := resp.StatusCode != http.StatusOK; failed != (tt.wantOutcome != "valid") {
+				t.Fatalf("status = %d for a %s body", resp.StatusCode, tt.wantOutcome)
+			}
+
+			decode := onlySpan(t, "request :: decode")
+			handler := onlySpan(t, tt.handler)
+			if decode.Parent.SpanID() != handler.SpanContext.SpanID() {
+				t.Errorf("decode span is not a child of the %s span", tt.handler)
+			}
+			if got, _ := spanAttr(decode, "request.validation.outcome"); got != tt.wantOutcome {
+				t.Errorf("request.validation.outcome = %q, want %q", got, tt.wantOutcome)
+			}
+			if got, _ := spanAttr(decode, "request.content_length"); got != fmt.Sprint(len(tt.body)) {
+				t.Errorf("request.content_length = %s, want %d", got, len(tt.body))
+			}
+			if wantError := tt.wantOutcome != "valid"; (decode.Status.Code == codes.Error) != wantError || hasSpanEvent(decode, "exception") != wantError {
+				t.Errorf("decode span status = %v, exception recorded = %v; want an error only for a %s body",
+					decode.Status.Code, hasSpanEvent(decode, "exception"), tt.wantOutcome)
+			}
+		})
+	}
+}
+
+func TestLargeCatalogsAreStreamed(t *testing.T) {
+	tests := []struct {
+		name         string
+		threshold    int
+		wantStreamed bool
+	}{
+		{name: "above the threshold", threshold: 100, wantStreamed: true},
+		{name: "at the threshold", threshold: 250},
+		{name: "streaming disabled", threshold: 0},
+	}
+
+	products := make(map[string]models.Product, 250)
+	for i := 0; i < 250; i++ {
+		name := fmt.Sprintf("Product %03d", i)
+		products[name] = models.Product{Name: name, Price: models.MoneyFromFloat(1.5), Stock: 1, Category: "Bulk"}
+	}
+	catalog, err := json.Marshal(products)
+	if err != nil {
+		t.Fatalf("encode catalog: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, func(c *config.Config) { c.RESPONSE_STREAM_THRESHOLD = tt.threshold })
+			if err := os.WriteFile(globals.Cfg().PRODUCT_DATA_FILE_PATH, catalog, 0o644); err != nil {
+				t.Fatalf("write catalog: %v", err)
+			}
+
+			resp := doRequest(t, app, http.MethodGet, "/products", "")
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			var got []models.Product
+			decodeData(t, resp, &got)
+			if len(got) != len(products) {
+				t.Errorf("response has %d products, want %d", len(got), len(products))
+			}
+
+			span := onlySpan(t, "product_handler :: get_all_products")
+			if got, _ := spanAttr(span, "response.streamed"); got != fmt.Sprint(tt.wantStreamed) {
+				t.Errorf("response.streamed = %s, want %v", got, tt.wantStreamed)
+			}
+		})
+	}
+}
+
+func TestCompareProducts(t *testing.T) {
+	tests := []struct {
+		name         string
+		target       string
+		wantProducts string
+		wantDeltas   []int64
+		wantInStock  []bool
+		wantNotFound string
+		wantCheapest string
+		wantCount    string
+	}{
+		{name: "all found", target: "/products/compare?names=Blender%20Pro,Coffee%20Mug,Reading%20Lamp",
+			wantProducts: "Blender Pro,Coffee Mug,Reading Lamp", wantDeltas: []int64{6549, 0, 1049}, wantInStock: []bool{true, true, true},
+			wantCheapest: "Coffee Mug", wantCount: "3"},
+		{name: "partially found", target: "/products/compare?names=Reading%20Lamp,Teapot,Blender%20Pro",
+			wantProducts: "Reading Lamp,Blender Pro", wantDeltas: []int64{0, 5500}, wantInStock: []bool{true, true},
+			wantNotFound: "Teapot", wantCheapest: "Reading Lamp", wantCount: "3"},
+		{name: "duplicates and blanks dropped", target: "/products/compare?names=Coffee%20Mug,,%20Coffee%20Mug%20",
+			wantProducts: "Coffee Mug", wantDeltas: []int64{0}, wantInStock: []bool{true},
+			wantCheapest: "Coffee Mug", wantCount: "1"},
+		{name: "none found", target: "/products/compare?names=Teapot,Kettle",
+			wantNotFound: "Teapot,Kettle", wantCount: "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+
+			var comparison models.ProductComparison
+			decodeData(t, doRequest(t, app, http.MethodGet, tt.target, ""), &comparison)
+
+			names := make([]string, len(comparison.Products))
+			deltas := make([]int64, len(comparison.Products))
+			inStock := make([]bool, len(comparison.Products))
+			for i, p := range comparison.Products {
+				names[i] = p.Product.Name
+				deltas[i] = p.PriceDelta.Cents()
+				inStock[i] = p.InStock
+			}
+			if got := strings.Join(names, ","); got != tt.wantProducts {
+				t.Errorf("products = %s, want %s", got, tt.wantProducts)
+			}
+			if !slices.Equal(deltas, tt.wantDeltas) {
+				t.Errorf("price deltas = %v cents, want %v", deltas, tt.wantDeltas)
+			}
+			if !slices.Equal(inStock, tt.wantInStock) {
+				t.Errorf("in stock = %v, want %v", inStock, tt.wantInStock)
+			}
+			if got := strings.Join(comparison.NotFound, ","); got != tt.wantNotFound {
+				t.Errorf("notFound = %s, want %s", got, tt.wantNotFound)
+			}
+			if comparison.Cheapest != tt.wantCheapest {
+				t.Errorf("cheapest = %q, want %q", comparison.Cheapest, tt.wantCheapest)
+			}
+
+			for _, name := range []string{"product_handler :: compare_products", "product_service :: compare"} {
+				if got, _ := spanAttr(onlySpan(t, name), "compare.count"); got != tt.wantCount {
+					t.Errorf("%s compare.count = %q, want %q", name, got, tt.wantCount)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareProductsValidatesNames(t *testing.T) {
+	tooMany := make([]string, 21)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("Product %d", i)
+	}
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{name: "missing", target: "/products/compare"},
+		{name: "only blanks", target: "/products/compare?names=,%20,"},
+		{name: "more than twenty", target: "/products/compare?names=" + url.QueryEscape(strings.Join(tooMany, ","))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+
+			resp := doRequest(t, app, http.MethodGet, tt.target, "")
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			}
+			if got := decodeError(t, resp).Error.Code; got != apierrors.ErrCodeRequestValidation {
+				t.Errorf("error code = %q, want %q", got, apierrors.ErrCodeRequestValidation)
+			}
+		})
+	}
+}
+
+func TestSoftDeletedProductsAreHidden(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantMug    bool
+	}{
+		{name: "list", method: http.MethodGet, target: "/products", wantStatus: http.StatusOK},
+		{name: "list including deleted", method: http.MethodGet, target: "/products?includeDeleted=true", wantStatus: http.StatusOK, wantMug: true},
+		{name: "category", method: http.MethodGet, target: "/products/category?category=Kitchenware", wantStatus: http.StatusOK},
+		{name: "category including deleted", method: http.MethodGet, target: "/products/category?category=Kitchenware&includeDeleted=true", wantStatus: http.StatusOK, wantMug: true},
+		{name: "lookup", method: http.MethodPost, target: "/products/details", body: `{"name": "Coffee Mug"}`, wantStatus: http.StatusNotFound},
+		{name: "buy", method: http.MethodPost, target: "/products/buy", body: `{"name": "Coffee Mug", "quantity": 1}`, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			if resp := doRequest(t, app, http.MethodDelete, "/products/Coffee%20Mug", ""); resp.StatusCode != http.StatusOK {
+				t.Fatalf("DELETE status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			resp := doRequest(t, app, tt.method, tt.target, tt.body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("%s %s status = %d, want %d", tt.method, tt.target, resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var products []models.Product
+			decodeData(t, resp, &products)
+			var mug *models.Product
+			for i := range products {
+				if products[i].Name == "Coffee Mug" {
+					mug = &products[i]
+				}
+			}
+			if (mug != nil) != tt.wantMug {
+				t.Fatalf("Coffee Mug listed = %v, want %v", mug != nil, tt.wantMug)
+			}
+			if mug != nil && (!mug.Deleted || mug.DeletedAt == nil) {
+				t.Errorf("listed Coffee Mug deleted = %v, deletedAt = %v, want it marked deleted", mug.Deleted, mug.DeletedAt)
+			}
+		})
+	}
+}
+
+func TestScannedCountsIncludeSoftDeletedProducts(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		span   string
+	}{
+		{name: "lookup by name", method: http.MethodPost, target: "/products/details", body: `{"name": "Coffee Mug"}`,
+			span: "product_repository :: get_by_name"},
+		{name: "compare", method: http.MethodGet, target: "/products/compare?names=Coffee%20Mug,Blender%20Pro",
+			span: "product_repository :: get_by_names"},
+		{name: "category", method: http.MethodGet, target: "/products/category?category=Kitchenware",
+			span: "product_repository :: get_by_category"},
+		{name: "search", method: http.MethodGet, target: "/products/search?q=mug",
+			span: "product_repository :: search"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			if resp := doRequest(t, app, http.MethodDelete, "/products/Reading%20Lamp", ""); resp.StatusCode != http.StatusOK {
+				t.Fatalf("DELETE status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			harness.Reset()
+
+			if resp := doRequest(t, app, tt.method, tt.target, tt.body); resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			span := onlySpan(t, tt.span)
+			if got, _ := spanAttr(span, "products.scanned.count"); got != "3" {
+				t.Errorf("products.scanned.count = %q, want the whole catalog of 3", got)
+			}
+			if got, _ := spanAttr(span, "products.deleted.hidden"); got != "1" {
+				t.Errorf("products.deleted.hidden = %q, want 1", got)
+			}
+		})
+	}
+}
+
+func TestSoftDeletedProductsLeaveTheStockGauge(t *testing.T) {
+	app := newTestApp(t)
+	mug := attribute.String(metric.AttrProductName, "Coffee Mug")
+
+	doRequest(t, app, http.MethodGet, "/products", "")
+	if _, ok := harness.MetricValueWith(metric.ProductStockCountMetric, mug); !ok {
+		t.Fatalf("no stock reported for Coffee Mug before deletion")
+	}
+
+	doRequest(t, app, http.MethodDelete, "/products/Coffee%20Mug", "")
+	doRequest(t, app, http.MethodGet, "/products?includeDeleted=true", "")
+
+	if stock, ok := harness.MetricValueWith(metric.ProductStockCountMetric, mug); ok {
+		t.Errorf("stock gauge still reports %v for the deleted Coffee Mug", stock)
+	}
+	if _, ok := harness.MetricValueWith(metric.ProductStockCountMetric, attribute.String(metric.AttrProductName, "Reading Lamp")); !ok {
+		t.Errorf("stock gauge dropped the live Reading Lamp")
+	}
+}
+
+func TestPurgeRemovesOnlySoftDeletedProducts(t *testing.T) {
+	app := newTestApp(t)
+
+	resp := doRequest(t, app, http.MethodDelete, "/products/Coffee%20Mug/purge", "")
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("purging a live product: status = %d, want %d", resp.StatusCode, http.StatusConflict)
+	}
+	if got := decodeError(t, resp).Error.Code; got != apierrors.ErrCodeConflict {
+		t.Errorf("purging a live product: error code = %q, want %q", got, apierrors.ErrCodeConflict)
+	}
+
+	doRequest(t, app, http.MethodDelete, "/products/Coffee%20Mug", "")
+	if resp := doRequest(t, app, http.MethodDelete, "/products/Coffee%20Mug/purge", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("purging a deleted product: status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var products []models.Product
+	decodeData(t, doRequest(t, app, http.MethodGet, "/products?includeDeleted=true", ""), &products)
+	for _, p := range products {
+		if p.Name == "Coffee Mug" {
+			t.Errorf("purged Coffee Mug still listed with includeDeleted=true")
+		}
+	}
+	if len(products) != 2 {
+		t.Errorf("listed %d products after purge, want 2", len(products))
+	}
+}
+
+func TestPaddedProductNamesResolve(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		target        string
+		body          string
+		wantNormalize bool
+	}{
+		{name: "lookup", method: http.MethodPost, target: "/products/details", body: `{"name": "  Coffee Mug "}`, wantNormalize: true},
+		{name: "buy", method: http.MethodPost, target: "/products/buy", body: `{"name": "Coffee Mug\t", "quantity": 1}`, wantNormalize: true},
+		{name: "stock update", method: http.MethodPatch, target: "/products/stock", body: `{"name": " Coffee Mug", "stock": 5}`, wantNormalize: true},
+		{name: "clean name", method: http.MethodPost, target: "/products/details", body: `{"name": "Coffee Mug"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			resp := doRequest(t, app, tt.method, tt.target, tt.body)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			if got := hasSpanEvent(onlySpan(t, "request :: decode"), "name.normalized"); got != tt.wantNormalize {
+				t.Errorf("name.normalized event recorded = %v, want %v", got, tt.wantNormalize)
+			}
+		})
+	}
+}
+
+func TestMetricAggregatesReset(t *testing.T) {
+	app := newTestApp(t)
+	if resp := doRequest(t, app, http.MethodPost, "/products/buy", `{"name": "Coffee Mug", "quantity": 2}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("buy status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	pipeline.Traces.Record(5, 3, 1)
+	pipeline.Traces.RecordDiscarded(2)
+	pipeline.Logs.Record(4, 4, 0)
+	if metric.RevenueTotalCents() == 0 {
+		t.Fatalf("revenue total is zero before the reset")
+	}
+	exported, _ := harness.MetricValue(metric.AppRevenueTotalMetric)
+
+	if resp := doRequest(t, app, http.MethodPost, "/debug/metrics/reset", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("reset status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	if got := metric.RevenueTotalCents(); got != 0 {
+		t.Errorf("revenue total after reset = %d cents, want 0", got)
+	}
+	health := pipeline.CurrentHealth()
+	for name, snapshot := range map[string]pipeline.SignalSnapshot{"traces": health.Traces, "metrics": health.Metrics, "logs": health.Logs} {
+		if snapshot != (pipeline.SignalSnapshot{}) {
+			t.Errorf("%s pipeline after reset = %+v, want all zero", name, snapshot)
+		}
+	}
+	// Exported counters are cumulative and survive the reset
+	if got, _ := harness.MetricValue(metric.AppRevenueTotalMetric); got != exported {
+		t.Errorf("%s = %v after reset, want it unchanged at %v", metric.AppRevenueTotalMetric, got, exported)
+	}
+}
+
+func TestMetricAggregatesResetRequiresDebugEndpoints(t *testing.T) {
+	app := newTestApp(t, func(c *config.Config) { c.DEBUG_ENDPOINTS_ENABLED = false })
+
+	if resp := doRequest(t, app, http.MethodPost, "/debug/metrics/reset", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestStrictDecodingRejectsUnknownFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		strict      string
+		method      string
+		target      string
+		body        string
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "unknown field ignored by default", method: http.MethodPost, target: "/products/buy",
+			body: `{"name": "Coffee Mug", "quantity": 1, "giftWrap": true}`, wantStatus: http.StatusOK},
+		{name: "unknown field rejected for every operation", strict: "*", method: http.MethodPost, target: "/products/buy",
+			body: `{"name": "Coffee Mug", "quantity": 1, "giftWrap": true}`, wantStatus: http.StatusBadRequest, wantMessage: `"giftWrap"`},
+		{name: "typo named instead of a zero quantity", strict: "buy_product", method: http.MethodPost, target: "/products/buy",
+			body: `{"name": "Coffee Mug", "quantiy": 2}`, wantStatus: http.StatusBadRequest, wantMessage: `"quantiy"`},
+		{name: "operations not listed stay lenient", strict: "buy_product", method: http.MethodPatch, target: "/products/stock",
+			body: `{"name": "Coffee Mug", "stock": 5, "reason": "recount"}`, wantStatus: http.StatusOK},
+		{name: "listed operations among several", strict: "buy_product, update_product_stock", method: http.MethodPatch, target: "/products/stock",
+			body: `{"name": "Coffee Mug", "stock": 5, "reason": "recount"}`, wantStatus: http.StatusBadRequest, wantMessage: `"reason"`},
+		{name: "known fields accepted in strict mode", strict: "*", method: http.MethodPost, target: "/products/buy",
+			body: `{"name": "Coffee Mug", "quantity": 1}`, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, func(c *config.Config) { c.STRICT_REQUEST_DECODING = tt.strict })
+
+			resp := doRequest(t, app, tt.method, tt.target, tt.body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK {
+				resp.Body.Close()
+				return
+			}
+			body := decodeError(t, resp)
+			if body.Error.Code != apierrors.ErrCodeRequestValidation {
+				t.Errorf("error code = %q, want %q", body.Error.Code, apierrors.ErrCodeRequestValidation)
+			}
+			if !strings.Contains(body.Error.Message, tt.wantMessage) {
+				t.Errorf("error message %q does not name the field %s", body.Error.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+// spanByID returns the recorded span with the given span ID.
+func spanByID(spans tracetest.SpanStubs, id trace.SpanID) (tracetest.SpanStub, bool) {
+	for _, span := range spans {
+		if span.SpanContext.SpanID() == id {
+			return span, true
+		}
+	}
+	return tracetest.SpanStub{}, false
+}
+
+func TestRequestLatencyBreakdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		chain  []string
+	}{
+		{name: "buy", method: http.MethodPost, target: "/products/buy", body: `{"name": "Coffee Mug", "quantity": 1}`,
+			chain: []string{"product_handler :: buy_product", "product_service :: buy_product", "product_repository :: update_stock", "file_database :: write"}},
+		{name: "list", method: http.MethodGet, target: "/products",
+			chain: []string{"product_handler :: get_all_products", "product_service :: get_all_products", "product_repository :: get_all", "file_database :: read"}},
+		{name: "lookup", method: http.MethodPost, target: "/products/details", body: `{"name": "Reading Lamp"}`,
+			chain: []string{"product_handler :: get_product_by_name", "product_service :: get_by_name", "product_repository :: get_by_name", "file_database :: read"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			if resp := doRequest(t, app, tt.method, tt.target, tt.body); resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			spans := harness.Spans()
+			var server tracetest.SpanStub
+			for _, span := range spans {
+				if !span.Parent.IsValid() {
+					server = span
+				}
+			}
+
+			// Each layer is its own span, a child of the layer above and within its time
+			for i := len(tt.chain) - 1; i > 0; i-- {
+				children := harness.SpansNamed(tt.chain[i])
+				if len(children) == 0 {
+					t.Fatalf("no %s span recorded", tt.chain[i])
+				}
+				child := children[len(children)-1]
+				parent, ok := spanByID(spans, child.Parent.SpanID())
+				for ok && parent.Name != tt.chain[i-1] && parent.Name != server.Name {
+					parent, ok = spanByID(spans, parent.Parent.SpanID())
+				}
+				if !ok || parent.Name != tt.chain[i-1] {
+					t.Fatalf("%s is not nested under %s", tt.chain[i], tt.chain[i-1])
+				}
+				if child.StartTime.Before(parent.StartTime) || child.EndTime.After(parent.EndTime) {
+					t.Errorf("%s (%s – %s) is outside its parent %s (%s – %s)", child.Name, child.StartTime, child.EndTime,
+						parent.Name, parent.StartTime, parent.EndTime)
+				}
+			}
+
+			var total float64
+			for _, layer := range []string{"handler", "service", "repo", "db", "downstream"} {
+				key := "latency." + layer + "_ms"
+				raw, ok := spanAttr(server, key)
+				if !ok {
+					t.Errorf("server span has no %s attribute", key)
+					continue
+				}
+				ms, err := strconv.ParseFloat(raw, 64)
+				if err != nil || ms < 0 {
+					t.Errorf("%s = %q, want a non-negative number", key, raw)
+				}
+				total += ms
+			}
+			if limit := float64(server.EndTime.Sub(server.StartTime).Microseconds()) / 1000; total > limit {
+				t.Errorf("layer self times add up to %.3fms, more than the %.3fms request", total, limit)
+			}
+		})
+	}
+}
+
+func TestStockChangesApplyCategoryThresholds(t *testing.T) {
+	metric.SetStockThresholds(10, map[string]int{"kitchenware": 25})
+	t.Cleanup(func() { metric.SetStockThresholds(10, nil) })
+
+	tests := []struct {
+		name          string
+		product       string
+		stock         int
+		wantThreshold string
+		wantBelow     string
+		wantWarning   bool
+	}{
+		// Coffee Mug starts at 20, already under the Kitchenware threshold
+		{name: "category threshold overrides the global one", product: "Coffee Mug", stock: 15, wantThreshold: "25", wantBelow: "true"},
+		{name: "above the category threshold", product: "Coffee Mug", stock: 30, wantThreshold: "25", wantBelow: "false"},
+		// Blender Pro starts at 30, so dropping to 24 crosses the Kitchenware threshold
+		{name: "crossing the category threshold warns", product: "Blender Pro", stock: 24, wantThreshold: "25", wantBelow: "true", wantWarning: true},
+		// Reading Lamp starts at 8 in Furniture, which has no override
+		{name: "global threshold without an override", product: "Reading Lamp", stock: 12, wantThreshold: "10", wantBelow: "false"},
+		{name: "below the global threshold", product: "Reading Lamp", stock: 4, wantThreshold: "10", wantBelow: "true"},
+	}
+
+	for _, tt := range tests {
+		for _, update := range stockUpdates {
+			t.Run(tt.name+" via "+update.operation, func(t *testing.T) {
+				app, logs := newTestAppWithLogs(t)
+				if resp := update.send(t, app, tt.product, tt.stock); resp.StatusCode != http.StatusOK {
+					t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+				}
+
+				span := onlySpan(t, "product_repository :: "+update.operation)
+				if got, _ := spanAttr(span, "stock.threshold"); got != tt.wantThreshold {
+					t.Errorf("stock.threshold = %q, want %q", got, tt.wantThreshold)
+				}
+				if got, _ := spanAttr(span, "stock.below_threshold"); got != tt.wantBelow {
+					t.Errorf("stock.below_threshold = %q, want %q", got, tt.wantBelow)
+				}
+				warned := strings.Contains(logs.String(), `"event_type":"low_stock"`)
+				if warned != tt.wantWarning {
+					t.Errorf("low_stock warning logged = %v, want %v", warned, tt.wantWarning)
+				}
+			})
+		}
+	}
+}
+
+// stockUpdates are the two ways of setting a product's stock: the stock endpoint and
+// a partial product update.
+var stockUpdates = []struct {
+	operation string // repository operation, as in the span name
+	send      func(t *testing.T, app *fiber.App, product string, stock int) *http.Response
+}{
+	{operation: "update_stock", send: func(t *testing.T, app *fiber.App, product string, stock int) *http.Response {
+		return doRequest(t, app, http.MethodPatch, "/products/stock", fmt.Sprintf(`{"name": %q, "stock": %d}`, product, stock))
+	}},
+	{operation: "update_product", send: func(t *testing.T, app *fiber.App, product string, stock int) *http.Response {
+		return doRequest(t, app, http.MethodPatch, "/products/"+url.PathEscape(product), fmt.Sprintf(`{"stock": %d}`, stock))
+	}},
+}
+
+func TestStockChangesReportBlankCategoriesUnderTheDefault(t *testing.T) {
+	for _, update := range stockUpdates {
+		t.Run(update.operation, func(t *testing.T) {
+			app := newTestApp(t)
+			catalog := strings.Replace(testCatalog, `"category": "Furniture"`, `"category": ""`, 1)
+			if err := os.WriteFile(globals.Cfg().PRODUCT_DATA_FILE_PATH, []byte(catalog), 0o644); err != nil {
+				t.Fatalf("write catalog: %v", err)
+			}
+
+			if resp := update.send(t, app, "Reading Lamp", 3); resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			lamp := attribute.String(metric.AttrProductName, "Reading Lamp")
+			if got, found := harness.MetricValueWith(metric.ProductStockCountMetric, lamp,
+				attribute.String(metric.AttrProductCategory, globals.Cfg().DEFAULT_CATEGORY)); !found || got != 3 {
+				t.Errorf("%s{Reading Lamp, %s} = %v (found %v), want 3", metric.ProductStockCountMetric, globals.Cfg().DEFAULT_CATEGORY, got, found)
+			}
+			if _, found := harness.MetricValueWith(metric.ProductStockCountMetric, lamp,
+				attribute.String(metric.AttrProductCategory, "")); found {
+				t.Errorf("%s reports Reading Lamp under a blank category", metric.ProductStockCountMetric)
+			}
+		})
+	}
+}
+
+func TestUnlimitedStockBuysAreMarkedOnTheSpan(t *testing.T) {
+	tests := []struct {
+		name          string
+		product       string
+		wantUnlimited string
+		wantStock     int
+	}{
+		{name: "unlimited category", product: "Reading Lamp", wantUnlimited: "true", wantStock: 8},
+		{name: "normal category", product: "Coffee Mug", wantUnlimited: "false", wantStock: 19},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, func(c *config.Config) { c.UNLIMITED_STOCK_CATEGORIES = "furniture" })
+			body := fmt.Sprintf(`{"name": %q, "quantity": 1}`, tt.product)
+			if resp := doRequest(t, app, http.MethodPost, "/products/buy", body); resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			if got, _ := spanAttr(onlySpan(t, "product_service :: buy_product"), "stock.unlimited"); got != tt.wantUnlimited {
+				t.Errorf("stock.unlimited = %q, want %q", got, tt.wantUnlimited)
+			}
+			var product models.Product
+			decodeData(t, doRequest(t, app, http.MethodPost, "/products/details", fmt.Sprintf(`{"name": %q}`, tt.product)), &product)
+			if product.Stock != tt.wantStock {
+				t.Errorf("%s stock after buying 1 = %d, want %d", tt.product, product.Stock, tt.wantStock)
+			}
+		})
+	}
+}
+
+func TestNotFoundLookupsSuggestNames(t *testing.T) {
+	tests := []struct {
+		name string
+		want []any
+	}{
+		{name: "Cofee Mug", want: []any{"Coffee Mug"}},
+		{name: "reading lamp", want: []any{"Reading Lamp"}},
+		{name: "Vacuum Cleaner", want: []any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			resp := doRequest(t, app, http.MethodPost, "/products/details", fmt.Sprintf(`{"name": %q}`, tt.name))
+			if resp.StatusCode != http.StatusNotFound {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+			}
+
+			body := decodeError(t, resp)
+			got, _ := body.Error.Details["suggestions"].([]any)
+			if got == nil {
+				got = []any{}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("error.details.suggestions = %v, want %v", got, tt.want)
+			}
+			wantCount := strconv.Itoa(len(tt.want))
+			if count, _ := spanAttr(onlySpan(t, "product_repository :: get_by_name"), "suggestions.count"); count != wantCount {
+				t.Errorf("suggestions.count = %q, want %q", count, wantCount)
+			}
+		})
+	}
+}
+
+func TestValidationFailuresAreCountedPerRoute(t *testing.T) {
+	app := newTestApp(t)
+	routes := []struct {
+		method, route string
+		want          float64
+	}{
+		{method: http.MethodPost, route: "/products/details", want: 1},
+		{method: http.MethodGet, route: "/products/compare", want: 2},
+		{method: http.MethodGet, route: "/products", want: 0},
+	}
+	count := func(method, route string) float64 {
+		value, _ := harness.MetricValueWith(metric.ValidationFailuresMetric,
+			attribute.String(metric.AttrHTTPRoute, route),
+			attribute.String(metric.AttrHTTPMethod, method))
+		return value
+	}
+	before := make([]float64, len(routes))
+	for i, r := range routes {
+		before[i] = count(r.method, r.route)
+	}
+
+	requests := []struct {
+		method, target, body string
+		wantStatus           int
+	}{
+		{method: http.MethodPost, target: "/products/details", body: `{"name": `, wantStatus: http.StatusBadRequest},
+		{method: http.MethodPost, target: "/products/details", body: `{"name": "Coffee Mug"}`, wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/products/compare", wantStatus: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/products/compare?names=,", wantStatus: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/products", wantStatus: http.StatusOK},
+	}
+	for _, r := range requests {
+		resp := doRequest(t, app, r.method, r.target, r.body)
+		if resp.StatusCode != r.wantStatus {
+			t.Fatalf("%s %s: status = %d, want %d", r.method, r.target, resp.StatusCode, r.wantStatus)
+		}
+	}
+
+	for i, r := range routes {
+		if got := count(r.method, r.route) - before[i]; got != r.want {
+			t.Errorf("%s %s: %s rose by %v, want %v", r.method, r.route, metric.ValidationFailuresMetric, got, r.want)
+		}
+	}
+}
+
+func TestFieldsParameterShapesProducts(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		threshold  int
+		span       string
+		wantFields string // empty means the full product
+		wantKeys   []string
+		wantCount  int
+	}{
+		{name: "list", method: http.MethodGet, target: "/products?fields=name,price", span: "product_handler :: get_all_products",
+			wantFields: "name,price", wantKeys: []string{"name", "price"}, wantCount: 3},
+		{name: "streamed list", method: http.MethodGet, target: "/products?fields=stock", threshold: 1, span: "product_handler :: get_all_products",
+			wantFields: "stock", wantKeys: []string{"stock"}, wantCount: 3},
+		{name: "category", method: http.MethodGet, target: "/products/category?category=Kitchenware&fields=name,%20category,name", span: "product_handler :: get_products_by_category",
+			wantFields: "name,category", wantKeys: []string{"category", "name"}, wantCount: 2},
+		{name: "details", method: http.MethodPost, target: "/products/details?fields=name,stock", body: `{"name": "Coffee Mug"}`, span: "product_handler :: get_product_by_name",
+			wantFields: "name,stock", wantKeys: []string{"name", "stock"}, wantCount: 1},
+		{name: "blank parameter", method: http.MethodGet, target: "/products?fields=%20,%20", span: "product_handler :: get_all_products",
+			wantKeys: []string{"category", "description", "name", "price", "stock"}, wantCount: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, func(c *config.Config) { c.RESPONSE_STREAM_THRESHOLD = tt.threshold })
+			resp := doRequest(t, app, tt.method, tt.target, tt.body)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			var products []map[string]json.RawMessage
+			if tt.method == http.MethodPost {
+				var product map[string]json.RawMessage
+				decodeData(t, resp, &product)
+				products = append(products, product)
+			} else {
+				decodeData(t, resp, &products)
+			}
+			if len(products) != tt.wantCount {
+				t.Fatalf("got %d products, want %d", len(products), tt.wantCount)
+			}
+			for _, product := range products {
+				keys := slices.Sorted(maps.Keys(product))
+				if !slices.Equal(keys, tt.wantKeys) {
+					t.Errorf("product fields = %v, want %v", keys, tt.wantKeys)
+				}
+			}
+
+			got, ok := spanAttr(onlySpan(t, tt.span), "response.fields")
+			if tt.wantFields == "" {
+				if ok {
+					t.Errorf("response.fields = %q for the full product, want it unset", got)
+				}
+			} else if got != tt.wantFields {
+				t.Errorf("response.fields = %q, want %q", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestFieldsParameterRejectsUnknownNames(t *testing.T) {
+	app := newTestApp(t)
+	for _, target := range []string{"/products?fields=name,colour", "/products/category?category=Furniture&fields=Price"} {
+		resp := doRequest(t, app, http.MethodGet, target, "")
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("GET %s: status = %d, want %d", target, resp.StatusCode, http.StatusBadRequest)
+		}
+		if body := decodeError(t, resp); body.Error.Code != apierrors.ErrCodeRequestValidation || !strings.Contains(body.Error.Message, "allowed: category,deleted,deletedAt,description,name,price,stock") {
+			t.Errorf("GET %s: error = %+v, want a validation error listing the allowed fields", target, body.Error)
+		}
+	}
+}
+
+func TestCatalogSizeFollowsDeletesAndReconcilesOnGetAll(t *testing.T) {
+	app := newTestApp(t)
+	getAll := func() tracetest.SpanStub {
+		t.Helper()
+		harness.Reset()
+		if resp := doRequest(t, app, http.MethodGet, "/products", ""); resp.StatusCode != http.StatusOK {
+			t.Fatalf("list status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		return onlySpan(t, "product_repository :: get_all")
+	}
+	catalogSize := func() float64 {
+		size, _ := harness.MetricValue(metric.CatalogSizeMetric)
+		return size
+	}
+
+	getAll()
+	if got := catalogSize(); got != 3 {
+		t.Fatalf("%s = %v after listing, want 3", metric.CatalogSizeMetric, got)
+	}
+
+	if resp := doRequest(t, app, http.MethodDelete, "/products/Coffee%20Mug", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := catalogSize(); got != 2 {
+		t.Errorf("%s = %v after a delete, want 2 without another read", metric.CatalogSizeMetric, got)
+	}
+	if drift, ok := spanAttr(getAll(), "catalog.size.drift"); ok {
+		t.Errorf("catalog.size.drift = %s after an accurate count, want it unset", drift)
+	}
+
+	// A change the counter missed shows up as drift on the next full read
+	metric.AddCatalogSize(1)
+	if drift, _ := spanAttr(getAll(), "catalog.size.drift"); drift != "1" {
+		t.Errorf("catalog.size.drift = %q, want 1", drift)
+	}
+	if got := catalogSize(); got != 2 {
+		t.Errorf("%s = %v after reconciling, want 2", metric.CatalogSizeMetric, got)
+	}
+}
+
+func TestPurchasesCrossingTheThresholdTriggerReorder(t *testing.T) {
+	metric.SetStockThresholds(10, map[string]int{"kitchenware": 25})
+	t.Cleanup(func() { metric.SetStockThresholds(10, nil) })
+	app := newTestApp(t)
+
+	// Blender Pro starts at 30; the first purchase takes it below 25, the second stays there
+	for _, tt := range []struct {
+		quantity      int
+		wantTriggered string
+	}{
+		{quantity: 6, wantTriggered: "true"},
+		{quantity: 1, wantTriggered: "false"},
+	} {
+		harness.Reset()
+		body := fmt.Sprintf(`{"name": "Blender Pro", "quantity": %d}`, tt.quantity)
+		if resp := doRequest(t, app, http.MethodPost, "/products/buy", body); resp.StatusCode != http.StatusOK {
+			t.Fatalf("buy %d: status = %d, want %d", tt.quantity, resp.StatusCode, http.StatusOK)
+		}
+
+		span := onlySpan(t, "product_service :: buy_product")
+		if got, _ := spanAttr(span, "reorder.triggered"); got != tt.wantTriggered {
+			t.Errorf("buy %d: reorder.triggered = %q, want %s", tt.quantity, got, tt.wantTriggered)
+		}
+		if published := hasSpanEvent(span, "event.published"); published != (tt.wantTriggered == "true") {
+			t.Errorf("buy %d: event.published recorded = %v, want %v", tt.quantity, published, tt.wantTriggered == "true")
+		}
+	}
+}
+
+func TestBuyFunnelCountsEachStage(t *testing.T) {
+	app := newTestApp(t, func(c *config.Config) { c.MAX_PURCHASE_QUANTITY = 10 })
+	stages := []string{metric.BuyRequestsMetric, metric.BuyValidatedMetric, metric.BuyStockAvailableMetric, metric.BuyCompletedMetric}
+	before := make(map[string]float64)
+	for _, stage := range stages {
+		before[stage], _ = harness.MetricValue(stage)
+	}
+
+	purchases := []struct {
+		body       string
+		wantStatus int
+	}{
+		{body: `{"name": "Coffee Mug", "quantity": 1}`, wantStatus: http.StatusOK},
+		{body: `{"name": "Coffee Mug", "quantity": 2}`, wantStatus: http.StatusOK},
+		// Over MAX_PURCHASE_QUANTITY: fails service validation
+		{body: `{"name": "Coffee Mug", "quantity": 11}`, wantStatus: http.StatusBadRequest},
+		{body: `{"name": "Teapot", "quantity": 1}`, wantStatus: http.StatusNotFound},
+		{body: `{"name": "Reading Lamp", "quantity": 9}`, wantStatus: http.StatusBadRequest},
+		// Rejected by request decoding, so it never enters the funnel
+		{body: `{"name": "Coffee Mug", "quantity": 0}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, p := range purchases {
+		if resp := doRequest(t, app, http.MethodPost, "/products/buy", p.body); resp.StatusCode != p.wantStatus {
+			t.Fatalf("buy %s: status = %d, want %d", p.body, resp.StatusCode, p.wantStatus)
+		}
+	}
+
+	want := map[string]float64{
+		metric.BuyRequestsMetric:       5,
+		metric.BuyValidatedMetric:      4,
+		metric.BuyStockAvailableMetric: 2,
+		metric.BuyCompletedMetric:      2,
+	}
+	for _, stage := range stages {
+		after, _ := harness.MetricValue(stage)
+		if got := after - before[stage]; got != want[stage] {
+			t.Errorf("%s rose by %v, want %v", stage, got, want[stage])
+		}
+	}
+}
+
+func TestBuyFunnelCountsUnlimitedStockAsAvailable(t *testing.T) {
+	app := newTestApp(t, func(c *config.Config) {
+		c.MAX_PURCHASE_QUANTITY = 10
+		c.UNLIMITED_STOCK_CATEGORIES = "furniture"
+	})
+	before, _ := harness.MetricValue(metric.BuyStockAvailableMetric)
+
+	// More than the 8 Reading Lamps in stock, but Furniture is never out of stock
+	if resp := doRequest(t, app, http.MethodPost, "/products/buy", `{"name": "Reading Lamp", "quantity": 9}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	if after, _ := harness.MetricValue(metric.BuyStockAvailableMetric); after-before != 1 {
+		t.Errorf("%s rose by %v, want 1", metric.BuyStockAvailableMetric, after-before)
+	}
+}
+
+func TestReadinessIsRecordedInHealthHistory(t *testing.T) {
+	var dataFile string
+	app := newTestApp(t, func(c *config.Config) {
+		c.HEALTH_HISTORY_SIZE = 3
+		dataFile = c.PRODUCT_DATA_FILE_PATH
+	})
+	// Start with every exporter connected and put the real state back afterwards
+	connections := metric.ExporterConnections()
+	for signal := range connections {
+		metric.SetExporterConnected(signal, true)
+	}
+	t.Cleanup(func() {
+		for signal, connected := range connections {
+			metric.SetExporterConnected(signal, connected)
+		}
+		if _, ok := connections["logs"]; !ok {
+			metric.SetExporterConnected("logs", true)
+		}
+	})
+
+	steps := []struct {
+		name        string
+		setup       func()
+		wantStatus  int
+		wantFailing []string
+	}{
+		{name: "ready", setup: func() {}, wantStatus: http.StatusOK},
+		{name: "exporter disconnected", setup: func() { metric.SetExporterConnected("logs", false) }, wantStatus: http.StatusServiceUnavailable, wantFailing: []string{"exporter.logs"}},
+		{name: "data file missing too", setup: func() {
+			if err := os.Remove(dataFile); err != nil {
+				t.Fatalf("remove data file: %v", err)
+			}
+		}, wantStatus: http.StatusServiceUnavailable, wantFailing: []string{"data_file", "exporter.logs"}},
+		{name: "recovered", setup: func() {
+			metric.SetExporterConnected("logs", true)
+			if err := os.WriteFile(dataFile, []byte(testCatalog), 0o644); err != nil {
+				t.Fatalf("restore data file: %v", err)
+			}
+		}, wantStatus: http.StatusOK},
+	}
+
+	for _, step := range steps {
+		step.setup()
+		if resp := doRequest(t, app, http.MethodGet, "/ready", ""); resp.StatusCode != step.wantStatus {
+			t.Errorf("%s: GET /ready = %d, want %d", step.name, resp.StatusCode, step.wantStatus)
+		}
+	}
+
+	var history struct {
+		Capacity int `json:"capacity"`
+		Results  []struct {
+			Time    time.Time `json:"time"`
+			Ready   bool      `json:"ready"`
+			Failing []string  `json:"failing"`
+		} `json:"results"`
+	}
+	decodeData(t, doRequest(t, app, http.MethodGet, "/debug/health-history", ""), &history)
+
+	if history.Capacity != 3 {
+		t.Errorf("capacity = %d, want 3", history.Capacity)
+	}
+	// The first result has rolled out of the three-result buffer
+	kept := steps[1:]
+	if len(history.Results) != len(kept) {
+		t.Fatalf("history has %d results, want %d: %+v", len(history.Results), len(kept), history.Results)
+	}
+	for i, step := range kept {
+		got := history.Results[i]
+		if got.Ready != (step.wantStatus == http.StatusOK) || !slices.Equal(got.Failing, step.wantFailing) {
+			t.Errorf("results[%d] = ready %v, failing %v, want the %q result (failing %v)", i, got.Ready, got.Failing, step.name, step.wantFailing)
+		}
+		if i > 0 && got.Time.Before(history.Results[i-1].Time) {
+			t.Errorf("results[%d] at %s is older than results[%d], want oldest first", i, got.Time, i-1)
+		}
+	}
+}
+
+func TestHealthHistoryStartsEmpty(t *testing.T) {
+	app := newTestApp(t)
+
+	var history struct {
+		Capacity int               `json:"capacity"`
+		Results  []json.RawMessage `json:"results"`
+	}
+	decodeData(t, doRequest(t, app, http.MethodGet, "/debug/health-history", ""), &history)
+
+	if history.Results == nil || len(history.Results) != 0 {
+		t.Errorf("results = %v, want an empty list", history.Results)
+	}
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry/metric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// aggregateProducts returns the products in productsMap accepted by keep (all when keep is nil),
+// optionally updating the stock-level metric for each one. Work is split across
+// r.aggregationWorkers goroutines; with one worker it runs serially.
+func (r *productRepository) aggregateProducts(ctx context.Context, productsMap map[string]models.Product, keep func(models.Product) bool, recordStock bool) []models.Product {
+	products := make([]models.Product, 0, len(productsMap))
+	for _, p := range productsMap {
+		products = append(products, p)
+	}
+
+	workers := r.aggregationWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(products) {
+		workers = len(products)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("aggregation.workers", workers))
+
+	process := func(chunk []models.Product) []models.Product {
+		kept := make([]models.Product, 0, len(chunk))
+		for _, p := range chunk {
+			if keep != nil && !keep(p) {
+				continue
+			}
+			kept = append(kept, p)
+			if recordStock && !p.Deleted {
+				metric.UpdateProductStockLevels(ctx, p.Name, p.Category, int64(p.Stock))
+			}
+			r.logger.DebugContext(ctx, "Processing individual product entity data",
+				slog.String("product_name", p.Name),
+				slog.String("product_category", p.Category),
+				slog.Float64("product_price", p.Price.Float64()),
+				slog.Int("stock", p.Stock),
+				slog.String("component", "product_repository"),
+				slog.String("operation", "entity_processing"))
+		}
+		return kept
+	}
+
+	if workers <= 1 {
+		return process(products)
+	}
+
+	chunkSize := (len(products) + workers - 1) / workers
+	results := make([][]models.Product, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * chunkSize
+		if start >= len(products) {
+			break
+		}
+		end := min(start+chunkSize, len(products))
+
+		wg.Add(1)
+		go func(w int, chunk []models.Product) {
+			defer wg.Done()
+			results[w] = process(chunk)
+		}(w, products[start:end])
+	}
+	wg.Wait()
+
+	merged := make([]models.Product, 0, len(products))
+	for _, kept := range results {
+		merged = append(merged, kept...)
+	}
+	return merged
+}
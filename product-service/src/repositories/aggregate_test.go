@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/narender/common/config"
+	"github.com/narender/common/models"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// largeCatalog returns a catalog of n products spread over five categories, every
+// tenth one soft-deleted.
+func largeCatalog(n int) string {
+	categories := []string{"Kitchenware", "Furniture", "Sports", "Garden", "Toys"}
+	entries := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("Product %05d", i)
+		entries = append(entries, fmt.Sprintf(`%q: {"name": %q, "description": "Generated product", "price": %d.99, "stock": %d, "category": %q, "deleted": %t}`,
+			name, name, i%500, i%100, categories[i%len(categories)], i%10 == 0))
+	}
+	return "{" + strings.Join(entries, ",\n") + "}"
+}
+
+func withWorkers(workers int) func(*config.Config) {
+	return func(c *config.Config) { c.AGGREGATION_WORKERS = workers }
+}
+
+func sortedByName(products []models.Product) []models.Product {
+	sort.Slice(products, func(i, j int) bool { return products[i].Name < products[j].Name })
+	return products
+}
+
+func TestParallelAggregationMatchesSerial(t *testing.T) {
+	catalog := largeCatalog(1003)
+	ctx := context.Background()
+
+	queries := []struct {
+		name string
+		run  func(repo ProductRepository) ([]models.Product, *apierrors.AppError)
+	}{
+		{name: "all", run: func(repo ProductRepository) ([]models.Product, *apierrors.AppError) { return repo.GetAll(ctx, false) }},
+		{name: "all including deleted", run: func(repo ProductRepository) ([]models.Product, *apierrors.AppError) { return repo.GetAll(ctx, true) }},
+		{name: "one category", run: func(repo ProductRepository) ([]models.Product, *apierrors.AppError) {
+			return repo.GetByCategory(ctx, "Sports", false)
+		}},
+	}
+
+	for _, query := range queries {
+		serial, appErr := query.run(newTestRepository(t, catalog, withWorkers(1)))
+		if appErr != nil {
+			t.Fatalf("%s with one worker: %v", query.name, appErr)
+		}
+		if len(serial) == 0 {
+			t.Fatalf("%s with one worker returned no products", query.name)
+		}
+		sortedByName(serial)
+
+		// Worker counts that do not divide the catalog evenly, and more workers than products
+		for _, workers := range []int{2, 7, 16, 5000} {
+			t.Run(fmt.Sprintf("%s with %d workers", query.name, workers), func(t *testing.T) {
+				parallel, appErr := query.run(newTestRepository(t, catalog, withWorkers(workers)))
+				if appErr != nil {
+					t.Fatalf("%v", appErr)
+				}
+				if !reflect.DeepEqual(sortedByName(parallel), serial) {
+					t.Errorf("returned %d products, want the %d returned serially", len(parallel), len(serial))
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkGetAll covers the whole read, in which decoding the data file dominates.
+func BenchmarkGetAll(b *testing.B) {
+	catalog := largeCatalog(20_000)
+	for _, workers := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			repo := newTestRepository(b, catalog, withWorkers(workers))
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, appErr := repo.GetAll(ctx, false); appErr != nil {
+					b.Fatalf("GetAll: %v", appErr)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkAggregateProducts isolates the work the pool parallelizes.
+func BenchmarkAggregateProducts(b *testing.B) {
+	catalog := largeCatalog(20_000)
+	for _, workers := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			repo := newTestRepository(b, catalog, withWorkers(workers)).(*productRepository)
+			ctx := context.Background()
+			var productsMap map[string]models.Product
+			if err := repo.readProducts(ctx, &productsMap); err != nil {
+				b.Fatalf("read catalog: %v", err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				repo.aggregateProducts(ctx, productsMap, nil, true)
+			}
+		})
+	}
+}
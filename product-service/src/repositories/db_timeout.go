@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/narender/common/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// readProducts reads the product map, giving up after the configured DB_OP_TIMEOUT.
+// File IO ignores the context, so a stuck filesystem would otherwise hang the request.
+func (r *productRepository) readProducts(ctx context.Context, dest *map[string]models.Product) error {
+	var productsMap map[string]models.Product
+	err := r.withDBTimeout(ctx, "read", func(ctx context.Context) error {
+		return r.database.Read(ctx, &productsMap)
+	})
+	if err == nil {
+		*dest = productsMap
+	}
+	return err
+}
+
+// writeProducts writes the product map. Unlike reads, writes are not bounded by
+// DB_OP_TIMEOUT: an abandoned write could still land after the caller released
+// writeMu, overlapping or overwriting a newer update.
+func (r *productRepository) writeProducts(ctx context.Context, productsMap map[string]models.Product) error {
+	return r.database.Write(ctx, productsMap)
+}
+
+// withDBTimeout runs op in its own goroutine and returns an ErrCodeRequestTimeout
+// AppError, recording a db.timeout span event, if it has not finished within the
+// timeout. The goroutine owns everything op writes to until op returns.
+func (r *productRepository) withDBTimeout(ctx context.Context, operation string, op func(ctx context.Context) error) error {
+	if r.dbOpTimeout <= 0 {
+		return op(ctx)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- op(ctx) }()
+
+	timer := time.NewTimer(r.dbOpTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+	}
+
+	trace.SpanFromContext(ctx).AddEvent("db.timeout", trace.WithAttributes(
+		attribute.String("db.operation", operation),
+		attribute.Int64("timeout_ms", r.dbOpTimeout.Milliseconds()),
+	))
+	r.logger.ErrorContext(ctx, "Database operation timed out",
+		slog.String("component", "product_repository"),
+		slog.String("db_operation", operation),
+		slog.Duration("timeout", r.dbOpTimeout),
+		slog.String("operation", "db_timeout"))
+	return apierrors.NewApplicationError(
+		apierrors.ErrCodeRequestTimeout,
+		"Database operation timed out",
+		nil).WithContext("db_operation", operation)
+}
+
+// dbError converts a readProducts/writeProducts failure into an AppError, passing
+// read timeouts through unchanged and reporting anything else as a database access error.
+func dbError(err error, message string) *apierrors.AppError {
+	var appErr *apierrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return apierrors.NewApplicationError(apierrors.ErrCodeDatabaseAccess, message, err)
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/narender/common/apierrors"
+	"github.com/narender/common/config"
+	"github.com/narender/common/models"
+)
+
+func TestDatabaseOperationsTimeOut(t *testing.T) {
+	finished := errors.New("operation finished")
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		opDelay  time.Duration
+		wantCode string
+		wantErr  error
+		maxWait  time.Duration
+		minWait  time.Duration
+	}{
+		{name: "fast operation", timeout: time.Second, opDelay: 0, wantErr: finished, maxWait: 500 * time.Millisecond},
+		{name: "slow operation times out", timeout: 50 * time.Millisecond, opDelay: 2 * time.Second, wantCode: apierrors.ErrCodeRequestTimeout, maxWait: time.Second},
+		{name: "timeout disabled waits for the operation", timeout: 0, opDelay: 100 * time.Millisecond, wantErr: finished, minWait: 100 * time.Millisecond, maxWait: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t, "{}", func(c *config.Config) {
+				c.DB_OP_TIMEOUT = tt.timeout
+			}).(*productRepository)
+			release := make(chan struct{})
+			defer close(release)
+
+			start := time.Now()
+			err := repo.withDBTimeout(context.Background(), "read", func(ctx context.Context) error {
+				select {
+				case <-time.After(tt.opDelay):
+				case <-release:
+				}
+				return finished
+			})
+			elapsed := time.Since(start)
+
+			if elapsed > tt.maxWait || elapsed < tt.minWait {
+				t.Errorf("withDBTimeout returned after %s, want between %s and %s", elapsed, tt.minWait, tt.maxWait)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("withDBTimeout error = %v, want the operation's %v", err, tt.wantErr)
+			}
+			if tt.wantCode != "" {
+				appErr := dbError(err, "Failed to read product data")
+				if appErr.Code != tt.wantCode {
+					t.Errorf("dbError code = %q, want %q", appErr.Code, tt.wantCode)
+				}
+				if appErr.ContextData["db_operation"] != "read" {
+					t.Errorf("db_operation context = %v, want read", appErr.ContextData["db_operation"])
+				}
+			}
+		})
+	}
+}
+
+func TestDBErrorWrapsOnlyPlainErrors(t *testing.T) {
+	timeout := apierrors.NewApplicationError(apierrors.ErrCodeRequestTimeout, "Database operation timed out", nil)
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{name: "timeout passes through", err: timeout, wantCode: apierrors.ErrCodeRequestTimeout},
+		{name: "file error becomes a database error", err: errors.New("permission denied"), wantCode: apierrors.ErrCodeDatabaseAccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dbError(tt.err, "Failed to read product data"); got.Code != tt.wantCode {
+				t.Errorf("dbError(%v).Code = %q, want %q", tt.err, got.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestWritesAreNotBoundedByTheOperationTimeout(t *testing.T) {
+	// A timeout no file write can meet
+	repo := newTestRepository(t, "{}", func(c *config.Config) {
+		c.DB_OP_TIMEOUT = time.Nanosecond
+	}).(*productRepository)
+
+	for i := 0; i < 20; i++ {
+		products := map[string]models.Product{"Coffee Mug": {Name: "Coffee Mug", Stock: i}}
+		if err := repo.writeProducts(context.Background(), products); err != nil {
+			t.Fatalf("write %d = %v, want it to run to completion", i, err)
+		}
+
+		// The write has landed by the time writeProducts returns
+		var stored map[string]models.Product
+		if err := repo.database.Read(context.Background(), &stored); err != nil {
+			t.Fatalf("read back: %v", err)
+		}
+		if stored["Coffee Mug"].Stock != i {
+			t.Fatalf("stored stock = %d after write %d, want %d", stored["Coffee Mug"].Stock, i, i)
+		}
+	}
+}
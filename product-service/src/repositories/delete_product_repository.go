@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry/metric"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// SoftDelete marks the named product as deleted, keeping it in the data file.
+// Deleting an already deleted product reports it as not found.
+func (r *productRepository) SoftDelete(ctx context.Context, name string) (product models.Product, appErr *apierrors.AppError) {
+	newCtx, span := commontrace.StartSpan(ctx, "product_repository", "soft_delete",
+		attribute.String(metric.AttrProductName, name))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if appErr != nil {
+			telemetryErr = appErr
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		appErr = simAppErr
+		return models.Product{}, appErr
+	}
+
+	// Hold the lock across read, modify and write so concurrent updates cannot interleave
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	var productsMap map[string]models.Product
+	if err := r.readProducts(ctx, &productsMap); err != nil {
+		r.logger.ErrorContext(ctx, "Database access error",
+			slog.String("component", "product_repository"),
+			slog.String("error", err.Error()),
+			slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
+			slog.String("operation", "soft_delete"))
+
+		appErr = dbError(err, "Failed to read product data from database")
+		metric.IncrementErrorCount(ctx, appErr.Code, "soft_delete", "repository")
+		return models.Product{}, appErr
+	}
+
+	product, ok := productsMap[name]
+	if !ok || product.Deleted {
+		r.logger.WarnContext(ctx, "Product not found",
+			slog.String("component", "product_repository"),
+			slog.String("product_name", name),
+			slog.String("error_code", apierrors.ErrCodeProductNotFound),
+			slog.String("operation", "soft_delete"))
+
+		appErr = apierrors.NewBusinessError(
+			apierrors.ErrCodeProductNotFound,
+			fmt.Sprintf("Product with name '%s' not found for deletion", name),
+			nil)
+		metric.IncrementErrorCount(ctx, apierrors.ErrCodeProductNotFound, "soft_delete", "repository")
+		return models.Product{}, appErr
+	}
+
+	deletedAt := time.Now().UTC()
+	product.Deleted = true
+	product.DeletedAt = &deletedAt
+	productsMap[name] = product
+	if writeErr := r.writeProducts(ctx, productsMap); writeErr != nil {
+		r.logger.ErrorContext(ctx, "Database write error",
+			slog.String("component", "product_repository"),
+			slog.String("error", writeErr.Error()),
+			slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
+			slog.String("product_name", name),
+			slog.String("operation", "soft_delete"))
+
+		appErr = dbError(writeErr, "Failed to write updated product data")
+		metric.IncrementErrorCount(ctx, appErr.Code, "soft_delete", "repository")
+		return models.Product{}, appErr
+	}
+
+	// A deleted product no longer has stock worth reporting or counts toward the catalog
+	metric.RemoveProductStockLevel(name)
+	metric.AddCatalogSize(-1)
+
+	r.logger.InfoContext(ctx, "Product soft-deleted",
+		slog.String("component", "product_repository"),
+		slog.String("product_name", name),
+		slog.String("operation", "soft_delete"),
+		slog.String("status", "success"))
+
+	return product, nil
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// errExportAborted wraps an error returned by the caller's export callback so it
+// is not reported as a database failure.
+type errExportAborted struct {
+	err error
+}
+
+func (e errExportAborted) Error() string { return e.err.Error() }
+func (e errExportAborted) Unwrap() error { return e.err }
+
+// Export calls fn with every product in the data file, soft-deleted ones included,
+// in file order. Products are decoded one at a time, so the catalog is never held
+// in memory as a whole. Blank categories are reported under the default category.
+// An error returned by fn stops the export and is returned as an internal
+// processing error.
+func (r *productRepository) Export(ctx context.Context, fn func(models.Product) error) (appErr *apierrors.AppError) {
+	newCtx, span := commontrace.StartSpan(ctx, "product_repository", "export")
+	ctx = newCtx
+	exported := 0
+	defer func() {
+		span.SetAttributes(attribute.Int("products.returned.count", exported))
+		var telemetryErr error
+		if appErr != nil {
+			telemetryErr = appErr
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		appErr = simAppErr
+		return appErr
+	}
+
+	r.logger.InfoContext(ctx, "Streaming product catalog from database",
+		slog.String("component", "product_repository"),
+		slog.String("operation", "export"))
+
+	err := r.database.Stream(ctx, func(key string, value json.RawMessage) error {
+		var product models.Product
+		if err := json.Unmarshal(value, &product); err != nil {
+			return err
+		}
+		if strings.TrimSpace(product.Category) == "" {
+			product.Category = r.defaultCategory
+		}
+		if err := fn(product); err != nil {
+			return errExportAborted{err}
+		}
+		exported++
+		return nil
+	})
+
+	var aborted errExportAborted
+	switch {
+	case err == nil:
+		return nil
+	case os.IsNotExist(err):
+		return nil
+	case errors.As(err, &aborted):
+		appErr = apierrors.NewApplicationError(apierrors.ErrCodeInternalProcessing, "Product export was interrupted", aborted.err)
+		return appErr
+	default:
+		r.logger.ErrorContext(ctx, "Database access error",
+			slog.String("error", err.Error()),
+			slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
+			slog.String("operation", "export"))
+		appErr = dbError(err, "Failed to read product data from database")
+		return appErr
+	}
+}
@@ -6,18 +6,17 @@ import (
 	"os"
 
 	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
 	"github.com/narender/common/telemetry/metric"
 	commontrace "github.com/narender/common/telemetry/trace"
-	"github.com/narender/product-service/src/models" // Corrected path
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 
 	"go.opentelemetry.io/otel/trace"
 
 	apierrors "github.com/narender/common/apierrors"
 )
 
-func (r *productRepository) GetAll(ctx context.Context) (productsSlice []models.Product, appErr *apierrors.AppError) {
+func (r *productRepository) GetAll(ctx context.Context, includeDeleted bool) (productsSlice []models.Product, appErr *apierrors.AppError) {
 	newCtx, span := commontrace.StartSpan(ctx, "product_repository", "get_all",
 		attribute.String("repository.operation", "GetAll"))
 	ctx = newCtx // Update ctx if StartSpan modifies it
@@ -43,7 +42,7 @@ func (r *productRepository) GetAll(ctx context.Context) (productsSlice []models.
 		slog.String("operation", "read_from_database"))
 
 	var productsMap map[string]models.Product
-	err := r.database.Read(ctx, &productsMap)
+	err := r.readProducts(ctx, &productsMap)
 	if err != nil {
 		if os.IsNotExist(err) {
 			r.logger.WarnContext(ctx, "No products found in database",
@@ -52,6 +51,9 @@ func (r *productRepository) GetAll(ctx context.Context) (productsSlice []models.
 				slog.String("error", err.Error()))
 
 			span.AddEvent("FileDatabase.Read indicated file not found, returning empty.", trace.WithAttributes(attribute.String("error.message", err.Error())))
+			metric.SetCatalogSize(0)
+			metric.RecordCatalogPrices(nil)
+			span.AddEvent("catalog.empty")
 			return []models.Product{}, nil
 		} else {
 			errMsg := "Failed to read product data from database"
@@ -60,44 +62,49 @@ func (r *productRepository) GetAll(ctx context.Context) (productsSlice []models.
 				slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
 				slog.String("operation", "get_all_products"))
 
-			if span != nil {
-				span.SetStatus(codes.Error, errMsg)
-			}
-
-			appErr = apierrors.NewApplicationError(
-				apierrors.ErrCodeDatabaseAccess,
-				errMsg,
-				err)
+			appErr = dbError(err, errMsg)
 
 			return nil, appErr
 		}
 	}
 
+	r.normalizeCategories(ctx, productsMap)
+	r.hideDeleted(ctx, productsMap, includeDeleted)
+
 	r.logger.DebugContext(ctx, "Converting database entity map to product array structure",
 		slog.String("component", "product_repository"),
 		slog.Int("product_count", len(productsMap)),
 		slog.String("operation", "entity_transformation"))
 
-	productsSlice = make([]models.Product, 0, len(productsMap))
-	for _, p := range productsMap {
-		productsSlice = append(productsSlice, p)
-		r.logger.DebugContext(ctx, "Processing individual product entity data",
-			slog.String("product_name", p.Name),
-			slog.String("product_category", p.Category),
-			slog.Float64("product_price", p.Price),
-			slog.Int("stock", p.Stock),
-			slog.String("component", "product_repository"))
-			slog.String("operation", "entity_processing")
-	}
-
-	// Update product stock levels for telemetry
-	for _, p := range productsSlice {
-		metric.UpdateProductStockLevels(ctx, p.Name, p.Category, int64(p.Stock))
-	}
+	// Build the slice and update product stock levels for telemetry
+	productsSlice = r.aggregateProducts(ctx, productsMap, nil, true)
 
 	productCount := len(productsSlice)
 	span.SetAttributes(attribute.Int("products.returned.count", productCount))
 
+	// Catalog gauges describe live products only, even when deleted ones are listed
+	prices := make([]float64, 0, productCount)
+	for _, p := range productsSlice {
+		if !p.Deleted {
+			prices = append(prices, p.Price.Float64())
+		}
+	}
+	if drift := metric.SetCatalogSize(int64(len(prices))); drift != 0 {
+		span.SetAttributes(attribute.Int64("catalog.size.drift", drift))
+		r.logger.WarnContext(ctx, "Catalog size counter drifted from data file, reconciled",
+			slog.String("component", "product_repository"),
+			slog.Int64("drift", drift),
+			slog.String("operation", "get_all_products"))
+	}
+	metric.RecordCatalogPrices(prices)
+	if len(prices) == 0 {
+		span.AddEvent("catalog.empty")
+		r.logger.WarnContext(ctx, "Product catalog is empty",
+			slog.String("component", "product_repository"),
+			slog.String("file_path", r.database.FilePath()),
+			slog.String("operation", "get_all_products"))
+	}
+
 	r.logger.InfoContext(ctx, "Repository layer successfully completed product catalog retrieval",
 		slog.Int("product_count", productCount),
 		slog.String("component", "product_repository"),
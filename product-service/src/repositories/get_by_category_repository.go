@@ -6,17 +6,16 @@ import (
 	"os"
 
 	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
 	commontrace "github.com/narender/common/telemetry/trace"
-	"github.com/narender/product-service/src/models" // Corrected path
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 
 	"go.opentelemetry.io/otel/trace"
 
 	apierrors "github.com/narender/common/apierrors"
 )
 
-func (r *productRepository) GetByCategory(ctx context.Context, category string) (filteredProducts []models.Product, appErr *apierrors.AppError) {
+func (r *productRepository) GetByCategory(ctx context.Context, category string, includeDeleted bool) (filteredProducts []models.Product, appErr *apierrors.AppError) {
 	categoryAttr := attribute.String("product.category", category)
 	newCtx, span := commontrace.StartSpan(ctx, "product_repository", "get_by_category", categoryAttr)
 	ctx = newCtx // Update ctx
@@ -44,7 +43,7 @@ func (r *productRepository) GetByCategory(ctx context.Context, category string)
 		slog.String("operation", "read_from_database"))
 
 	var productsMap map[string]models.Product
-	err := r.database.Read(ctx, &productsMap)
+	err := r.readProducts(ctx, &productsMap)
 	if err != nil {
 		if os.IsNotExist(err) {
 			r.logger.WarnContext(ctx, "No products found in database",
@@ -62,41 +61,30 @@ func (r *productRepository) GetByCategory(ctx context.Context, category string)
 				slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
 				slog.String("operation", "get_by_category"))
 
-			if span != nil {
-				span.SetStatus(codes.Error, errMsg)
-			}
-
-			appErr = apierrors.NewApplicationError(
-				apierrors.ErrCodeDatabaseAccess,
-				errMsg,
-				err)
+			appErr = dbError(err, errMsg)
 
 			return nil, appErr
 		}
 	}
 
+	r.normalizeCategories(ctx, productsMap)
+	scanned := len(productsMap)
+	r.hideDeleted(ctx, productsMap, includeDeleted)
+
 	r.logger.DebugContext(ctx, "Applying category filter to product inventory data",
 		slog.String("category", category),
 		slog.String("component", "product_repository"),
 		slog.Int("total_products", len(productsMap)),
 		slog.String("operation", "category_match"))
 
-	filteredProducts = make([]models.Product, 0)
-	for _, p := range productsMap {
-		if p.Category == category {
-			filteredProducts = append(filteredProducts, p)
-			r.logger.DebugContext(ctx, "Product entity matches requested category criteria",
-				slog.String("product_name", p.Name),
-				slog.Int("stock", p.Stock),
-				slog.String("product_category", p.Category),
-				slog.Float64("product_price", p.Price),
-				slog.String("component", "product_repository"),
-				slog.String("operation", "category_filtering"))
-		}
-	}
+	filteredProducts = r.aggregateProducts(ctx, productsMap, func(p models.Product) bool {
+		return p.Category == category
+	}, false)
 
 	productCount := len(filteredProducts)
-	span.SetAttributes(attribute.Int("products.returned.count", productCount))
+	span.SetAttributes(
+		attribute.Int("products.scanned.count", scanned),
+		attribute.Int("products.returned.count", productCount))
 
 	r.logger.InfoContext(ctx, "Repository layer successfully completed category-filtered product retrieval",
 		slog.String("category", category),
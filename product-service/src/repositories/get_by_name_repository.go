@@ -6,10 +6,9 @@ import (
 	"log/slog"
 
 	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
 	commontrace "github.com/narender/common/telemetry/trace"
-	"github.com/narender/product-service/src/models"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 
 	apierrors "github.com/narender/common/apierrors"
 )
@@ -44,7 +43,7 @@ func (r *productRepository) GetByName(ctx context.Context, name string) (product
 		slog.String("product_name", name))
 
 	var productsMap map[string]models.Product
-	err := r.database.Read(ctx, &productsMap)
+	err := r.readProducts(ctx, &productsMap)
 	if err != nil {
 		errMsg := "Failed to read product data from database"
 		r.logger.ErrorContext(ctx, "Database access error during product lookup",
@@ -55,11 +54,7 @@ func (r *productRepository) GetByName(ctx context.Context, name string) (product
 			slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
 			slog.String("operation", "get_by_name"))
 
-		if span != nil {
-			span.SetStatus(codes.Error, errMsg)
-		}
-
-		appErr = apierrors.NewApplicationError(apierrors.ErrCodeDatabaseAccess, errMsg, err)
+		appErr = dbError(err, errMsg)
 		return models.Product{}, appErr
 	}
 
@@ -68,6 +63,11 @@ func (r *productRepository) GetByName(ctx context.Context, name string) (product
 		slog.String("operation", "search_for_product"),
 		slog.String("product_name", name))
 
+	r.normalizeCategories(ctx, productsMap)
+	// Scanned counts the whole catalog, soft-deleted products included
+	span.SetAttributes(attribute.Int("products.scanned.count", len(productsMap)))
+	r.hideDeleted(ctx, productsMap, false)
+
 	product, exists := productsMap[name]
 	if !exists {
 		errMsg := fmt.Sprintf("Product with name '%s' not found", name)
@@ -79,15 +79,15 @@ func (r *productRepository) GetByName(ctx context.Context, name string) (product
 			slog.String("error_code", apierrors.ErrCodeProductNotFound),
 			slog.String("operation", "get_by_name"))
 
-		if span != nil {
-			span.SetStatus(codes.Error, errMsg)
-		}
+		suggestions := suggestProductNames(name, productsMap)
+		span.SetAttributes(attribute.Int("suggestions.count", len(suggestions)))
 
 		appErr = apierrors.NewBusinessError(
 			apierrors.ErrCodeProductNotFound,
 			errMsg,
 			nil,
-		).WithContext("operation", "get_by_name")
+		).WithContext("operation", "get_by_name").
+			WithDetail("suggestions", suggestions)
 
 		return models.Product{}, appErr
 	}
@@ -105,7 +105,7 @@ func (r *productRepository) GetByName(ctx context.Context, name string) (product
 		slog.String("operation", "retrieve_product_details"),
 		slog.String("product_name", product.Name),
 		slog.Int("stock", product.Stock),
-		slog.Float64("price", product.Price))
+		slog.Float64("price", product.Price.Float64()))
 
 	return product, appErr // appErr is nil here if successful
 }
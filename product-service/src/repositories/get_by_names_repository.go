@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// GetByNames looks up several products with a single read of the data file. Found
+// products are returned in request order; names with no product go to notFound.
+func (r *productRepository) GetByNames(ctx context.Context, names []string) (found []models.Product, notFound []string, appErr *apierrors.AppError) {
+	newCtx, span := commontrace.StartSpan(ctx, "product_repository", "get_by_names",
+		attribute.Int("products.requested.count", len(names)))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if appErr != nil {
+			telemetryErr = appErr
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		appErr = simAppErr
+		return nil, nil, appErr
+	}
+
+	var productsMap map[string]models.Product
+	err := r.readProducts(ctx, &productsMap)
+	if err != nil && !os.IsNotExist(err) {
+		r.logger.ErrorContext(ctx, "Database access error",
+			slog.String("component", "product_repository"),
+			slog.String("error", err.Error()),
+			slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
+			slog.String("operation", "get_by_names"))
+
+		appErr = dbError(err, "Failed to read product data from database")
+		return nil, nil, appErr
+	}
+
+	r.normalizeCategories(ctx, productsMap)
+	// Scanned counts the whole catalog, soft-deleted products included
+	span.SetAttributes(attribute.Int("products.scanned.count", len(productsMap)))
+	r.hideDeleted(ctx, productsMap, false)
+
+	found = make([]models.Product, 0, len(names))
+	notFound = make([]string, 0)
+	for _, name := range names {
+		if product, ok := productsMap[name]; ok {
+			found = append(found, product)
+		} else {
+			notFound = append(notFound, name)
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("products.returned.count", len(found)),
+		attribute.Int("products.not_found.count", len(notFound)))
+	r.logger.DebugContext(ctx, "Products looked up by name",
+		slog.String("component", "product_repository"),
+		slog.Int("found_count", len(found)),
+		slog.Int("not_found_count", len(notFound)),
+		slog.String("operation", "get_by_names"))
+
+	return found, notFound, nil
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/narender/common/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// normalizeCategories replaces blank categories with the configured default so
+// uncategorized products surface consistently in filters and metrics. It only
+// changes the in-memory copy and records the number of normalized products on the span.
+func (r *productRepository) normalizeCategories(ctx context.Context, productsMap map[string]models.Product) {
+	normalized := 0
+	for key, p := range productsMap {
+		if strings.TrimSpace(p.Category) == "" {
+			p.Category = r.defaultCategory
+			productsMap[key] = p
+			normalized++
+		}
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("products.normalized.count", normalized))
+}
+
+// hideDeleted removes soft-deleted products from a freshly read productsMap unless
+// includeDeleted is set, recording how many were hidden on the span. Never call it
+// on a map that will be written back.
+func (r *productRepository) hideDeleted(ctx context.Context, productsMap map[string]models.Product, includeDeleted bool) {
+	hidden := 0
+	if !includeDeleted {
+		for key, p := range productsMap {
+			if p.Deleted {
+				delete(productsMap, key)
+				hidden++
+			}
+		}
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("products.deleted.hidden", hidden))
+}
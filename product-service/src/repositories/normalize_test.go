@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/narender/common/config"
+	"github.com/narender/common/globals"
+)
+
+const uncategorizedCatalog = `{
+  "Coffee Mug": {"name": "Coffee Mug", "price": 9.5, "stock": 20, "category": "Kitchenware"},
+  "Gift Card": {"name": "Gift Card", "price": 25, "stock": 100, "category": ""},
+  "Mystery Box": {"name": "Mystery Box", "price": 5, "stock": 3, "category": "  "},
+  "Sticker": {"name": "Sticker", "price": 1, "stock": 50}
+}`
+
+func TestBlankCategoriesSurfaceUnderTheDefault(t *testing.T) {
+	tests := []struct {
+		name            string
+		defaultCategory string
+	}{
+		{name: "built-in default", defaultCategory: "uncategorized"},
+		{name: "configured default", defaultCategory: "misc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t, uncategorizedCatalog, func(c *config.Config) {
+				c.DEFAULT_CATEGORY = tt.defaultCategory
+			})
+			ctx := context.Background()
+			before, err := os.ReadFile(globals.Cfg().PRODUCT_DATA_FILE_PATH)
+			if err != nil {
+				t.Fatalf("read data file: %v", err)
+			}
+
+			all, appErr := repo.GetAll(ctx, false)
+			if appErr != nil {
+				t.Fatalf("GetAll: %v", appErr)
+			}
+			categories := make(map[string]string, len(all))
+			for _, p := range all {
+				categories[p.Name] = p.Category
+			}
+			want := map[string]string{
+				"Coffee Mug":  "Kitchenware",
+				"Gift Card":   tt.defaultCategory,
+				"Mystery Box": tt.defaultCategory,
+				"Sticker":     tt.defaultCategory,
+			}
+			for name, category := range want {
+				if categories[name] != category {
+					t.Errorf("GetAll: %s category = %q, want %q", name, categories[name], category)
+				}
+			}
+
+			inDefault, appErr := repo.GetByCategory(ctx, tt.defaultCategory, false)
+			if appErr != nil {
+				t.Fatalf("GetByCategory: %v", appErr)
+			}
+			if len(inDefault) != 3 {
+				t.Errorf("GetByCategory(%q) returned %d products, want 3", tt.defaultCategory, len(inDefault))
+			}
+			if blank, _ := repo.GetByCategory(ctx, "", false); len(blank) != 0 {
+				t.Errorf("GetByCategory(\"\") returned %d products, want none", len(blank))
+			}
+
+			product, appErr := repo.GetByName(ctx, "Gift Card")
+			if appErr != nil {
+				t.Fatalf("GetByName: %v", appErr)
+			}
+			if product.Category != tt.defaultCategory {
+				t.Errorf("GetByName: category = %q, want %q", product.Category, tt.defaultCategory)
+			}
+
+			// Normalization is applied on read only; the data file keeps blank categories
+			after, err := os.ReadFile(globals.Cfg().PRODUCT_DATA_FILE_PATH)
+			if err != nil {
+				t.Fatalf("read data file: %v", err)
+			}
+			if string(after) != string(before) {
+				t.Errorf("data file changed by reads:\n%s", after)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry/metric"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// Purge removes the named product from the data file for good. Only soft-deleted
+// products can be purged, so a single call cannot destroy a live product.
+func (r *productRepository) Purge(ctx context.Context, name string) (appErr *apierrors.AppError) {
+	newCtx, span := commontrace.StartSpan(ctx, "product_repository", "purge",
+		attribute.String(metric.AttrProductName, name))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if appErr != nil {
+			telemetryErr = appErr
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		appErr = simAppErr
+		return appErr
+	}
+
+	// Hold the lock across read, modify and write so concurrent updates cannot interleave
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	var productsMap map[string]models.Product
+	if err := r.readProducts(ctx, &productsMap); err != nil {
+		r.logger.ErrorContext(ctx, "Database access error",
+			slog.String("component", "product_repository"),
+			slog.String("error", err.Error()),
+			slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
+			slog.String("operation", "purge"))
+
+		appErr = dbError(err, "Failed to read product data from database")
+		metric.IncrementErrorCount(ctx, appErr.Code, "purge", "repository")
+		return appErr
+	}
+
+	product, ok := productsMap[name]
+	if !ok {
+		r.logger.WarnContext(ctx, "Product not found",
+			slog.String("component", "product_repository"),
+			slog.String("product_name", name),
+			slog.String("error_code", apierrors.ErrCodeProductNotFound),
+			slog.String("operation", "purge"))
+
+		appErr = apierrors.NewBusinessError(
+			apierrors.ErrCodeProductNotFound,
+			fmt.Sprintf("Product with name '%s' not found for purge", name),
+			nil)
+		metric.IncrementErrorCount(ctx, apierrors.ErrCodeProductNotFound, "purge", "repository")
+		return appErr
+	}
+	if !product.Deleted {
+		r.logger.WarnContext(ctx, "Refusing to purge a product that is not soft-deleted",
+			slog.String("component", "product_repository"),
+			slog.String("product_name", name),
+			slog.String("error_code", apierrors.ErrCodeConflict),
+			slog.String("operation", "purge"))
+
+		appErr = apierrors.NewBusinessError(
+			apierrors.ErrCodeConflict,
+			fmt.Sprintf("Product '%s' must be deleted before it can be purged", name),
+			nil)
+		metric.IncrementErrorCount(ctx, apierrors.ErrCodeConflict, "purge", "repository")
+		return appErr
+	}
+
+	delete(productsMap, name)
+	if writeErr := r.writeProducts(ctx, productsMap); writeErr != nil {
+		r.logger.ErrorContext(ctx, "Database write error",
+			slog.String("component", "product_repository"),
+			slog.String("error", writeErr.Error()),
+			slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
+			slog.String("product_name", name),
+			slog.String("operation", "purge"))
+
+		appErr = dbError(writeErr, "Failed to write updated product data")
+		metric.IncrementErrorCount(ctx, appErr.Code, "purge", "repository")
+		return appErr
+	}
+
+	r.logger.InfoContext(ctx, "Product purged",
+		slog.String("component", "product_repository"),
+		slog.String("product_name", name),
+		slog.String("operation", "purge"),
+		slog.String("status", "success"))
+
+	return nil
+}
@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"log/slog"
+	"sync"
+	"time"
 
 	db "github.com/narender/common/db"
 	"github.com/narender/common/globals"
@@ -10,27 +12,41 @@ import (
 	"context"
 
 	apierrors "github.com/narender/common/apierrors"
-	"github.com/narender/product-service/src/models"
+	"github.com/narender/common/models"
 )
 
 // Updated Interface
 type ProductRepository interface {
-	GetAll(ctx context.Context) ([]models.Product, *apierrors.AppError)
+	GetAll(ctx context.Context, includeDeleted bool) ([]models.Product, *apierrors.AppError)
 	GetByName(ctx context.Context, name string) (models.Product, *apierrors.AppError)
-	UpdateStock(ctx context.Context, name string, newStock int) *apierrors.AppError
-	GetByCategory(ctx context.Context, category string) ([]models.Product, *apierrors.AppError)
+	UpdateStock(ctx context.Context, name string, newStock int, expectedStock *int) *apierrors.AppError
+	GetByCategory(ctx context.Context, category string, includeDeleted bool) ([]models.Product, *apierrors.AppError)
+	Search(ctx context.Context, query string, inDescription, includeDeleted bool) ([]models.SearchResult, *apierrors.AppError)
+	UpdateProduct(ctx context.Context, name string, update models.ProductUpdate) (models.Product, []string, *apierrors.AppError)
+	GetByNames(ctx context.Context, names []string) (found []models.Product, notFound []string, appErr *apierrors.AppError)
+	SoftDelete(ctx context.Context, name string) (models.Product, *apierrors.AppError)
+	Purge(ctx context.Context, name string) *apierrors.AppError
+	Export(ctx context.Context, fn func(models.Product) error) *apierrors.AppError
 }
 
 type productRepository struct {
-	database *db.FileDatabase
-	logger   *slog.Logger
+	database           *db.FileDatabase
+	logger             *slog.Logger
+	aggregationWorkers int
+	defaultCategory    string
+	dbOpTimeout        time.Duration
+	// writeMu serializes read-modify-write cycles on the data file
+	writeMu sync.Mutex
 }
 
 // NewProductRepository creates a new repository instance loading data from a JSON file.
 func NewProductRepository() ProductRepository {
 	repo := &productRepository{
-		database: db.NewFileDatabase(),
-		logger:   globals.Logger(),
+		database:           db.NewFileDatabase(),
+		logger:             globals.Logger(),
+		aggregationWorkers: globals.Cfg().AGGREGATION_WORKERS,
+		defaultCategory:    globals.Cfg().DEFAULT_CATEGORY,
+		dbOpTimeout:        globals.Cfg().DB_OP_TIMEOUT,
 	}
 	return repo
 }
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/narender/common/config"
+	"github.com/narender/common/globals"
+)
+
+// newTestRepository returns a repository backed by a temporary data file holding
+// catalog, a JSON object of products keyed by name. overrides are applied to the
+// configuration last.
+func newTestRepository(tb testing.TB, catalog string, overrides ...func(*config.Config)) ProductRepository {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "data.json")
+	if err := os.WriteFile(path, []byte(catalog), 0o644); err != nil {
+		tb.Fatalf("write catalog: %v", err)
+	}
+	globals.InitForTest(tb, append([]func(*config.Config){func(c *config.Config) {
+		c.PRODUCT_DATA_FILE_PATH = path
+	}}, overrides...)...)
+	return NewProductRepository()
+}
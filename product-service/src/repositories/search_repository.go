@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// highlightContext is the number of characters kept on each side of a match.
+const highlightContext = 30
+
+func (r *productRepository) Search(ctx context.Context, query string, inDescription, includeDeleted bool) (results []models.SearchResult, appErr *apierrors.AppError) {
+	newCtx, span := commontrace.StartSpan(ctx, "product_repository", "search",
+		attribute.String("search.query", query),
+		attribute.Bool("search.in_description", inDescription))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if appErr != nil {
+			telemetryErr = appErr
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		appErr = simAppErr
+		return nil, appErr
+	}
+
+	r.logger.InfoContext(ctx, "Searching product catalog",
+		slog.String("component", "product_repository"),
+		slog.String("query", query),
+		slog.Bool("in_description", inDescription),
+		slog.String("operation", "search"))
+
+	var productsMap map[string]models.Product
+	err := r.readProducts(ctx, &productsMap)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []models.SearchResult{}, nil
+		}
+		r.logger.ErrorContext(ctx, "Database access error",
+			slog.String("error", err.Error()),
+			slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
+			slog.String("operation", "search"))
+
+		appErr = dbError(err, "Failed to read product data from database")
+		return nil, appErr
+	}
+
+	r.normalizeCategories(ctx, productsMap)
+	scanned := len(productsMap)
+	r.hideDeleted(ctx, productsMap, includeDeleted)
+
+	results = make([]models.SearchResult, 0)
+	for _, p := range productsMap {
+		if highlight, ok := highlightMatch(p.Name, query); ok {
+			results = append(results, models.SearchResult{Product: p, MatchedField: "name", Highlight: highlight})
+			continue
+		}
+		if inDescription {
+			if highlight, ok := highlightMatch(p.Description, query); ok {
+				results = append(results, models.SearchResult{Product: p, MatchedField: "description", Highlight: highlight})
+			}
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("products.scanned.count", scanned),
+		attribute.Int("products.returned.count", len(results)))
+
+	r.logger.InfoContext(ctx, "Product search completed",
+		slog.String("component", "product_repository"),
+		slog.String("query", query),
+		slog.Int("result_count", len(results)),
+		slog.String("operation", "search"),
+		slog.String("status", "success"))
+
+	return results, nil
+}
+
+// highlightMatch finds query in text case-insensitively and returns the match
+// wrapped in <mark> tags with up to highlightContext characters on each side.
+// The match is located in text itself rather than in a lowercased copy, whose
+// byte offsets can differ, so every slice falls on a rune boundary.
+func highlightMatch(text, query string) (string, bool) {
+	if query == "" {
+		return "", false
+	}
+	idx, end, ok := indexFold(text, query)
+	if !ok {
+		return "", false
+	}
+
+	start := idx
+	for i := 0; i < highlightContext && start > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(text[:start])
+		start -= size
+	}
+	stop := end
+	for i := 0; i < highlightContext && stop < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[stop:])
+		stop += size
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("...")
+	}
+	b.WriteString(text[start:idx])
+	b.WriteString("<mark>")
+	b.WriteString(text[idx:end])
+	b.WriteString("</mark>")
+	b.WriteString(text[end:stop])
+	if stop < len(text) {
+		b.WriteString("...")
+	}
+	return b.String(), true
+}
+
+// indexFold returns the byte range in text of the first case-insensitive match
+// of query, comparing rune by rune under Unicode case folding.
+func indexFold(text, query string) (start, end int, ok bool) {
+	for start = range text {
+		if n, matched := prefixFold(text[start:], query); matched {
+			return start, start + n, true
+		}
+	}
+	return 0, 0, false
+}
+
+// prefixFold reports whether s begins with prefix under case folding, and the
+// length in bytes of the matching part of s.
+func prefixFold(s, prefix string) (int, bool) {
+	n := 0
+	for _, want := range prefix {
+		if n >= len(s) {
+			return 0, false
+		}
+		got, size := utf8.DecodeRuneInString(s[n:])
+		if got != want && !strings.EqualFold(string(got), string(want)) {
+			return 0, false
+		}
+		n += size
+	}
+	return n, true
+}
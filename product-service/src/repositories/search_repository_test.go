@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestHighlightMatch(t *testing.T) {
+	long := strings.Repeat("é", 40) + "needle" + strings.Repeat("ü", 40)
+
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+		found bool
+	}{
+		{name: "ascii", text: "High-speed blender", query: "BLENDER", want: "High-speed <mark>blender</mark>", found: true},
+		{name: "no match", text: "Coffee Mug", query: "tea", found: false},
+		{name: "empty query", text: "Coffee Mug", query: "", found: false},
+		{name: "multibyte before match", text: "Crème brûlée torch", query: "torch", want: "Crème brûlée <mark>torch</mark>", found: true},
+		{name: "multibyte match", text: "Großes Ärger", query: "ärger", want: "Großes <mark>Ärger</mark>", found: true},
+		// Lowercasing "İ" grows it from two bytes to three, which shifted offsets taken from a lowercased copy
+		{name: "lowercase changes length", text: "İstanbul kebab", query: "kebab", want: "İstanbul <mark>kebab</mark>", found: true},
+		{name: "kelvin sign folds to k", text: "Kelvin K sign", query: "k sign", want: "Kelvin <mark>K sign</mark>", found: true},
+		{
+			name:  "context trimmed on rune boundaries",
+			text:  long,
+			query: "NEEDLE",
+			want:  "..." + strings.Repeat("é", highlightContext) + "<mark>needle</mark>" + strings.Repeat("ü", highlightContext) + "...",
+			found: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := highlightMatch(tt.text, tt.query)
+			if found != tt.found {
+				t.Fatalf("highlightMatch(%q, %q) found = %v, want %v", tt.text, tt.query, found, tt.found)
+			}
+			if got != tt.want {
+				t.Errorf("highlightMatch(%q, %q) = %q, want %q", tt.text, tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+const searchTestCatalog = `{
+  "Blender Pro": {"name": "Blender Pro", "description": "High-speed blender for smoothies", "price": 74.99, "stock": 30, "category": "Kitchenware"},
+  "Crème Brûlée Torch": {"name": "Crème Brûlée Torch", "description": "Caramelizes crème brûlée in seconds", "price": 24.5, "stock": 12, "category": "Kitchenware"},
+  "Reading Lamp": {"name": "Reading Lamp", "description": "Warm light for reading Ελληνικά books", "price": 19.99, "stock": 8, "category": "Furniture"}
+}`
+
+func TestSearchInDescription(t *testing.T) {
+	repo := newTestRepository(t, searchTestCatalog)
+
+	tests := []struct {
+		name          string
+		query         string
+		inDescription bool
+		wantProduct   string
+		wantField     string
+		wantHighlight string
+	}{
+		{
+			name:          "name match wins over description",
+			query:         "blender",
+			inDescription: true,
+			wantProduct:   "Blender Pro",
+			wantField:     "name",
+			wantHighlight: "<mark>Blender</mark> Pro",
+		},
+		{
+			name:          "description match",
+			query:         "SMOOTHIES",
+			inDescription: true,
+			wantProduct:   "Blender Pro",
+			wantField:     "description",
+			wantHighlight: "High-speed blender for <mark>smoothies</mark>",
+		},
+		{
+			name:          "description match after multibyte text",
+			query:         "seconds",
+			inDescription: true,
+			wantProduct:   "Crème Brûlée Torch",
+			wantField:     "description",
+			wantHighlight: "Caramelizes crème brûlée in <mark>seconds</mark>",
+		},
+		{
+			name:          "multibyte query in description",
+			query:         "ελληνικά",
+			inDescription: true,
+			wantProduct:   "Reading Lamp",
+			wantField:     "description",
+			wantHighlight: "Warm light for reading <mark>Ελληνικά</mark> books",
+		},
+		{
+			name:          "description ignored unless requested",
+			query:         "smoothies",
+			inDescription: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, appErr := repo.Search(context.Background(), tt.query, tt.inDescription, false)
+			if appErr != nil {
+				t.Fatalf("Search returned error: %v", appErr)
+			}
+			if tt.wantProduct == "" {
+				if len(results) != 0 {
+					t.Fatalf("Search(%q) returned %d results, want none", tt.query, len(results))
+				}
+				return
+			}
+			if len(results) != 1 {
+				t.Fatalf("Search(%q) returned %d results, want 1", tt.query, len(results))
+			}
+			got := results[0]
+			if got.Product.Name != tt.wantProduct {
+				t.Errorf("product = %q, want %q", got.Product.Name, tt.wantProduct)
+			}
+			if got.MatchedField != tt.wantField {
+				t.Errorf("matched field = %q, want %q", got.MatchedField, tt.wantField)
+			}
+			if got.Highlight != tt.wantHighlight {
+				t.Errorf("highlight = %q, want %q", got.Highlight, tt.wantHighlight)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/narender/common/models"
+)
+
+const (
+	// Most names returned as suggestions for a missing product
+	maxNameSuggestions = 3
+	// Catalog names compared per lookup, bounding the cost on large catalogs
+	maxSuggestionCandidates = 5000
+)
+
+// suggestProductNames returns up to maxNameSuggestions catalog names closest to name
+// by case-insensitive edit distance, nearest first. Names further than a third of
+// the query length (at least 2 edits) away are not suggested.
+func suggestProductNames(name string, productsMap map[string]models.Product) []string {
+	query := strings.ToLower(strings.TrimSpace(name))
+	maxDistance := max(2, len([]rune(query))/3)
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	scanned := 0
+	for productName := range productsMap {
+		if scanned == maxSuggestionCandidates {
+			break
+		}
+		scanned++
+		if d := editDistance(query, strings.ToLower(productName), maxDistance); d <= maxDistance {
+			candidates = append(candidates, candidate{name: productName, distance: d})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	suggestions := make([]string, 0, maxNameSuggestions)
+	for i := 0; i < len(candidates) && i < maxNameSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].name)
+	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between a and b, or limit+1 as
+// soon as it is known to exceed limit.
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > limit || -diff > limit {
+		return limit + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
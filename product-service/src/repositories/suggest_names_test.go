@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/narender/common/models"
+)
+
+func TestSuggestProductNames(t *testing.T) {
+	catalog := make(map[string]models.Product)
+	for _, name := range []string{"Blender Pro", "Coffee Mug", "Coffee Maker", "Tea Mug", "Reading Lamp", "Desk Lamp"} {
+		catalog[name] = models.Product{Name: name}
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "Cofee Mug", want: []string{"Coffee Mug"}},
+		{name: "coffee mug", want: []string{"Coffee Mug"}},
+		{name: "  Blendr Pro ", want: []string{"Blender Pro"}},
+		{name: "Desk Lmp", want: []string{"Desk Lamp"}},
+		// Nearest first
+		{name: "Coffee Mag", want: []string{"Coffee Mug", "Coffee Maker"}},
+		{name: "Teh Mug", want: []string{"Tea Mug"}},
+		{name: "Vacuum Cleaner", want: []string{}},
+		{name: "", want: []string{}},
+	}
+	for _, tt := range tests {
+		if got := suggestProductNames(tt.name, catalog); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("suggestProductNames(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestProductNamesReturnsTheClosestThree(t *testing.T) {
+	catalog := make(map[string]models.Product)
+	for _, name := range []string{"Widget A", "Widget B", "Widget C", "Widget D", "Widget AB"} {
+		catalog[name] = models.Product{Name: name}
+	}
+
+	got := suggestProductNames("Widget", catalog)
+	want := []string{"Widget A", "Widget B", "Widget C"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("suggestProductNames(%q) = %q, want %q", "Widget", got, want)
+	}
+}
+
+func TestSuggestProductNamesIsBoundedOnLargeCatalogs(t *testing.T) {
+	catalog := make(map[string]models.Product, 2*maxSuggestionCandidates)
+	for i := 0; i < 2*maxSuggestionCandidates; i++ {
+		name := fmt.Sprintf("Product %05d", i)
+		catalog[name] = models.Product{Name: name}
+	}
+
+	got := suggestProductNames("Product 0000", catalog)
+	if len(got) == 0 || len(got) > maxNameSuggestions {
+		t.Errorf("got %d suggestions, want between 1 and %d", len(got), maxNameSuggestions)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{a: "kitten", b: "sitting", limit: 5, want: 3},
+		{a: "mug", b: "mug", limit: 2, want: 0},
+		{a: "", b: "abc", limit: 5, want: 3},
+		{a: "café", b: "cafe", limit: 2, want: 1},
+		// Past the limit the result is only known to be limit+1
+		{a: "kitten", b: "sitting", limit: 2, want: 3},
+		{a: "a", b: "abcdef", limit: 2, want: 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.limit); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
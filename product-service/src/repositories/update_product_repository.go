@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry/metric"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// UpdateProduct applies the non-nil fields of update to the named product and returns
+// the updated product along with the names of the fields whose values changed.
+func (r *productRepository) UpdateProduct(ctx context.Context, name string, update models.ProductUpdate) (product models.Product, changed []string, appErr *apierrors.AppError) {
+	newCtx, span := commontrace.StartSpan(ctx, "product_repository", "update_product",
+		attribute.String(metric.AttrProductName, name))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if appErr != nil {
+			telemetryErr = appErr
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		appErr = simAppErr
+		return models.Product{}, nil, appErr
+	}
+
+	// Hold the lock across read, modify and write so concurrent updates cannot interleave
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	var productsMap map[string]models.Product
+	if err := r.readProducts(ctx, &productsMap); err != nil {
+		r.logger.ErrorContext(ctx, "Database access error",
+			slog.String("component", "product_repository"),
+			slog.String("error", err.Error()),
+			slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
+			slog.String("operation", "update_product"))
+
+		appErr = dbError(err, "Failed to read product data from database")
+		metric.IncrementErrorCount(ctx, appErr.Code, "update_product", "repository")
+		return models.Product{}, nil, appErr
+	}
+
+	product, ok := productsMap[name]
+	if !ok || product.Deleted {
+		r.logger.WarnContext(ctx, "Product not found",
+			slog.String("component", "product_repository"),
+			slog.String("product_name", name),
+			slog.String("error_code", apierrors.ErrCodeProductNotFound),
+			slog.String("operation", "update_product"))
+
+		appErr = apierrors.NewBusinessError(
+			apierrors.ErrCodeProductNotFound,
+			fmt.Sprintf("Product with name '%s' not found for update", name),
+			nil)
+		metric.IncrementErrorCount(ctx, apierrors.ErrCodeProductNotFound, "update_product", "repository")
+		return models.Product{}, nil, appErr
+	}
+
+	oldStock := product.Stock
+	changed = make([]string, 0, 4)
+	if update.Price != nil && *update.Price != product.Price {
+		product.Price = *update.Price
+		changed = append(changed, "price")
+	}
+	if update.Description != nil && *update.Description != product.Description {
+		product.Description = *update.Description
+		changed = append(changed, "description")
+	}
+	if update.Category != nil && *update.Category != product.Category {
+		product.Category = *update.Category
+		changed = append(changed, "category")
+	}
+	if update.Stock != nil && *update.Stock != product.Stock {
+		product.Stock = *update.Stock
+		changed = append(changed, models.JSONFieldStock)
+	}
+	span.SetAttributes(attribute.StringSlice("product.fields_changed", changed))
+
+	if len(changed) == 0 {
+		return product, changed, nil
+	}
+
+	productsMap[name] = product
+	if writeErr := r.writeProducts(ctx, productsMap); writeErr != nil {
+		r.logger.ErrorContext(ctx, "Database write error",
+			slog.String("component", "product_repository"),
+			slog.String("error", writeErr.Error()),
+			slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
+			slog.String("product_name", name),
+			slog.String("operation", "update_product"))
+
+		appErr = dbError(writeErr, "Failed to write updated product data")
+		metric.IncrementErrorCount(ctx, appErr.Code, "update_product", "repository")
+		return models.Product{}, nil, appErr
+	}
+
+	// Keep the stock gauge and low-stock reporting in step, including a changed category
+	r.recordStockLevel(ctx, span, product, oldStock)
+
+	r.logger.InfoContext(ctx, "Product updated",
+		slog.String("component", "product_repository"),
+		slog.String("product_name", name),
+		slog.Any("fields_changed", changed),
+		slog.String("operation", "update_product"),
+		slog.String("status", "success"))
+
+	return product, changed, nil
+}
@@ -4,20 +4,22 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
 	"github.com/narender/common/telemetry/metric"
 	commontrace "github.com/narender/common/telemetry/trace"
-	"github.com/narender/product-service/src/models" // Corrected path
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 
 	"go.opentelemetry.io/otel/trace"
 
 	apierrors "github.com/narender/common/apierrors"
 )
 
-func (r *productRepository) UpdateStock(ctx context.Context, name string, newStock int) (appErr *apierrors.AppError) {
+// UpdateStock sets a product's stock. When expectedStock is non-nil the update only
+// applies if the current stock still equals it; otherwise ErrCodeConflict is returned.
+func (r *productRepository) UpdateStock(ctx context.Context, name string, newStock int, expectedStock *int) (appErr *apierrors.AppError) {
 	productNameAttr := attribute.String(metric.AttrProductName, name)
 	newStockAttr := attribute.Int("product.new_stock", newStock)
 	attrs := []attribute.KeyValue{productNameAttr, newStockAttr}
@@ -35,6 +37,10 @@ func (r *productRepository) UpdateStock(ctx context.Context, name string, newSto
 		return simAppErr
 	}
 
+	// Hold the lock across read, compare and write so concurrent updates cannot interleave
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
 	r.logger.InfoContext(ctx, "Updating product stock",
 		slog.String("component", "product_repository"),
 		slog.String("product_name", name),
@@ -47,7 +53,7 @@ func (r *productRepository) UpdateStock(ctx context.Context, name string, newSto
 		slog.String("operation", "database_read"))
 
 	var productsMap map[string]models.Product
-	err := r.database.Read(ctx, &productsMap)
+	err := r.readProducts(ctx, &productsMap)
 	if err != nil {
 		errMsg := "Failed to read product data from database"
 		r.logger.ErrorContext(ctx, "Database access error",
@@ -56,15 +62,10 @@ func (r *productRepository) UpdateStock(ctx context.Context, name string, newSto
 			slog.String("error_code", apierrors.ErrCodeDatabaseAccess),
 			slog.String("operation", "update_stock"))
 
-		span.SetStatus(codes.Error, errMsg)
-
-		appErr = apierrors.NewApplicationError(
-			apierrors.ErrCodeDatabaseAccess,
-			errMsg,
-			err)
+		appErr = dbError(err, errMsg)
 
 		// Track error metrics
-		metric.IncrementErrorCount(ctx, apierrors.ErrCodeDatabaseAccess, "update_stock", "repository")
+		metric.IncrementErrorCount(ctx, appErr.Code, "update_stock", "repository")
 		return appErr
 	}
 
@@ -74,7 +75,7 @@ func (r *productRepository) UpdateStock(ctx context.Context, name string, newSto
 		slog.String("operation", "verify_product"))
 
 	product, ok := productsMap[name]
-	if !ok {
+	if !ok || product.Deleted {
 		errMsg := fmt.Sprintf("Product with name '%s' not found for stock update", name)
 		r.logger.WarnContext(ctx, "Product not found",
 			slog.String("component", "product_repository"),
@@ -83,7 +84,6 @@ func (r *productRepository) UpdateStock(ctx context.Context, name string, newSto
 			slog.String("operation", "update_stock"))
 
 		span.AddEvent("product_not_found_in_map_for_update", trace.WithAttributes(attrs...))
-		span.SetStatus(codes.Error, errMsg)
 
 		appErr = apierrors.NewBusinessError(
 			apierrors.ErrCodeProductNotFound,
@@ -96,10 +96,37 @@ func (r *productRepository) UpdateStock(ctx context.Context, name string, newSto
 	}
 
 	oldStock := product.Stock
+	if expectedStock != nil && *expectedStock != oldStock {
+		errMsg := fmt.Sprintf("Stock for product '%s' changed: expected %d, current %d", name, *expectedStock, oldStock)
+		r.logger.WarnContext(ctx, "Stock update rejected: concurrent modification",
+			slog.String("component", "product_repository"),
+			slog.String("product_name", name),
+			slog.Int("expected_stock", *expectedStock),
+			slog.Int("current_stock", oldStock),
+			slog.String("error_code", apierrors.ErrCodeConflict),
+			slog.String("operation", "update_stock"))
+
+		span.AddEvent("conflict.detected", trace.WithAttributes(
+			attribute.Int("stock.expected", *expectedStock),
+			attribute.Int("stock.current", oldStock),
+		))
+
+		appErr = apierrors.NewBusinessError(apierrors.ErrCodeConflict, errMsg, nil)
+
+		// Track error metrics
+		metric.IncrementErrorCount(ctx, apierrors.ErrCodeConflict, "update_stock", "repository")
+		return appErr
+	}
+
 	product.Stock = newStock
 	productsMap[name] = product
 
 	span.SetAttributes(attribute.Int("product.old_stock", oldStock))
+	span.SetAttributes(
+		attribute.Int("stock.before", oldStock),
+		attribute.Int("stock.after", newStock),
+	)
+	span.SetAttributes(commontrace.CauseAttributes(ctx)...)
 
 	stockDiff := newStock - oldStock
 	stockChangeType := "unchanged"
@@ -118,7 +145,7 @@ func (r *productRepository) UpdateStock(ctx context.Context, name string, newSto
 		slog.String("stock_change_type", stockChangeType),
 		slog.String("operation", "stock_update"))
 
-	if writeErr := r.database.Write(ctx, productsMap); writeErr != nil {
+	if writeErr := r.writeProducts(ctx, productsMap); writeErr != nil {
 		errMsg := "Failed to write updated product data"
 		r.logger.ErrorContext(ctx, "Database write error",
 			slog.String("component", "product_repository"),
@@ -127,20 +154,14 @@ func (r *productRepository) UpdateStock(ctx context.Context, name string, newSto
 			slog.String("product_name", name),
 			slog.String("operation", "update_stock"))
 
-		span.SetStatus(codes.Error, errMsg)
-
-		appErr = apierrors.NewApplicationError(
-			apierrors.ErrCodeDatabaseAccess,
-			errMsg,
-			writeErr)
+		appErr = dbError(writeErr, errMsg)
 
 		// Track error metrics
-		metric.IncrementErrorCount(ctx, apierrors.ErrCodeDatabaseAccess, "update_stock", "repository")
+		metric.IncrementErrorCount(ctx, appErr.Code, "update_stock", "repository")
 		return appErr
 	}
 
-	// Update product stock level for telemetry
-	metric.UpdateProductStockLevels(ctx, product.Name, product.Category, int64(newStock))
+	r.recordStockLevel(ctx, span, product, oldStock)
 
 	r.logger.InfoContext(ctx, "Product stock update completed",
 		slog.String("component", "product_repository"),
@@ -153,3 +174,29 @@ func (r *productRepository) UpdateStock(ctx context.Context, name string, newSto
 
 	return nil
 }
+
+// recordStockLevel reports a product's stock after a successful write: it updates the
+// stock gauge, reporting blank categories under the default, records the low-stock
+// threshold on span and warns when the stock has just fallen below it.
+func (r *productRepository) recordStockLevel(ctx context.Context, span trace.Span, product models.Product, oldStock int) {
+	category := product.Category
+	if strings.TrimSpace(category) == "" {
+		category = r.defaultCategory
+	}
+	metric.UpdateProductStockLevels(ctx, product.Name, category, int64(product.Stock))
+
+	threshold := metric.StockThresholdFor(category)
+	span.SetAttributes(
+		attribute.Int("stock.threshold", threshold),
+		attribute.Bool("stock.below_threshold", product.Stock < threshold),
+	)
+	if product.Stock < threshold && oldStock >= threshold {
+		r.logger.WarnContext(ctx, "Product stock fell below low-stock threshold",
+			slog.String("component", "product_repository"),
+			slog.String("product_name", product.Name),
+			slog.String("category", category),
+			slog.Int("new_stock", product.Stock),
+			slog.Int("threshold", threshold),
+			slog.String("event_type", "low_stock"))
+	}
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+const stockTestCatalog = `{
+  "Coffee Mug": {"name": "Coffee Mug", "description": "Ceramic mug", "price": 9.5, "stock": 20, "category": "Kitchenware"}
+}`
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestUpdateStockCompareAndSet(t *testing.T) {
+	tests := []struct {
+		name          string
+		newStock      int
+		expectedStock *int
+		wantCode      string
+		wantStock     int
+	}{
+		{name: "unconditional update", newStock: 15, expectedStock: nil, wantStock: 15},
+		{name: "expected stock matches", newStock: 18, expectedStock: intPtr(20), wantStock: 18},
+		{name: "expected stock is stale", newStock: 18, expectedStock: intPtr(25), wantCode: apierrors.ErrCodeConflict, wantStock: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t, stockTestCatalog)
+			ctx := context.Background()
+
+			appErr := repo.UpdateStock(ctx, "Coffee Mug", tt.newStock, tt.expectedStock)
+			if tt.wantCode == "" && appErr != nil {
+				t.Fatalf("UpdateStock returned error: %v", appErr)
+			}
+			if tt.wantCode != "" && (appErr == nil || appErr.Code != tt.wantCode) {
+				t.Fatalf("UpdateStock error = %v, want code %s", appErr, tt.wantCode)
+			}
+
+			product, getErr := repo.GetByName(ctx, "Coffee Mug")
+			if getErr != nil {
+				t.Fatalf("GetByName returned error: %v", getErr)
+			}
+			if product.Stock != tt.wantStock {
+				t.Errorf("stock = %d, want %d", product.Stock, tt.wantStock)
+			}
+		})
+	}
+}
+
+func TestUpdateStockConcurrentBuyersOnlyOneWins(t *testing.T) {
+	repo := newTestRepository(t, stockTestCatalog)
+	ctx := context.Background()
+
+	// Both buyers read a stock of 20; the second guarded write must not overwrite the first
+	if appErr := repo.UpdateStock(ctx, "Coffee Mug", 19, intPtr(20)); appErr != nil {
+		t.Fatalf("first update returned error: %v", appErr)
+	}
+	appErr := repo.UpdateStock(ctx, "Coffee Mug", 18, intPtr(20))
+	if appErr == nil || appErr.Code != apierrors.ErrCodeConflict {
+		t.Fatalf("second update error = %v, want %s", appErr, apierrors.ErrCodeConflict)
+	}
+
+	product, getErr := repo.GetByName(ctx, "Coffee Mug")
+	if getErr != nil {
+		t.Fatalf("GetByName returned error: %v", getErr)
+	}
+	if product.Stock != 19 {
+		t.Errorf("stock = %d, want 19", product.Stock)
+	}
+}
+
+func TestUpdateStockUnknownProduct(t *testing.T) {
+	repo := newTestRepository(t, stockTestCatalog)
+
+	appErr := repo.UpdateStock(context.Background(), "Teapot", 5, nil)
+	if appErr == nil || appErr.Code != apierrors.ErrCodeProductNotFound {
+		t.Errorf("UpdateStock error = %v, want %s", appErr, apierrors.ErrCodeProductNotFound)
+	}
+}
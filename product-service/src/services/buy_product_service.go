@@ -5,15 +5,20 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/narender/common/globals"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry"
 	"github.com/narender/common/telemetry/metric"
 	commontrace "github.com/narender/common/telemetry/trace"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	apierrors "github.com/narender/common/apierrors"
 )
 
 func (s *productService) BuyProduct(ctx context.Context, name string, quantity int) (revenue float64, appErr *apierrors.AppError) {
+	ctx = telemetry.WithOperation(ctx, "product_service", "buy_product")
+
 	newCtx, span := commontrace.StartSpan(ctx, "product_service", "buy_product",
 		attribute.String(metric.AttrProductName, name),
 		attribute.Int("product.purchase_quantity", quantity),
@@ -28,56 +33,140 @@ func (s *productService) BuyProduct(ctx context.Context, name string, quantity i
 	}()
 
 	s.logger.InfoContext(ctx, "Processing purchase request",
-		slog.String("component", "product_service"),
 		slog.String("product_name", name),
-		slog.Int("quantity", quantity),
-		slog.String("operation", "buy_product"))
+		slog.Int("quantity", quantity))
+	metric.IncrementBuyFunnel(ctx, metric.BuyRequestsMetric)
+
+	if maxQuantity := globals.Cfg().MAX_PURCHASE_QUANTITY; quantity <= 0 || quantity > maxQuantity {
+		errMsg := fmt.Sprintf("Purchase quantity %d is outside the allowed range 1-%d", quantity, maxQuantity)
+
+		s.logger.WarnContext(ctx, "Purchase rejected: invalid quantity",
+			slog.String("product_name", name),
+			slog.Int("quantity", quantity),
+			slog.Int("max_quantity", maxQuantity),
+			slog.String("error_code", apierrors.ErrCodeOrderLimitExceeded))
+
+		span.AddEvent("quantity.invalid", trace.WithAttributes(
+			attribute.Int("product.purchase_quantity", quantity),
+			attribute.Int("purchase.max_quantity", maxQuantity),
+		))
+
+		appErr = apierrors.NewBusinessError(apierrors.ErrCodeOrderLimitExceeded, errMsg, nil)
+		metric.IncrementErrorCount(ctx, apierrors.ErrCodeOrderLimitExceeded, "buy_product", "service")
+		return 0, appErr
+	}
+	metric.IncrementBuyFunnel(ctx, metric.BuyValidatedMetric)
+
+	// A concurrent purchase of the same product makes the guarded stock update fail
+	// with a conflict; re-read the product and try again a bounded number of times.
+	maxRetries := globals.Cfg().BUY_CONFLICT_RETRIES
+	var (
+		product  models.Product
+		newStock int
+	)
+	for attempt := 1; ; attempt++ {
+		product, newStock, appErr = s.reserveStock(ctx, span, name, quantity)
+		if appErr == nil || appErr.Code != apierrors.ErrCodeConflict || attempt > maxRetries {
+			span.SetAttributes(attribute.Int("purchase.attempts", attempt))
+			break
+		}
+
+		s.logger.InfoContext(ctx, "Stock changed during purchase, retrying",
+			slog.String("product_name", name),
+			slog.Int("attempt", attempt),
+			slog.Int("max_retries", maxRetries))
+		span.AddEvent("purchase.conflict_retry", trace.WithAttributes(attribute.Int("purchase.attempt", attempt)))
+	}
+	if appErr != nil {
+		return 0, appErr
+	}
+	// Counted once the stock is reserved, so retried attempts are not counted twice
+	metric.IncrementBuyFunnel(ctx, metric.BuyStockAvailableMetric)
+
+	// Calculate revenue in cents so aggregation is exact; convert only for display
+	revenueCents := product.Price.Cents() * int64(quantity)
+	revenue = models.FromCents(revenueCents)
+	span.SetAttributes(attribute.Float64("product.revenue", revenue))
+	span.SetAttributes(attribute.Int("product.remaining_stock", newStock))
+
+	// --- Metrics Reporting for Sale ---
+	metric.IncrementRevenueTotal(ctx, revenueCents, product.Name, product.Category)
+	metric.IncrementItemsSoldCount(ctx, int64(quantity), product.Name, product.Category)
+	metric.RecordProductSale(product.Name)
+	s.logger.InfoContext(ctx, "Sales metrics recorded",
+		slog.String("product_name", product.Name),
+		slog.Float64("revenue", revenue),
+		slog.Int("quantity_sold", quantity),
+		slog.String("operation", "metrics_recording"))
+	// --- End Metrics Reporting ---
+
+	metric.IncrementBuyFunnel(ctx, metric.BuyCompletedMetric)
+	s.logger.InfoContext(ctx, "Purchase completed successfully",
+		slog.String("product_name", name),
+		slog.Float64("revenue", revenue),
+		slog.Int("remaining_stock", newStock),
+		slog.String("status", "success"))
+
+	return revenue, appErr
+}
 
+// reserveStock reads the product and, unless its category has unlimited stock,
+// takes quantity out of its stock. It returns the product as read and the
+// remaining stock.
+func (s *productService) reserveStock(ctx context.Context, span trace.Span, name string, quantity int) (models.Product, int, *apierrors.AppError) {
 	s.logger.DebugContext(ctx, "Retrieving product stock information",
-		slog.String("component", "product_service"),
 		slog.String("product_name", name),
 		slog.String("operation", "product_lookup"))
 
 	product, repoGetErr := s.repo.GetByName(ctx, name)
 	if repoGetErr != nil {
 		s.logger.ErrorContext(ctx, "Failed to retrieve product information",
-			slog.String("component", "product_service"),
 			slog.String("product_name", name),
 			slog.String("error", repoGetErr.Error()),
-			slog.String("error_code", repoGetErr.Code),
-			slog.String("operation", "buy_product"))
-
-		if span != nil {
-			span.SetStatus(codes.Error, repoGetErr.Message)
-		}
+			slog.String("error_code", repoGetErr.Code))
 
 		// Track error metrics
 		metric.IncrementErrorCount(ctx, repoGetErr.Code, "buy_product", "service")
-		return 0, repoGetErr
+		return models.Product{}, 0, apierrors.WithOp(repoGetErr, "product_service.buy_product")
 	}
 
+	newStock := product.Stock
+	unlimited := unlimitedStockCategory(product.Category)
+	span.SetAttributes(attribute.Bool("stock.unlimited", unlimited))
+	if unlimited {
+		s.logger.DebugContext(ctx, "Stock check skipped: category has unlimited stock",
+			slog.String("product_name", product.Name),
+			slog.String("category", product.Category),
+			slog.String("operation", "stock_verification"))
+	} else {
+		var appErr *apierrors.AppError
+		newStock, appErr = s.decrementStock(ctx, product, quantity)
+		if appErr != nil {
+			return models.Product{}, 0, appErr
+		}
+		s.checkReorder(ctx, span, product, newStock)
+	}
+	return product, newStock, nil
+}
+
+// decrementStock checks that product has quantity in stock and takes it out,
+// returning the remaining stock.
+func (s *productService) decrementStock(ctx context.Context, product models.Product, quantity int) (int, *apierrors.AppError) {
 	s.logger.DebugContext(ctx, "Product stock verification",
-		slog.String("component", "product_service"),
 		slog.String("product_name", product.Name),
 		slog.Int("stock", product.Stock),
 		slog.String("operation", "stock_verification"))
 
 	if product.Stock < quantity {
-		errMsg := fmt.Sprintf("Insufficient stock for product '%s'. Available: %d, Requested: %d", name, product.Stock, quantity)
+		errMsg := fmt.Sprintf("Insufficient stock for product '%s'. Available: %d, Requested: %d", product.Name, product.Stock, quantity)
 
 		s.logger.WarnContext(ctx, "Purchase rejected: insufficient stock",
-			slog.String("component", "product_service"),
-			slog.String("product_name", name),
+			slog.String("product_name", product.Name),
 			slog.Int("available", product.Stock),
-			slog.String("error", apierrors.ErrCodeInsufficientStock),
-			slog.String("operation", "buy_product"))
-
-		if span != nil {
-			span.SetStatus(codes.Error, "Insufficient stock")
-		}
+			slog.String("error", apierrors.ErrCodeInsufficientStock))
 
 		// Create business error
-		appErr = apierrors.NewBusinessError(
+		appErr := apierrors.NewBusinessError(
 			apierrors.ErrCodeInsufficientStock,
 			errMsg,
 			nil,
@@ -89,68 +178,37 @@ func (s *productService) BuyProduct(ctx context.Context, name string, quantity i
 	}
 
 	s.logger.DebugContext(ctx, "Stock verification completed: sufficient stock available",
-		slog.String("component", "product_service"),
-		slog.String("product_name", name),
+		slog.String("product_name", product.Name),
 		slog.Int("available", product.Stock),
 		slog.Int("requested", quantity),
 		slog.String("operation", "stock_verification"))
 
 	newStock := product.Stock - quantity
 	s.logger.DebugContext(ctx, "Calculating inventory update",
-		slog.String("component", "product_service"),
 		slog.String("product_name", product.Name),
 		slog.Int("new_stock", newStock),
 		slog.String("operation", "inventory_calculation"))
 
 	s.logger.DebugContext(ctx, "Updating product inventory",
-		slog.String("component", "product_service"),
 		slog.String("product_name", product.Name),
 		slog.Int("new_stock", newStock),
 		slog.String("operation", "inventory_update"))
 
-	repoUpdateErr := s.repo.UpdateStock(ctx, name, newStock)
+	// Mark the stock update as a consequence of this purchase so the trace shows the causal link
+	updateCtx := commontrace.WithCause(ctx, "buy_product", attribute.Int("purchase.quantity", quantity))
+	// Guard on the stock we checked so a concurrent purchase cannot oversell
+	repoUpdateErr := s.repo.UpdateStock(updateCtx, product.Name, newStock, &product.Stock)
 	if repoUpdateErr != nil {
 		s.logger.ErrorContext(ctx, "Failed to update inventory during purchase",
-			slog.String("component", "product_service"),
-			slog.String("product_name", name),
+			slog.String("product_name", product.Name),
 			slog.String("error", repoUpdateErr.Error()),
-			slog.String("error_code", repoUpdateErr.Code),
-			slog.String("operation", "buy_product"))
+			slog.String("error_code", repoUpdateErr.Code))
 
-		if span != nil {
-			span.SetStatus(codes.Error, repoUpdateErr.Message)
-		}
-
-		// Remove RequestID handling
-		appErr = repoUpdateErr
+		appErr := apierrors.WithOp(repoUpdateErr, "product_service.buy_product")
 		// Track error metrics
 		metric.IncrementErrorCount(ctx, repoUpdateErr.Code, "buy_product", "service")
-		return 0, appErr // Return zero revenue if update fails
+		return 0, appErr
 	}
 
-	// Calculate revenue for the purchase
-	revenue = product.Price * float64(quantity)
-	span.SetAttributes(attribute.Float64("product.revenue", revenue))
-	span.SetAttributes(attribute.Int("product.remaining_stock", newStock))
-
-	// --- Metrics Reporting for Sale ---
-	metric.IncrementRevenueTotal(ctx, revenue, product.Name, product.Category)
-	metric.IncrementItemsSoldCount(ctx, int64(quantity), product.Name, product.Category)
-	s.logger.InfoContext(ctx, "Sales metrics recorded",
-		slog.String("component", "product_service"),
-		slog.String("product_name", product.Name),
-		slog.Float64("revenue", revenue),
-		slog.Int("quantity_sold", quantity),
-		slog.String("operation", "metrics_recording"))
-	// --- End Metrics Reporting ---
-
-	s.logger.InfoContext(ctx, "Purchase completed successfully",
-		slog.String("component", "product_service"),
-		slog.String("product_name", name),
-		slog.Float64("revenue", revenue),
-		slog.Int("remaining_stock", newStock),
-		slog.String("operation", "buy_product"),
-		slog.String("status", "success"))
-
-	return revenue, appErr
+	return newStock, nil
 }
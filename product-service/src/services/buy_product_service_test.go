@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apierrors "github.com/narender/common/apierrors"
+	"github.com/narender/common/config"
+	"github.com/narender/common/events"
+	"github.com/narender/common/globals"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry/metric"
+	"github.com/narender/product-service/src/repositories"
+)
+
+// racingRepository serves a single product and simulates buyers that win the race
+// for its stock: each of the first conflicts guarded updates finds the stock lowered
+// by one by a concurrent purchase and is rejected with a conflict.
+type racingRepository struct {
+	repositories.ProductRepository
+
+	product   models.Product
+	conflicts int
+	updates   int
+}
+
+func (r *racingRepository) GetByName(ctx context.Context, name string) (models.Product, *apierrors.AppError) {
+	if name != r.product.Name {
+		return models.Product{}, apierrors.NewBusinessError(apierrors.ErrCodeProductNotFound, "not found", nil)
+	}
+	return r.product, nil
+}
+
+func (r *racingRepository) UpdateStock(ctx context.Context, name string, newStock int, expectedStock *int) *apierrors.AppError {
+	r.updates++
+	if r.conflicts > 0 {
+		r.conflicts--
+		r.product.Stock--
+	}
+	if expectedStock != nil && *expectedStock != r.product.Stock {
+		return apierrors.NewBusinessError(apierrors.ErrCodeConflict, "stock changed", nil)
+	}
+	r.product.Stock = newStock
+	return nil
+}
+
+func newTestService(t *testing.T, repo repositories.ProductRepository, retries int, overrides ...func(*config.Config)) *productService {
+	t.Helper()
+	globals.InitForTest(t, append([]func(*config.Config){func(c *config.Config) {
+		c.BUY_CONFLICT_RETRIES = retries
+	}}, overrides...)...)
+	return &productService{repo: repo, logger: globals.Logger(), events: events.NewBus()}
+}
+
+func TestBuyProductRetriesOnConflict(t *testing.T) {
+	tests := []struct {
+		name        string
+		retries     int
+		conflicts   int
+		wantCode    string
+		wantUpdates int
+		wantStock   int
+	}{
+		{name: "no contention", retries: 3, conflicts: 0, wantUpdates: 1, wantStock: 8},
+		{name: "one lost race recovered", retries: 3, conflicts: 1, wantUpdates: 2, wantStock: 7},
+		{name: "recovered on last retry", retries: 3, conflicts: 3, wantUpdates: 4, wantStock: 5},
+		{name: "retries exhausted", retries: 3, conflicts: 4, wantCode: apierrors.ErrCodeConflict, wantUpdates: 4, wantStock: 6},
+		{name: "retries disabled", retries: 0, conflicts: 1, wantCode: apierrors.ErrCodeConflict, wantUpdates: 1, wantStock: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &racingRepository{
+				product:   models.Product{Name: "Coffee Mug", Price: models.MoneyFromFloat(9.5), Stock: 10, Category: "Kitchenware"},
+				conflicts: tt.conflicts,
+			}
+			svc := newTestService(t, repo, tt.retries)
+
+			revenue, appErr := svc.BuyProduct(context.Background(), "Coffee Mug", 2)
+
+			if tt.wantCode == "" {
+				if appErr != nil {
+					t.Fatalf("BuyProduct returned error: %v", appErr)
+				}
+				if revenue != 19 {
+					t.Errorf("revenue = %v, want 19", revenue)
+				}
+			} else if appErr == nil || appErr.Code != tt.wantCode {
+				t.Fatalf("BuyProduct error = %v, want code %s", appErr, tt.wantCode)
+			}
+			if repo.updates != tt.wantUpdates {
+				t.Errorf("stock updates = %d, want %d", repo.updates, tt.wantUpdates)
+			}
+			if repo.product.Stock != tt.wantStock {
+				t.Errorf("stock = %d, want %d", repo.product.Stock, tt.wantStock)
+			}
+		})
+	}
+}
+
+func TestBuyProductDoesNotRetryOtherErrors(t *testing.T) {
+	repo := &racingRepository{
+		product: models.Product{Name: "Coffee Mug", Price: models.MoneyFromFloat(9.5), Stock: 1, Category: "Kitchenware"},
+	}
+	svc := newTestService(t, repo, 3)
+
+	_, appErr := svc.BuyProduct(context.Background(), "Coffee Mug", 2)
+
+	if appErr == nil || appErr.Code != apierrors.ErrCodeInsufficientStock {
+		t.Fatalf("BuyProduct error = %v, want %s", appErr, apierrors.ErrCodeInsufficientStock)
+	}
+	if repo.updates != 0 {
+		t.Errorf("stock updates = %d, want none", repo.updates)
+	}
+}
+
+func TestBuyProductRejectsQuantitiesOutsideTheLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity int
+		wantCode string
+	}{
+		{name: "zero", quantity: 0, wantCode: apierrors.ErrCodeOrderLimitExceeded},
+		{name: "negative", quantity: -3, wantCode: apierrors.ErrCodeOrderLimitExceeded},
+		{name: "over the limit", quantity: 6, wantCode: apierrors.ErrCodeOrderLimitExceeded},
+		{name: "at the limit", quantity: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &racingRepository{
+				product: models.Product{Name: "Coffee Mug", Price: models.MoneyFromFloat(9.5), Stock: 10, Category: "Kitchenware"},
+			}
+			svc := newTestService(t, repo, 3, func(c *config.Config) { c.MAX_PURCHASE_QUANTITY = 5 })
+
+			_, appErr := svc.BuyProduct(context.Background(), "Coffee Mug", tt.quantity)
+
+			if tt.wantCode == "" {
+				if appErr != nil {
+					t.Fatalf("BuyProduct returned error: %v", appErr)
+				}
+				return
+			}
+			if appErr == nil || appErr.Code != tt.wantCode {
+				t.Fatalf("BuyProduct error = %v, want code %s", appErr, tt.wantCode)
+			}
+			if repo.updates != 0 || repo.product.Stock != 10 {
+				t.Errorf("stock updated %d times to %d, want it left at 10", repo.updates, repo.product.Stock)
+			}
+		})
+	}
+}
+
+func TestBuyProductSkipsStockForUnlimitedCategories(t *testing.T) {
+	tests := []struct {
+		name        string
+		category    string
+		unlimited   string
+		quantity    int
+		wantCode    string
+		wantUpdates int
+		wantStock   int
+	}{
+		{name: "unlimited category sells beyond stock", category: "Digital Goods", unlimited: "Digital Goods", quantity: 5, wantStock: 1},
+		{name: "category matched case-insensitively", category: "digital goods", unlimited: "Gift Cards, DIGITAL GOODS", quantity: 1, wantStock: 1},
+		{name: "other categories enforce stock", category: "Kitchenware", unlimited: "Digital Goods", quantity: 5, wantCode: apierrors.ErrCodeInsufficientStock, wantStock: 1},
+		{name: "other categories decrement stock", category: "Kitchenware", unlimited: "Digital Goods", quantity: 1, wantUpdates: 1, wantStock: 0},
+		{name: "blank category never unlimited", category: "", unlimited: "Digital Goods,", quantity: 5, wantCode: apierrors.ErrCodeInsufficientStock, wantStock: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &racingRepository{
+				product: models.Product{Name: "E-Book", Price: models.MoneyFromFloat(4), Stock: 1, Category: tt.category},
+			}
+			svc := newTestService(t, repo, 3, func(c *config.Config) { c.UNLIMITED_STOCK_CATEGORIES = tt.unlimited })
+
+			revenue, appErr := svc.BuyProduct(context.Background(), "E-Book", tt.quantity)
+
+			if tt.wantCode == "" {
+				if appErr != nil {
+					t.Fatalf("BuyProduct returned error: %v", appErr)
+				}
+				if want := 4 * float64(tt.quantity); revenue != want {
+					t.Errorf("revenue = %v, want %v", revenue, want)
+				}
+			} else if appErr == nil || appErr.Code != tt.wantCode {
+				t.Fatalf("BuyProduct error = %v, want code %s", appErr, tt.wantCode)
+			}
+			if repo.updates != tt.wantUpdates {
+				t.Errorf("stock updates = %d, want %d", repo.updates, tt.wantUpdates)
+			}
+			if repo.product.Stock != tt.wantStock {
+				t.Errorf("stock = %d, want %d", repo.product.Stock, tt.wantStock)
+			}
+		})
+	}
+}
+
+func TestBuyProductPublishesReorderNeeded(t *testing.T) {
+	metric.SetStockThresholds(10, nil)
+	t.Cleanup(func() { metric.SetStockThresholds(10, nil) })
+
+	tests := []struct {
+		name      string
+		stock     int
+		quantity  int
+		category  string
+		wantEvent *events.ReorderNeeded
+	}{
+		{name: "crossing the threshold", stock: 12, quantity: 3, category: "Kitchenware",
+			wantEvent: &events.ReorderNeeded{Product: "Coffee Mug", Category: "Kitchenware", Stock: 9, Threshold: 10}},
+		{name: "starting at the threshold", stock: 10, quantity: 1, category: "Kitchenware",
+			wantEvent: &events.ReorderNeeded{Product: "Coffee Mug", Category: "Kitchenware", Stock: 9, Threshold: 10}},
+		{name: "staying above the threshold", stock: 12, quantity: 2, category: "Kitchenware"},
+		{name: "already below the threshold", stock: 8, quantity: 1, category: "Kitchenware"},
+		{name: "unlimited category", stock: 12, quantity: 5, category: "Furniture"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &racingRepository{
+				product: models.Product{Name: "Coffee Mug", Price: models.MoneyFromFloat(9.5), Stock: tt.stock, Category: tt.category},
+			}
+			svc := newTestService(t, repo, 0, func(c *config.Config) { c.UNLIMITED_STOCK_CATEGORIES = "furniture" })
+			var published []events.ReorderNeeded
+			svc.events.Subscribe(events.ReorderNeededEvent, func(ctx context.Context, e events.Event) {
+				published = append(published, e.(events.ReorderNeeded))
+			})
+
+			if _, appErr := svc.BuyProduct(context.Background(), "Coffee Mug", tt.quantity); appErr != nil {
+				t.Fatalf("BuyProduct: %v", appErr)
+			}
+
+			if tt.wantEvent == nil {
+				if len(published) != 0 {
+					t.Errorf("published %+v, want no ReorderNeeded event", published)
+				}
+				return
+			}
+			if len(published) != 1 {
+				t.Fatalf("published %d ReorderNeeded events, want 1", len(published))
+			}
+			got := published[0]
+			if got.At.IsZero() {
+				t.Errorf("ReorderNeeded.At is not set")
+			}
+			got.At = time.Time{}
+			if got != *tt.wantEvent {
+				t.Errorf("published %+v, want %+v", got, *tt.wantEvent)
+			}
+		})
+	}
+}
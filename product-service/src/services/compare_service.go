@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+func (s *productService) Compare(ctx context.Context, names []string) (comparison models.ProductComparison, appErr *apierrors.AppError) {
+	ctx = telemetry.WithOperation(ctx, "product_service", "compare")
+
+	newCtx, span := commontrace.StartSpan(ctx, "product_service", "compare",
+		attribute.Int("compare.count", len(names)))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if appErr != nil {
+			telemetryErr = appErr
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		appErr = simAppErr
+		return models.ProductComparison{}, appErr
+	}
+
+	found, notFound, repoErr := s.repo.GetByNames(ctx, names)
+	if repoErr != nil {
+		s.logger.ErrorContext(ctx, "Repository layer encountered error during product comparison",
+			slog.Int("compare_count", len(names)),
+			slog.String("error", repoErr.Error()),
+			slog.String("error_code", repoErr.Code))
+
+		appErr = apierrors.WithOp(repoErr, "product_service.compare")
+		return models.ProductComparison{}, appErr
+	}
+
+	comparison = compareProducts(found, notFound)
+	span.SetAttributes(
+		attribute.Int("products.returned.count", len(comparison.Products)),
+		attribute.Int("products.not_found.count", len(comparison.NotFound)))
+
+	s.logger.InfoContext(ctx, "Service layer successfully processed product comparison",
+		slog.Int("found_count", len(comparison.Products)),
+		slog.Int("not_found_count", len(comparison.NotFound)),
+		slog.String("cheapest", comparison.Cheapest))
+
+	return comparison, nil
+}
+
+// compareProducts computes each product's price delta against the cheapest one and
+// its stock availability. The first of several equally cheap products is the baseline.
+func compareProducts(found []models.Product, notFound []string) models.ProductComparison {
+	comparison := models.ProductComparison{
+		Products: make([]models.ComparedProduct, 0, len(found)),
+		NotFound: notFound,
+	}
+	if len(found) == 0 {
+		return comparison
+	}
+
+	cheapest := found[0]
+	for _, p := range found[1:] {
+		if p.Price < cheapest.Price {
+			cheapest = p
+		}
+	}
+	comparison.Cheapest = cheapest.Name
+
+	for _, p := range found {
+		comparison.Products = append(comparison.Products, models.ComparedProduct{
+			Product:    p,
+			PriceDelta: p.Price - cheapest.Price,
+			InStock:    p.Stock > 0,
+		})
+	}
+	return comparison
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry"
+	"github.com/narender/common/telemetry/metric"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+func (s *productService) DeleteProduct(ctx context.Context, name string) (product models.Product, appErr *apierrors.AppError) {
+	ctx = telemetry.WithOperation(ctx, "product_service", "delete_product")
+
+	newCtx, span := commontrace.StartSpan(ctx, "product_service", "delete_product",
+		attribute.String(metric.AttrProductName, name))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if appErr != nil {
+			telemetryErr = appErr
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		appErr = simAppErr
+		metric.IncrementErrorCount(ctx, simAppErr.Code, "delete_product", "service")
+		return models.Product{}, appErr
+	}
+
+	product, repoErr := s.repo.SoftDelete(ctx, name)
+	if repoErr != nil {
+		s.logger.ErrorContext(ctx, "Failed to delete product",
+			slog.String("product_name", name),
+			slog.String("error", repoErr.Error()),
+			slog.String("error_code", repoErr.Code))
+
+		appErr = apierrors.WithOp(repoErr, "product_service.delete_product")
+		metric.IncrementErrorCount(ctx, repoErr.Code, "delete_product", "service")
+		return models.Product{}, appErr
+	}
+
+	s.logger.InfoContext(ctx, "Product deleted successfully",
+		slog.String("product_name", name))
+
+	return product, nil
+}
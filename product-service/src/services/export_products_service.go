@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry"
+	"github.com/narender/common/telemetry/metric"
+	commontrace "github.com/narender/common/telemetry/trace"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+// ExportProducts passes every product, soft-deleted ones included, to fn in data
+// file order without loading the whole catalog.
+func (s *productService) ExportProducts(ctx context.Context, fn func(models.Product) error) (appErr *apierrors.AppError) {
+	ctx = telemetry.WithOperation(ctx, "product_service", "export_products")
+
+	newCtx, span := commontrace.StartSpan(ctx, "product_service", "export_products")
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if appErr != nil {
+			telemetryErr = appErr
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		appErr = simAppErr
+		metric.IncrementErrorCount(ctx, simAppErr.Code, "export_products", "service")
+		return appErr
+	}
+
+	if repoErr := s.repo.Export(ctx, fn); repoErr != nil {
+		s.logger.ErrorContext(ctx, "Failed to export product catalog",
+			slog.String("error", repoErr.Error()),
+			slog.String("error_code", repoErr.Code))
+
+		appErr = apierrors.WithOp(repoErr, "product_service.export_products")
+		metric.IncrementErrorCount(ctx, repoErr.Code, "export_products", "service")
+		return appErr
+	}
+	return nil
+}
@@ -2,69 +2,63 @@ package services
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/narender/common/debugutils"
-	commontrace "github.com/narender/common/telemetry/trace"
-	"github.com/narender/product-service/src/models"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	apierrors "github.com/narender/common/apierrors"
 )
 
-func (s *productService) GetByCategory(ctx context.Context, category string) (products []models.Product, appErr *apierrors.AppError) {
+func (s *productService) GetByCategory(ctx context.Context, category string, sortOpts SortOptions, includeDeleted bool) ([]models.Product, *apierrors.AppError) {
+	ctx = telemetry.WithOperation(ctx, "product_service", "get_products_by_category")
+
 	s.logger.InfoContext(ctx, "Initializing service layer processing for category-based product filtering",
-		slog.String("category", category),
-		slog.String("component", "product_service"),
-		slog.String("operation", "get_products_by_category"))
+		slog.String("category", category))
+
+	products, err := telemetry.Instrument(ctx, "product_service.get_by_category", func(ctx context.Context) ([]models.Product, error) {
+		span := trace.SpanFromContext(ctx)
+		span.SetAttributes(attribute.String("product.category", category))
 
-	newCtx, span := commontrace.StartSpan(ctx, "product_service", "get_by_category",
-		attribute.String("product.category", category))
-	ctx = newCtx // Update ctx
-	defer func() {
-		var telemetryErr error
-		if appErr != nil {
-			telemetryErr = appErr
+		if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+			return nil, simAppErr
 		}
-		commontrace.EndSpan(span, &telemetryErr, nil)
-	}()
 
-	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
-		appErr = simAppErr
-		return nil, appErr
-	}
+		s.logger.DebugContext(ctx, "Delegating category-based product query to repository layer",
+			slog.String("category", category),
+			slog.String("operation", "repository_fetch_by_category"))
 
-	s.logger.DebugContext(ctx, "Delegating category-based product query to repository layer",
-		slog.String("category", category),
-		slog.String("component", "product_service"),
-		slog.String("operation", "repository_fetch_by_category"))
+		products, repoErr := s.repo.GetByCategory(ctx, category, includeDeleted)
+		if repoErr != nil {
+			s.logger.ErrorContext(ctx, "Repository layer encountered error during category-based product retrieval",
+				slog.String("category", category),
+				slog.String("error", repoErr.Error()),
+				slog.String("error_code", repoErr.Code))
+			return nil, apierrors.WithOp(repoErr, "product_service.get_by_category")
+		}
 
-	products, repoErr := s.repo.GetByCategory(ctx, category)
-	if repoErr != nil {
-		s.logger.ErrorContext(ctx, "Repository layer encountered error during category-based product retrieval",
+		sortProducts(ctx, products, sortOpts)
+
+		productCount := len(products)
+		span.SetAttributes(attribute.Int("products.returned.count", productCount))
+
+		s.logger.InfoContext(ctx, "Service layer successfully processed category-based product retrieval",
 			slog.String("category", category),
-			slog.String("error", repoErr.Error()),
-			slog.String("error_code", repoErr.Code),
-			slog.String("component", "product_service"),
-			slog.String("operation", "get_products_by_category"))
+			slog.Int("product_count", productCount))
 
-		if span != nil {
-			span.SetStatus(codes.Error, repoErr.Message)
+		return products, nil
+	})
+	if err != nil {
+		var appErr *apierrors.AppError
+		if !errors.As(err, &appErr) {
+			appErr = apierrors.NewApplicationError(apierrors.ErrCodeUnknown, "Unexpected error during category lookup", err)
 		}
-
-		appErr = repoErr
 		return nil, appErr
 	}
 
-	productCount := len(products)
-	span.SetAttributes(attribute.Int("products.returned.count", productCount))
-
-	s.logger.InfoContext(ctx, "Service layer successfully processed category-based product retrieval",
-		slog.String("category", category),
-		slog.Int("product_count", productCount),
-		slog.String("component", "product_service"),
-		slog.String("operation", "get_products_by_category"))
-
-	return products, appErr
+	return products, nil
 }
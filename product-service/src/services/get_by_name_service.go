@@ -5,15 +5,17 @@ import (
 	"log/slog"
 
 	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry"
 	commontrace "github.com/narender/common/telemetry/trace"
-	"github.com/narender/product-service/src/models"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 
 	apierrors "github.com/narender/common/apierrors"
 )
 
 func (s *productService) GetByName(ctx context.Context, name string) (product models.Product, appErr *apierrors.AppError) {
+	ctx = telemetry.WithOperation(ctx, "product_service", "get_by_name")
+
 	productNameAttr := attribute.String("product.name", name)
 
 	newCtx, span := commontrace.StartSpan(ctx, "product_service", "get_by_name", productNameAttr)
@@ -32,9 +34,7 @@ func (s *productService) GetByName(ctx context.Context, name string) (product mo
 	}
 
 	s.logger.InfoContext(ctx, "Processing product details request",
-		slog.String("component", "product_service"),
-		slog.String("product_name", name),
-		slog.String("operation", "get_by_name"))
+		slog.String("product_name", name))
 
 	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
 		appErr = simAppErr
@@ -42,24 +42,17 @@ func (s *productService) GetByName(ctx context.Context, name string) (product mo
 	}
 
 	s.logger.DebugContext(ctx, "Retrieving product from repository",
-		slog.String("component", "product_service"),
 		slog.String("product_name", name),
 		slog.String("operation", "repository_lookup"))
 
 	product, repoErr := s.repo.GetByName(ctx, name)
 	if repoErr != nil {
 		s.logger.ErrorContext(ctx, "Failed to retrieve product details",
-			slog.String("component", "product_service"),
 			slog.String("product_name", name),
 			slog.String("error", repoErr.Error()),
-			slog.String("error_code", repoErr.Code),
-			slog.String("operation", "get_by_name"))
-
-		if span != nil {
-			span.SetStatus(codes.Error, repoErr.Message)
-		}
+			slog.String("error_code", repoErr.Code))
 
-		appErr = repoErr
+		appErr = apierrors.WithOp(repoErr, "product_service.get_by_name")
 		return models.Product{}, appErr
 	}
 
@@ -69,9 +62,7 @@ func (s *productService) GetByName(ctx context.Context, name string) (product mo
 	}
 
 	s.logger.InfoContext(ctx, "Product details retrieved successfully",
-		slog.String("component", "product_service"),
-		slog.String("product_name", product.Name),
-		slog.String("operation", "get_by_name"))
+		slog.String("product_name", product.Name))
 
 	return product, appErr
 }
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/telemetry"
+	"github.com/narender/common/telemetry/metric"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+func (s *productService) PurgeProduct(ctx context.Context, name string) (appErr *apierrors.AppError) {
+	ctx = telemetry.WithOperation(ctx, "product_service", "purge_product")
+
+	newCtx, span := commontrace.StartSpan(ctx, "product_service", "purge_product",
+		attribute.String(metric.AttrProductName, name))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if appErr != nil {
+			telemetryErr = appErr
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		appErr = simAppErr
+		metric.IncrementErrorCount(ctx, simAppErr.Code, "purge_product", "service")
+		return appErr
+	}
+
+	if repoErr := s.repo.Purge(ctx, name); repoErr != nil {
+		s.logger.ErrorContext(ctx, "Failed to purge product",
+			slog.String("product_name", name),
+			slog.String("error", repoErr.Error()),
+			slog.String("error_code", repoErr.Code))
+
+		appErr = apierrors.WithOp(repoErr, "product_service.purge_product")
+		metric.IncrementErrorCount(ctx, repoErr.Code, "purge_product", "service")
+		return appErr
+	}
+
+	s.logger.InfoContext(ctx, "Product purged successfully",
+		slog.String("product_name", name))
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/narender/common/events"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry/metric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// checkReorder publishes ReorderNeeded when a purchase took product's stock from at
+// or above its category threshold to below it, and records the outcome on span.
+// Purchases that start below the threshold do not publish again.
+func (s *productService) checkReorder(ctx context.Context, span trace.Span, product models.Product, newStock int) {
+	threshold := metric.StockThresholdFor(product.Category)
+	triggered := product.Stock >= threshold && newStock < threshold
+	span.SetAttributes(attribute.Bool("reorder.triggered", triggered))
+	if !triggered {
+		return
+	}
+
+	s.logger.InfoContext(ctx, "Purchase took stock below threshold, reorder needed",
+		slog.String("product_name", product.Name),
+		slog.String("category", product.Category),
+		slog.Int("remaining_stock", newStock),
+		slog.Int("threshold", threshold))
+
+	s.events.Publish(ctx, events.ReorderNeeded{
+		Product:   product.Name,
+		Category:  product.Category,
+		Stock:     newStock,
+		Threshold: threshold,
+		At:        time.Now().UTC(),
+	})
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+func (s *productService) Search(ctx context.Context, query string, inDescription, includeDeleted bool) (results []models.SearchResult, appErr *apierrors.AppError) {
+	ctx = telemetry.WithOperation(ctx, "product_service", "search")
+
+	newCtx, span := commontrace.StartSpan(ctx, "product_service", "search",
+		attribute.String("search.query", query),
+		attribute.Bool("search.in_description", inDescription))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if appErr != nil {
+			telemetryErr = appErr
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		appErr = simAppErr
+		return nil, appErr
+	}
+
+	results, repoErr := s.repo.Search(ctx, query, inDescription, includeDeleted)
+	if repoErr != nil {
+		s.logger.ErrorContext(ctx, "Repository layer encountered error during product search",
+			slog.String("query", query),
+			slog.String("error", repoErr.Error()),
+			slog.String("error_code", repoErr.Code))
+
+		appErr = apierrors.WithOp(repoErr, "product_service.search")
+		return nil, appErr
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Product.Name < results[j].Product.Name
+	})
+	span.SetAttributes(
+		attribute.Int("products.returned.count", len(results)),
+		attribute.String("sort.field", SortByName),
+		attribute.String("sort.order", SortOrderAsc))
+
+	s.logger.InfoContext(ctx, "Service layer successfully processed product search",
+		slog.String("query", query),
+		slog.Int("result_count", len(results)))
+
+	return results, appErr
+}
@@ -6,27 +6,36 @@ import (
 	"context"
 
 	apierrors "github.com/narender/common/apierrors"
+	"github.com/narender/common/events"
 	"github.com/narender/common/globals"
-	"github.com/narender/product-service/src/models"
+	"github.com/narender/common/models"
 	"github.com/narender/product-service/src/repositories"
 )
 
 type ProductService interface {
-	GetAll(ctx context.Context) ([]models.Product, *apierrors.AppError)
+	GetAll(ctx context.Context, sortOpts SortOptions, includeDeleted bool) ([]models.Product, *apierrors.AppError)
 	GetByName(ctx context.Context, name string) (models.Product, *apierrors.AppError)
-	UpdateStock(ctx context.Context, name string, newStock int) *apierrors.AppError
-	GetByCategory(ctx context.Context, category string) ([]models.Product, *apierrors.AppError)
+	UpdateStock(ctx context.Context, name string, newStock int, expectedStock *int) *apierrors.AppError
+	GetByCategory(ctx context.Context, category string, sortOpts SortOptions, includeDeleted bool) ([]models.Product, *apierrors.AppError)
 	BuyProduct(ctx context.Context, name string, quantity int) (revenue float64, appErr *apierrors.AppError)
+	Search(ctx context.Context, query string, inDescription, includeDeleted bool) ([]models.SearchResult, *apierrors.AppError)
+	UpdateProduct(ctx context.Context, name string, update models.ProductUpdate) (models.Product, *apierrors.AppError)
+	Compare(ctx context.Context, names []string) (models.ProductComparison, *apierrors.AppError)
+	DeleteProduct(ctx context.Context, name string) (models.Product, *apierrors.AppError)
+	PurgeProduct(ctx context.Context, name string) *apierrors.AppError
+	ExportProducts(ctx context.Context, fn func(models.Product) error) *apierrors.AppError
 }
 
 type productService struct {
 	repo   repositories.ProductRepository
 	logger *slog.Logger
+	events *events.Bus
 }
 
 func NewProductService(repo repositories.ProductRepository) ProductService {
 	return &productService{
 		repo:   repo,
 		logger: globals.Logger(),
+		events: events.Default,
 	}
 }
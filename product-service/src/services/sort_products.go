@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/narender/common/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+const (
+	SortByName  = "name"
+	SortByPrice = "price"
+	SortByStock = "stock"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// SortOptions controls the ordering of product lists. The zero value sorts by name ascending.
+type SortOptions struct {
+	Field string
+	Order string
+}
+
+// ParseSortOptions validates the sortBy and order query parameters.
+// Empty values fall back to name ascending.
+func ParseSortOptions(sortBy, order string) (SortOptions, *apierrors.AppError) {
+	opts := SortOptions{Field: strings.ToLower(sortBy), Order: strings.ToLower(order)}
+	if opts.Field == "" {
+		opts.Field = SortByName
+	}
+	if opts.Order == "" {
+		opts.Order = SortOrderAsc
+	}
+
+	switch opts.Field {
+	case SortByName, SortByPrice, SortByStock:
+	default:
+		return opts, apierrors.NewApplicationError(
+			apierrors.ErrCodeRequestValidation,
+			"Invalid 'sortBy' query parameter; expected one of name, price, stock",
+			nil)
+	}
+	if opts.Order != SortOrderAsc && opts.Order != SortOrderDesc {
+		return opts, apierrors.NewApplicationError(
+			apierrors.ErrCodeRequestValidation,
+			"Invalid 'order' query parameter; expected asc or desc",
+			nil)
+	}
+	return opts, nil
+}
+
+// sortProducts orders products in place and records the ordering on the current span.
+// Ties are broken by name so the result is deterministic regardless of map iteration order.
+func sortProducts(ctx context.Context, products []models.Product, opts SortOptions) {
+	if opts.Field == "" {
+		opts.Field = SortByName
+	}
+	if opts.Order == "" {
+		opts.Order = SortOrderAsc
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("sort.field", opts.Field),
+		attribute.String("sort.order", opts.Order))
+
+	less := func(a, b models.Product) bool {
+		switch opts.Field {
+		case SortByPrice:
+			if a.Price != b.Price {
+				return a.Price < b.Price
+			}
+		case SortByStock:
+			if a.Stock != b.Stock {
+				return a.Stock < b.Stock
+			}
+		}
+		return a.Name < b.Name
+	}
+
+	sort.SliceStable(products, func(i, j int) bool {
+		if opts.Order == SortOrderDesc {
+			return less(products[j], products[i])
+		}
+		return less(products[i], products[j])
+	})
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/narender/common/models"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+func TestParseSortOptions(t *testing.T) {
+	tests := []struct {
+		sortBy   string
+		order    string
+		want     SortOptions
+		wantCode string
+	}{
+		{want: SortOptions{Field: SortByName, Order: SortOrderAsc}},
+		{sortBy: "price", want: SortOptions{Field: SortByPrice, Order: SortOrderAsc}},
+		{sortBy: "Stock", order: "DESC", want: SortOptions{Field: SortByStock, Order: SortOrderDesc}},
+		{sortBy: "rating", wantCode: apierrors.ErrCodeRequestValidation},
+		{sortBy: "name", order: "sideways", wantCode: apierrors.ErrCodeRequestValidation},
+	}
+	for _, tt := range tests {
+		got, appErr := ParseSortOptions(tt.sortBy, tt.order)
+		if tt.wantCode != "" {
+			if appErr == nil || appErr.Code != tt.wantCode {
+				t.Errorf("ParseSortOptions(%q, %q) error = %v, want code %s", tt.sortBy, tt.order, appErr, tt.wantCode)
+			}
+			continue
+		}
+		if appErr != nil || got != tt.want {
+			t.Errorf("ParseSortOptions(%q, %q) = %+v, %v, want %+v", tt.sortBy, tt.order, got, appErr, tt.want)
+		}
+	}
+}
+
+func TestSortProducts(t *testing.T) {
+	// Price and stock ties between Desk Lamp and Mug are broken by name
+	catalog := []models.Product{
+		{Name: "Teapot", Price: models.MoneyFromFloat(24), Stock: 3},
+		{Name: "Mug", Price: models.MoneyFromFloat(9.5), Stock: 12},
+		{Name: "Blender", Price: models.MoneyFromFloat(74.99), Stock: 30},
+		{Name: "Desk Lamp", Price: models.MoneyFromFloat(9.5), Stock: 12},
+	}
+
+	tests := []struct {
+		opts SortOptions
+		want string
+	}{
+		{opts: SortOptions{}, want: "Blender,Desk Lamp,Mug,Teapot"},
+		{opts: SortOptions{Field: SortByName, Order: SortOrderDesc}, want: "Teapot,Mug,Desk Lamp,Blender"},
+		{opts: SortOptions{Field: SortByPrice, Order: SortOrderAsc}, want: "Desk Lamp,Mug,Teapot,Blender"},
+		{opts: SortOptions{Field: SortByPrice, Order: SortOrderDesc}, want: "Blender,Teapot,Mug,Desk Lamp"},
+		{opts: SortOptions{Field: SortByStock, Order: SortOrderAsc}, want: "Teapot,Desk Lamp,Mug,Blender"},
+		{opts: SortOptions{Field: SortByStock, Order: SortOrderDesc}, want: "Blender,Mug,Desk Lamp,Teapot"},
+	}
+	for _, tt := range tests {
+		// Every starting order, as map iteration would give, must sort the same way
+		for i := 0; i < 10; i++ {
+			products := append([]models.Product(nil), catalog...)
+			rand.Shuffle(len(products), func(a, b int) { products[a], products[b] = products[b], products[a] })
+
+			sortProducts(context.Background(), products, tt.opts)
+
+			names := make([]string, len(products))
+			for j, p := range products {
+				names[j] = p.Name
+			}
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Fatalf("sortProducts(%+v) = %s, want %s", tt.opts, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/narender/common/globals"
+)
+
+// unlimitedStockCategory reports whether category is listed in
+// UNLIMITED_STOCK_CATEGORIES. Such products (e.g. digital goods) are sold without
+// checking or decrementing stock.
+func unlimitedStockCategory(category string) bool {
+	category = strings.TrimSpace(category)
+	if category == "" {
+		return false
+	}
+	for _, name := range strings.Split(globals.Cfg().UNLIMITED_STOCK_CATEGORIES, ",") {
+		if strings.EqualFold(strings.TrimSpace(name), category) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/narender/common/debugutils"
+	"github.com/narender/common/models"
+	"github.com/narender/common/telemetry"
+	"github.com/narender/common/telemetry/metric"
+	commontrace "github.com/narender/common/telemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	apierrors "github.com/narender/common/apierrors"
+)
+
+func (s *productService) UpdateProduct(ctx context.Context, name string, update models.ProductUpdate) (product models.Product, appErr *apierrors.AppError) {
+	ctx = telemetry.WithOperation(ctx, "product_service", "update_product")
+
+	newCtx, span := commontrace.StartSpan(ctx, "product_service", "update_product",
+		attribute.String(metric.AttrProductName, name))
+	ctx = newCtx
+	defer func() {
+		var telemetryErr error
+		if appErr != nil {
+			telemetryErr = appErr
+		}
+		commontrace.EndSpan(span, &telemetryErr, nil)
+	}()
+
+	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
+		appErr = simAppErr
+		metric.IncrementErrorCount(ctx, simAppErr.Code, "update_product", "service")
+		return models.Product{}, appErr
+	}
+
+	product, changed, repoErr := s.repo.UpdateProduct(ctx, name, update)
+	if repoErr != nil {
+		s.logger.ErrorContext(ctx, "Failed to update product",
+			slog.String("product_name", name),
+			slog.String("error", repoErr.Error()),
+			slog.String("error_code", repoErr.Code))
+
+		appErr = apierrors.WithOp(repoErr, "product_service.update_product")
+		metric.IncrementErrorCount(ctx, repoErr.Code, "update_product", "service")
+		return models.Product{}, appErr
+	}
+
+	span.SetAttributes(attribute.StringSlice("product.fields_changed", changed))
+
+	s.logger.InfoContext(ctx, "Product updated successfully",
+		slog.String("product_name", name),
+		slog.Any("fields_changed", changed))
+
+	return product, appErr
+}
@@ -5,15 +5,17 @@ import (
 	"log/slog"
 
 	"github.com/narender/common/debugutils"
+	"github.com/narender/common/telemetry"
 	"github.com/narender/common/telemetry/metric"
 	commontrace "github.com/narender/common/telemetry/trace"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 
 	apierrors "github.com/narender/common/apierrors"
 )
 
-func (s *productService) UpdateStock(ctx context.Context, name string, newStock int) (appErr *apierrors.AppError) {
+func (s *productService) UpdateStock(ctx context.Context, name string, newStock int, expectedStock *int) (appErr *apierrors.AppError) {
+	ctx = telemetry.WithOperation(ctx, "product_service", "update_stock")
+
 	productNameAttr := attribute.String(metric.AttrProductName, name)
 	newStockAttr := attribute.Int("product.new_stock", newStock)
 
@@ -35,10 +37,8 @@ func (s *productService) UpdateStock(ctx context.Context, name string, newStock
 	}
 
 	s.logger.InfoContext(ctx, "Processing stock update request",
-		slog.String("component", "product_service"),
 		slog.String("product_name", name),
-		slog.Int("new_stock", newStock),
-		slog.String("operation", "update_stock"))
+		slog.Int("new_stock", newStock))
 
 	if simAppErr := debugutils.Simulate(ctx); simAppErr != nil {
 		appErr = simAppErr
@@ -48,25 +48,18 @@ func (s *productService) UpdateStock(ctx context.Context, name string, newStock
 	}
 
 	s.logger.DebugContext(ctx, "Updating product stock in repository",
-		slog.String("component", "product_service"),
 		slog.String("product_name", name),
 		slog.Int("new_stock", newStock),
 		slog.String("operation", "repository_update_stock"))
 
-	repoErr := s.repo.UpdateStock(ctx, name, newStock)
+	repoErr := s.repo.UpdateStock(ctx, name, newStock, expectedStock)
 	if repoErr != nil {
 		s.logger.ErrorContext(ctx, "Failed to update product stock",
-			slog.String("component", "product_service"),
 			slog.String("product_name", name),
 			slog.String("error", repoErr.Error()),
-			slog.String("error_code", repoErr.Code),
-			slog.String("operation", "update_stock"))
+			slog.String("error_code", repoErr.Code))
 
-		if span != nil {
-			span.SetStatus(codes.Error, repoErr.Message)
-		}
-
-		appErr = repoErr
+		appErr = apierrors.WithOp(repoErr, "product_service.update_stock")
 		// Track error metrics
 		metric.IncrementErrorCount(ctx, repoErr.Code, "update_stock", "service")
 		return appErr
@@ -80,10 +73,8 @@ func (s *productService) UpdateStock(ctx context.Context, name string, newStock
 	}
 
 	s.logger.InfoContext(ctx, "Product stock updated successfully",
-		slog.String("component", "product_service"),
 		slog.String("product_name", name),
-		slog.Int("new_stock", newStock),
-		slog.String("operation", "update_stock"))
+		slog.Int("new_stock", newStock))
 
 	return appErr
 }